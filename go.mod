@@ -1,13 +1,13 @@
 module kube-route-info
 
-go 1.13
+go 1.16
 
 require (
-	github.com/spf13/cobra v0.0.5
-	github.com/spf13/pflag v1.0.5
+	github.com/spf13/cobra v1.1.1
 	github.com/xlab/treeprint v1.0.0
-	k8s.io/api v0.18.6
-	k8s.io/apimachinery v0.18.6
-	k8s.io/cli-runtime v0.18.6
-	k8s.io/client-go v0.18.6
+	k8s.io/api v0.21.14
+	k8s.io/apimachinery v0.21.14
+	k8s.io/cli-runtime v0.21.14
+	k8s.io/client-go v0.21.14
+	sigs.k8s.io/yaml v1.2.0
 )
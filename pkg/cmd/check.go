@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"kube-route-info/pkg/route"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// CheckerInterface defines the methods that must be implemented
+// by the resources whose route chain can be checked
+type CheckerInterface interface {
+	Check(string) ([]route.Finding, error)
+}
+
+// Check returns the findings of the ingress route chain
+func (i *Ingress) Check(name string) ([]route.Finding, error) {
+	return i.Resolver.CheckIngress(name)
+}
+
+// Check returns the findings of the service route chain
+func (s *Service) Check(name string) ([]route.Finding, error) {
+	return s.Resolver.CheckService(name)
+}
+
+// PrintFindings prints route chain findings in table format,
+// or in a machine-readable format when an output is given
+func PrintFindings(findings []route.Finding, output string, w io.Writer) error {
+
+	if output != "" {
+		document := route.NewDocument()
+		document.Findings = findings
+
+		return PrintDocument(document, output, w)
+	}
+
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No issues found in the route chain")
+		return nil
+	}
+
+	rows := []metav1.TableRow{}
+
+	for _, finding := range findings {
+		rows = append(rows, metav1.TableRow{
+			Cells: []interface{}{
+				finding.Severity,
+				finding.Type,
+				finding.Object,
+				finding.Message,
+			},
+		})
+	}
+
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Severity", Type: "string"},
+			{Name: "Type", Type: "string"},
+			{Name: "Object", Type: "string"},
+			{Name: "Message", Type: "string"},
+		},
+		Rows: rows,
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	printer.PrintObj(table, out)
+
+	fmt.Fprint(w, out.String())
+
+	return nil
+}
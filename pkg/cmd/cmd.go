@@ -1,12 +1,19 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"kube-route-info/pkg/route"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // combined authprovider import
 )
@@ -17,17 +24,88 @@ var cmdExample = `
 
 	# View the route information of the ingress my-ingress in namespace my-namespace
 	%[1]s route-info ingress my-ingress --namespace my-namespace
+
+	# View the services and ingresses that route traffic to the pod my-pod
+	%[1]s route-info pod my-pod
+
+	# View the route information of the Gateway API HTTPRoute my-route and of the gateway my-gateway
+	%[1]s route-info httproute my-route
+	%[1]s route-info gateway my-gateway
+
+	# View the route information of the Istio VirtualService my-virtualservice
+	%[1]s route-info virtualservice my-virtualservice
+
+	# View the route information of the OpenShift route my-app
+	%[1]s route-info route my-app
+
+	# View the route information of the Traefik IngressRoute my-ingressroute and of the Contour HTTPProxy my-proxy
+	%[1]s route-info ingressroute my-ingressroute
+	%[1]s route-info httpproxy my-proxy
+
+	# View the ingress route that serves the URL https://shop.example.com/api/cart
+	%[1]s route-info url https://shop.example.com/api/cart
+
+	# Check the route chain of the ingress my-ingress for broken links
+	%[1]s route-info ingress my-ingress --check
+
+	# View the route information of the ingress my-ingress with the IP, node, status, restarts and age of each pod
+	%[1]s route-info ingress my-ingress -o wide
+
+	# View the route information of the ingress my-ingress in JSON format
+	%[1]s route-info ingress my-ingress -o json
+
+	# Render the route graph of the ingress my-ingress as a Graphviz or Mermaid diagram
+	%[1]s route-info ingress my-ingress -o dot | dot -Tsvg > my-ingress.svg
+	%[1]s route-info ingress my-ingress -o mermaid
+
+	# View the route information of every ingress in the current namespace
+	%[1]s route-info ingress
+
+	# View the route information of the services labeled app=my-app in all namespaces
+	%[1]s route-info service -l app=my-app --all-namespaces
+
+	# Check the route chain of the ingress my-ingress in rendered manifests, without cluster access
+	helm template my-chart | %[1]s route-info ingress my-ingress --check -f -
+	%[1]s route-info ingress my-ingress -f manifests/ -R
+
+	# Watch the route information of the service my-service while its deployment rolls out
+	%[1]s route-info service my-service --watch
+
+	# Save a snapshot of the route graph of the current namespace, and compare it with a later one or with the cluster
+	%[1]s route-info snapshot > before.json
+	%[1]s route-info diff before.json after.json
+	%[1]s route-info diff before.json -o json
+
+	# Find the hosts and paths claimed by several ingresses across all namespaces
+	%[1]s route-info conflicts --all-namespaces
+
+	# View the route information of the ingresses my-ingress and my-other-ingress as tree graphs
+	%[1]s route-info ingress my-ingress my-other-ingress --graph
 `
 
+// resourceTypes lists the supported resource types
+var resourceTypes = []string{"ingress", "service", "pod", "url", "httproute", "gateway", "virtualservice", "route", "ingressroute", "httpproxy", "snapshot", "diff", "conflicts"}
+
 // Resource provides the information required to get
 // the route configuration from ingress and service objects
 type Resource struct {
 	configFlags *genericclioptions.ConfigFlags
 	genericclioptions.IOStreams
 	resourceInterface ResourceInterface
+	snapshot          *Snapshot
+	conflicts         *Conflicts
+	watcher           *Watcher
 	printGraph        bool
+	output            string
+	showCandidates    bool
+	check             bool
+	watch             bool
+	allNamespaces     bool
+	selector          string
+	filenames         []string
+	recursive         bool
 	resourceType      string
-	resourceName      string
+	resourceNames     []string
 }
 
 // ResourceInterface defines the methods the must be
@@ -35,6 +113,15 @@ type Resource struct {
 type ResourceInterface interface {
 	PrintTable(string, io.Writer) error
 	PrintGraph(string, io.Writer) error
+	PrintDocument(string, string, io.Writer) error
+}
+
+// ListerInterface defines the methods implemented by the resource
+// types that can print the route information of several objects
+type ListerInterface interface {
+	PrintTableList([]string, string, io.Writer) error
+	PrintGraphList([]string, string, io.Writer) error
+	PrintDocumentList([]string, string, string, io.Writer) error
 }
 
 // NewResource creates a new Resource struct with the required information
@@ -51,8 +138,8 @@ func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
 	r := NewResource(streams)
 
 	cmd := &cobra.Command{
-		Use:          "route-info [TYPE] [NAME] [flags]",
-		Short:        "View route information from ingresses or services to pods",
+		Use:          "route-info [TYPE] [NAME...] [flags]",
+		Short:        "View route information from ingresses, Gateway API routes or services to pods, or from pods back to them",
 		Example:      fmt.Sprintf(cmdExample, "kubectl"),
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
@@ -71,6 +158,14 @@ func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&r.printGraph, "graph", r.printGraph, "if true, print the route information in a tree graph format")
+	cmd.Flags().BoolVar(&r.showCandidates, "candidates", r.showCandidates, "if true, also print the ingress paths that match a url but are not selected")
+	cmd.Flags().BoolVar(&r.check, "check", r.check, "if true, check the route chain for broken links and exit with a non-zero code when errors are found")
+	cmd.Flags().BoolVarP(&r.watch, "watch", "w", r.watch, "if true, print the route information again whenever the objects it is resolved from change")
+	cmd.Flags().StringVarP(&r.output, "output", "o", r.output, "output format. One of: json|yaml|wide|dot|mermaid")
+	cmd.Flags().BoolVarP(&r.allNamespaces, "all-namespaces", "A", r.allNamespaces, "if true, list the requested objects across all namespaces")
+	cmd.Flags().StringVarP(&r.selector, "selector", "l", r.selector, "selector (label query) to filter on, supports '=', '==', and '!='")
+	cmd.Flags().StringSliceVarP(&r.filenames, "filename", "f", r.filenames, "read the objects from manifest files, directories or URLs instead of a cluster, or from the standard input with -")
+	cmd.Flags().BoolVarP(&r.recursive, "recursive", "R", r.recursive, "if true, read the manifests of the --filename directories recursively")
 	r.configFlags.AddFlags(cmd.Flags())
 
 	return cmd
@@ -78,46 +173,142 @@ func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
 
 // Validate ensures that all required arguments and flags values are provided
 func (r *Resource) Validate(args []string) error {
-	if len(args) != 2 {
-		return fmt.Errorf("requires 2 arguments. Run: kubectl route-info -h")
+	if len(args) == 0 {
+		return fmt.Errorf("requires a resource type argument. Run: kubectl route-info -h")
+	}
+
+	if !isResourceTypeSupported(args[0]) {
+		return fmt.Errorf("only %s types are supported. Run: kubectl route-info -h", strings.Join(resourceTypes, ", "))
+	}
+
+	if args[0] == "snapshot" || args[0] == "diff" || args[0] == "conflicts" {
+		return r.validateSnapshot(args)
+	}
+
+	listable := args[0] == "ingress" || args[0] == "service"
+
+	if !listable && len(args) != 2 {
+		return fmt.Errorf("%s type requires exactly 1 name argument. Run: kubectl route-info -h", args[0])
+	}
+
+	if !listable && (r.allNamespaces || r.selector != "") {
+		return fmt.Errorf("--all-namespaces and --selector flags are only supported for ingress and service types. Run: kubectl route-info -h")
+	}
+
+	if len(args) > 1 && (r.allNamespaces || r.selector != "") {
+		return fmt.Errorf("a resource name can not be used together with --all-namespaces or --selector flags. Run: kubectl route-info -h")
+	}
+
+	if r.output != "" && r.output != OutputJSON && r.output != OutputYAML && r.output != OutputWide && r.output != OutputDot && r.output != OutputMermaid {
+		return fmt.Errorf("output format %q is not supported, only json, yaml, wide, dot and mermaid are supported. Run: kubectl route-info -h", r.output)
+	}
+
+	// Pod and URL tables do not list the pods of services
+	if r.output == OutputWide && (args[0] == "pod" || args[0] == "url") {
+		return fmt.Errorf("wide output format is not supported for %s type. Run: kubectl route-info -h", args[0])
+	}
+
+	if r.check && (r.output == OutputWide || r.output == OutputDot || r.output == OutputMermaid) {
+		return fmt.Errorf("--check flag only supports json and yaml output formats. Run: kubectl route-info -h")
+	}
+
+	if r.output != "" && r.printGraph {
+		return fmt.Errorf("--output and --graph flags can not be used together. Run: kubectl route-info -h")
+	}
+
+	if r.check && r.printGraph {
+		return fmt.Errorf("--check and --graph flags can not be used together. Run: kubectl route-info -h")
+	}
+
+	if r.watch && r.check {
+		return fmt.Errorf("--watch and --check flags can not be used together. Run: kubectl route-info -h")
+	}
+
+	if r.watch && len(r.filenames) > 0 {
+		return fmt.Errorf("--watch and --filename flags can not be used together. Run: kubectl route-info -h")
+	}
+
+	if r.check && !listable {
+		return fmt.Errorf("--check flag is only supported for ingress and service types. Run: kubectl route-info -h")
+	}
+
+	if r.check && len(args) != 2 {
+		return fmt.Errorf("--check flag requires exactly 1 name argument. Run: kubectl route-info -h")
 	}
 
-	if args[0] != "ingress" && args[0] != "service" {
-		return fmt.Errorf("only ingress and service types are supported. Run: kubectl route-info -h")
+	return nil
+}
+
+// validateSnapshot ensures that the arguments and flags of the snapshot, diff
+// and conflicts types, which cover a whole namespace or cluster, are valid
+func (r *Resource) validateSnapshot(args []string) error {
+	if (args[0] == "snapshot" || args[0] == "conflicts") && len(args) != 1 {
+		return fmt.Errorf("%s type does not accept name arguments. Run: kubectl route-info -h", args[0])
+	}
+
+	if args[0] == "diff" && (len(args) < 2 || len(args) > 3) {
+		return fmt.Errorf("diff type requires 1 or 2 snapshot file arguments. Run: kubectl route-info -h")
+	}
+
+	if r.output != "" && r.output != OutputJSON && r.output != OutputYAML {
+		return fmt.Errorf("output format %q is not supported for %s type, only json and yaml are supported. Run: kubectl route-info -h", r.output, args[0])
+	}
+
+	if r.check || r.printGraph || r.watch {
+		return fmt.Errorf("--check, --graph and --watch flags are not supported for %s type. Run: kubectl route-info -h", args[0])
 	}
 
 	return nil
 }
 
+func isResourceTypeSupported(resourceType string) bool {
+	for _, supported := range resourceTypes {
+		if resourceType == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Complete sets all information required for the command
 func (r *Resource) Complete(cmd *cobra.Command, args []string) error {
 
 	var err error
 
 	r.resourceType = args[0]
-	r.resourceName = args[1]
+	r.resourceNames = args[1:]
 
-	config, err := r.configFlags.ToRESTConfig()
+	// Diffs between two snapshot files do not require a cluster
+	if r.resourceType == "diff" && len(r.resourceNames) == 2 {
+		return nil
+	}
+
+	namespace, err := r.namespace()
 	if err != nil {
 		return err
 	}
 
-	// TODO: Test this with the kubectl plugin ns
-	namespace, err := cmd.Flags().GetString("namespace")
+	client, err := r.newClient(namespace)
 	if err != nil {
 		return err
 	}
 
-	if namespace == "" {
-		namespace = "default"
+	// An empty namespace lists objects across all namespaces
+	if r.allNamespaces {
+		namespace = ""
+		client = client.Namespaced("")
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return err
-	}
+	if r.watch {
+		clusterClient, ok := client.(*route.Client)
+		if !ok {
+			return fmt.Errorf("--watch flag requires access to a cluster")
+		}
 
-	client := NewClient(clientset, namespace)
+		r.watcher = NewWatcher(clusterClient, namespace)
+		r.watcher.CustomResources = watchedCustomResources[r.resourceType]
+	}
 
 	switch r.resourceType {
 	case "service":
@@ -125,21 +316,155 @@ func (r *Resource) Complete(cmd *cobra.Command, args []string) error {
 
 	case "ingress":
 		r.resourceInterface = NewIngress(client, namespace)
+
+	case "pod":
+		r.resourceInterface = NewPod(client, namespace)
+
+	case "url":
+		r.resourceInterface = NewURL(client, namespace, r.showCandidates)
+
+	case "httproute":
+		r.resourceInterface = NewHTTPRoute(client, namespace)
+
+	case "gateway":
+		r.resourceInterface = NewGateway(client, namespace)
+
+	case "virtualservice":
+		r.resourceInterface = NewVirtualService(client, namespace)
+
+	case "route":
+		r.resourceInterface = NewOpenShiftRoute(client, namespace)
+
+	case "ingressroute":
+		r.resourceInterface = NewIngressRoute(client, namespace)
+
+	case "httpproxy":
+		r.resourceInterface = NewHTTPProxy(client, namespace)
+
+	case "snapshot", "diff":
+		r.snapshot = NewSnapshot(client, namespace)
+
+	case "conflicts":
+		r.conflicts = NewConflicts(client, namespace)
+	}
+
+	if r.output == OutputWide {
+		setPodDetails(r.resourceInterface)
 	}
 
 	return nil
 }
 
+// setPodDetails makes the resolver of a resource type read the details of the pods
+// backing its services, which wide tables print
+func setPodDetails(resource ResourceInterface) {
+	switch resource := resource.(type) {
+	case *Ingress:
+		resource.Resolver.PodDetails = true
+	case *Service:
+		resource.Resolver.PodDetails = true
+	case *HTTPRoute:
+		resource.Resolver.PodDetails = true
+	case *Gateway:
+		resource.Resolver.PodDetails = true
+	case *VirtualService:
+		resource.Resolver.PodDetails = true
+	case *OpenShiftRoute:
+		resource.Resolver.PodDetails = true
+	case *IngressRoute:
+		resource.Resolver.PodDetails = true
+	case *HTTPProxy:
+		resource.Resolver.PodDetails = true
+	}
+}
+
+// newClient returns the client of the cluster of the kubeconfig or, when manifests are given
+// with --filename, an in-memory client serving the objects of the manifests. Objects of the
+// manifests without namespace are set in the given namespace
+func (r *Resource) newClient(namespace string) (route.ClientInterface, error) {
+
+	if len(r.filenames) > 0 {
+		objects, err := LoadManifests(r.filenames, r.recursive)
+		if err != nil {
+			return nil, err
+		}
+
+		return route.NewMemoryClient(namespace, objects...)
+	}
+
+	config, err := r.configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	client := route.NewClient(clientset, namespace)
+	client.Dynamic = dynamicClient
+
+	return client, nil
+}
+
+// namespace returns the namespace of the command, which is taken from the --namespace flag,
+// the kubeconfig context or, when running in a pod, the service account namespace.
+// The kubeconfig loader falls back to "default" when none of them sets a namespace
+func (r *Resource) namespace() (string, error) {
+	namespace, _, err := r.configFlags.ToRawKubeConfigLoader().Namespace()
+	return namespace, err
+}
+
 // Run executes the command of printing the route information
 func (r *Resource) Run() (err error) {
 
+	if r.check {
+		return r.runCheck()
+	}
+
+	switch r.resourceType {
+	case "snapshot":
+		return r.runSnapshot()
+	case "diff":
+		return r.runDiff()
+	case "conflicts":
+		return r.runConflicts()
+	}
+
+	if r.watch {
+		return r.runWatch()
+	}
+
+	return r.print(r.Out)
+}
+
+// print prints the route information of the requested objects
+// in table, tree graph or machine-readable format
+func (r *Resource) print(w io.Writer) (err error) {
+
+	if r.isList() {
+		return r.runList(w)
+	}
+
+	name := r.resourceNames[0]
+
+	if r.output != "" && r.output != OutputWide {
+		return r.resourceInterface.PrintDocument(name, r.output, w)
+	}
+
 	if r.printGraph {
-		err := r.resourceInterface.PrintGraph(r.resourceName, os.Stdout)
+		err := r.resourceInterface.PrintGraph(name, w)
 		if err != nil {
 			return err
 		}
 	} else {
-		err := r.resourceInterface.PrintTable(r.resourceName, os.Stdout)
+		err := r.resourceInterface.PrintTable(name, w)
 		if err != nil {
 			return err
 		}
@@ -147,3 +472,142 @@ func (r *Resource) Run() (err error) {
 
 	return
 }
+
+// runWatch prints the route information of the requested objects
+// whenever it changes, until the command is interrupted
+func (r *Resource) runWatch() error {
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Cluster scoped objects, such as nodes, are cached by the resolvers and may have changed
+	print := r.print
+	if cache, ok := r.resourceInterface.(CacheInterface); ok {
+		print = func(w io.Writer) error {
+			cache.ResetCache()
+			return r.print(w)
+		}
+	}
+
+	return r.watcher.Watch(ctx, print, r.Out)
+}
+
+// isList returns whether the command prints the route information
+// of several objects rather than the one of a single named object
+func (r *Resource) isList() bool {
+	return len(r.resourceNames) != 1 || r.allNamespaces || r.selector != ""
+}
+
+// runList prints the route information of several objects
+// in one combined table, a forest of tree graphs or a document
+func (r *Resource) runList(w io.Writer) error {
+
+	lister, ok := r.resourceInterface.(ListerInterface)
+	if !ok {
+		return fmt.Errorf("%s type does not support listing several objects", r.resourceType)
+	}
+
+	if r.output != "" && r.output != OutputWide {
+		return lister.PrintDocumentList(r.resourceNames, r.selector, r.output, w)
+	}
+
+	if r.printGraph {
+		return lister.PrintGraphList(r.resourceNames, r.selector, w)
+	}
+
+	return lister.PrintTableList(r.resourceNames, r.selector, w)
+}
+
+// runCheck prints the findings of the route chain and returns
+// an error when any of them has error severity
+func (r *Resource) runCheck() error {
+
+	checker, ok := r.resourceInterface.(CheckerInterface)
+	if !ok {
+		return fmt.Errorf("%s type does not support --check", r.resourceType)
+	}
+
+	name := r.resourceNames[0]
+
+	findings, err := checker.Check(name)
+	if err != nil {
+		return err
+	}
+
+	if err := PrintFindings(findings, r.output, r.Out); err != nil {
+		return err
+	}
+
+	if errors := route.CountErrors(findings); errors > 0 {
+		return fmt.Errorf("%d error(s) found in the route chain of %s %s", errors, r.resourceType, name)
+	}
+
+	return nil
+}
+
+// runSnapshot prints a snapshot of the route graph of the namespace or cluster,
+// in JSON format unless another machine-readable output is given
+func (r *Resource) runSnapshot() error {
+
+	document, err := r.snapshot.Take(r.selector)
+	if err != nil {
+		return err
+	}
+
+	output := r.output
+	if output == "" {
+		output = OutputJSON
+	}
+
+	return PrintDocument(document, output, r.Out)
+}
+
+// runDiff prints the changes between two snapshot files or, when only
+// one is given, between the snapshot file and the current route graph
+func (r *Resource) runDiff() error {
+
+	from, err := LoadSnapshot(r.resourceNames[0])
+	if err != nil {
+		return err
+	}
+
+	var to *route.Document
+	if len(r.resourceNames) == 2 {
+		to, err = LoadSnapshot(r.resourceNames[1])
+	} else {
+		to, err = r.snapshot.Take(r.selector)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return PrintDiff(route.DiffDocuments(from, to), r.output, r.Out)
+}
+
+// runConflicts prints the conflicts between the ingresses of the namespace or
+// cluster and returns an error when any of them has error severity
+func (r *Resource) runConflicts() error {
+
+	conflicts, err := r.conflicts.Find(r.selector)
+	if err != nil {
+		return err
+	}
+
+	if err := PrintConflicts(conflicts, r.output, r.Out); err != nil {
+		return err
+	}
+
+	errors := 0
+	for _, conflict := range conflicts {
+		if conflict.Severity == route.SeverityError {
+			errors++
+		}
+	}
+
+	if errors > 0 {
+		return fmt.Errorf("%d conflict(s) with error severity found between ingresses", errors)
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const kubeconfigTemplate = `apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: user
+  user:
+    token: token
+contexts:
+- name: context-with-namespace
+  context:
+    cluster: cluster
+    user: user
+    namespace: context-namespace
+- name: context-without-namespace
+  context:
+    cluster: cluster
+    user: user
+current-context: %s
+`
+
+// newNamespaceResource returns a Resource whose kubeconfig uses the given
+// current context and whose --namespace flag is set to the given namespace
+func newNamespaceResource(t *testing.T, currentContext string, namespace string) *Resource {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+
+	content := []byte(fmt.Sprintf(kubeconfigTemplate, currentContext))
+	if err := ioutil.WriteFile(kubeconfig, content, 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	r := NewResource(genericclioptions.NewTestIOStreamsDiscard())
+	r.configFlags.KubeConfig = &kubeconfig
+	r.configFlags.Namespace = &namespace
+
+	return r
+}
+
+func TestResourceNamespace(t *testing.T) {
+
+	tests := []struct {
+		name              string
+		currentContext    string
+		contextFlag       string
+		namespaceFlag     string
+		expectedNamespace string
+	}{
+		{
+			"namespace flag",
+			"context-with-namespace",
+			"",
+			"flag-namespace",
+			"flag-namespace",
+		},
+		{
+			"context namespace",
+			"context-with-namespace",
+			"",
+			"",
+			"context-namespace",
+		},
+		{
+			"context flag namespace",
+			"context-without-namespace",
+			"context-with-namespace",
+			"",
+			"context-namespace",
+		},
+		{
+			"namespace flag over context flag",
+			"context-without-namespace",
+			"context-with-namespace",
+			"flag-namespace",
+			"flag-namespace",
+		},
+		{
+			"default namespace",
+			"context-without-namespace",
+			"",
+			"",
+			"default",
+		},
+		{
+			"default namespace of context flag",
+			"context-with-namespace",
+			"context-without-namespace",
+			"",
+			"default",
+		},
+	}
+
+	for _, test := range tests {
+
+		r := newNamespaceResource(t, test.currentContext, test.namespaceFlag)
+		r.configFlags.Context = &test.contextFlag
+
+		namespace, err := r.namespace()
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		if namespace != test.expectedNamespace {
+			t.Errorf("Returned namespace for %s was incorrect, got: %s, want: %s", test.name, namespace, test.expectedNamespace)
+		}
+	}
+}
+
+const offlineManifests = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: foo
+  template:
+    metadata:
+      labels:
+        app: foo
+    spec:
+      containers:
+      - name: app
+        image: foo
+        ports:
+        - name: http
+          containerPort: 8080
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+spec:
+  selector:
+    app: foo
+  ports:
+  - name: http
+    port: 80
+    targetPort: http
+`
+
+const offlineIngress = `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: my-ingress
+spec:
+  rules:
+  - host: foo.example.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: foo
+            port:
+              name: http
+      - path: /missing
+        pathType: Prefix
+        backend:
+          service:
+            name: missing
+            port:
+              number: 80
+`
+
+func TestResourceRunOffline(t *testing.T) {
+
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "nested")
+	if err := os.Mkdir(nested, 0700); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "foo.yaml"), []byte(offlineManifests), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(nested, "ingress.yaml"), []byte(offlineIngress), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		recursive     bool
+		expectedError string
+	}{
+		{
+			"recursive",
+			true,
+			"1 error(s) found in the route chain of ingress my-ingress",
+		},
+		{
+			"not recursive",
+			false,
+			"ingresses.networking.k8s.io \"my-ingress\" not found",
+		},
+	}
+
+	for _, test := range tests {
+
+		streams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+		r := newNamespaceResource(t, "context-without-namespace", "")
+		r.IOStreams = streams
+		r.filenames = []string{dir}
+		r.recursive = test.recursive
+		r.check = true
+
+		if err := r.Complete(nil, []string{"ingress", "my-ingress"}); err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		err := r.Run()
+		if err == nil || err.Error() != test.expectedError {
+			t.Errorf("Returned error for %s was incorrect, got: %v, want: %s", test.name, err, test.expectedError)
+		}
+
+		if test.recursive && !strings.Contains(out.String(), "ServiceNotFound") {
+			t.Errorf("Returned findings for %s do not contain ServiceNotFound, got: %s", test.name, out.String())
+		}
+	}
+}
+
+func TestResourceValidateWide(t *testing.T) {
+
+	tests := []struct {
+		args          []string
+		expectedError string
+	}{
+		{[]string{"service", "foo"}, ""},
+		{[]string{"pod", "foo"}, "wide output format is not supported for pod type. Run: kubectl route-info -h"},
+		{[]string{"url", "https://foo.com"}, "wide output format is not supported for url type. Run: kubectl route-info -h"},
+	}
+
+	for _, test := range tests {
+
+		r := NewResource(genericclioptions.NewTestIOStreamsDiscard())
+		r.output = OutputWide
+
+		err := r.Validate(test.args)
+		if (err == nil && test.expectedError != "") || (err != nil && err.Error() != test.expectedError) {
+			t.Errorf("Returned error for %s was incorrect, got: %v, want: %s", test.args[0], err, test.expectedError)
+		}
+	}
+}
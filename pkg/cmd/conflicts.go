@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"kube-route-info/pkg/route"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// Conflicts defines Conflicts atributes
+type Conflicts struct {
+	Resolver *route.Resolver
+}
+
+// NewConflicts returns a new Conflicts struct
+func NewConflicts(client route.ClientInterface, namespace string) *Conflicts {
+	return &Conflicts{
+		Resolver: route.NewResolver(client, namespace),
+	}
+}
+
+// Find returns the conflicts between the ingresses that match a label selector,
+// in the namespace of the resolver or across all namespaces when it is empty
+func (c *Conflicts) Find(selector string) ([]route.Conflict, error) {
+	return c.Resolver.ResolveConflicts(selector)
+}
+
+// PrintConflicts prints the conflicts between ingresses in table format,
+// or in a machine-readable format when an output is given
+func PrintConflicts(conflicts []route.Conflict, output string, w io.Writer) error {
+
+	if output != "" {
+		document := route.NewDocument()
+		document.Conflicts = conflicts
+
+		return PrintDocument(document, output, w)
+	}
+
+	if len(conflicts) == 0 {
+		fmt.Fprintln(w, "No conflicts found between ingresses")
+		return nil
+	}
+
+	rows := []metav1.TableRow{}
+
+	for _, conflict := range conflicts {
+		path := conflict.Path
+		if path == "" {
+			path = "<none>"
+		}
+
+		rows = append(rows, metav1.TableRow{
+			Cells: []interface{}{
+				conflict.Severity,
+				conflict.Type,
+				conflict.Host,
+				path,
+				ConflictIngressesToString(conflict.Ingresses),
+				conflict.Message,
+			},
+		})
+	}
+
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Severity", Type: "string"},
+			{Name: "Type", Type: "string"},
+			{Name: "Host", Type: "string"},
+			{Name: "Path", Type: "string"},
+			{Name: "Ingresses", Type: "string"},
+			{Name: "Message", Type: "string"},
+		},
+		Rows: rows,
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	printer.PrintObj(table, out)
+
+	fmt.Fprint(w, out.String())
+
+	return nil
+}
+
+// ConflictIngressesToString returns the ingresses of a conflict along with
+// their class, such as "team-a/shop (nginx), team-b/shop (traefik)"
+func ConflictIngressesToString(ingresses []route.ConflictIngress) string {
+	names := []string{}
+
+	for _, ingress := range ingresses {
+		class := ingress.Class
+		if class == "" {
+			class = "<none>"
+		}
+
+		names = append(names, ingress.Namespace+"/"+ingress.Name+" ("+class+")")
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// conflictingIngress routes a path below the paths of offlineIngress to another service
+const conflictingIngress = `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: my-other-ingress
+spec:
+  ingressClassName: traefik
+  rules:
+  - host: foo.example.com
+    http:
+      paths:
+      - path: /missing/v2
+        pathType: Prefix
+        backend:
+          service:
+            name: bar
+            port:
+              number: 80
+`
+
+func TestConflictsPrintSuccessful(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		manifests string
+		output    string
+		expected  string
+	}{
+		{
+			"shadowed prefixes",
+			offlineIngress + "---\n" + conflictingIngress,
+			"",
+			"SEVERITY   TYPE             HOST              PATH       INGRESSES                                                         MESSAGE\nWarning    ShadowedPrefix   foo.example.com   /          default/my-ingress (<none>), default/my-other-ingress (traefik)   requests to /missing/v2 are served by default/my-other-ingress instead of default/my-ingress; the ingresses belong to the <none>, traefik classes, so their controllers serve them separately\nWarning    ShadowedPrefix   foo.example.com   /missing   default/my-ingress (<none>), default/my-other-ingress (traefik)   requests to /missing/v2 are served by default/my-other-ingress instead of default/my-ingress; the ingresses belong to the <none>, traefik classes, so their controllers serve them separately\n",
+		},
+		{
+			"shadowed prefixes in yaml",
+			offlineIngress + "---\n" + conflictingIngress,
+			OutputYAML,
+			"apiVersion: route-info/v1\nconflicts:\n- crossController: true\n  host: foo.example.com\n  ingresses:\n  - backend: foo:http\n    name: my-ingress\n    namespace: default\n    path: /\n    pathType: Prefix\n  - backend: bar:80\n    class: traefik\n    name: my-other-ingress\n    namespace: default\n    path: /missing/v2\n    pathType: Prefix\n  message: requests to /missing/v2 are served by default/my-other-ingress instead\n    of default/my-ingress; the ingresses belong to the <none>, traefik classes, so\n    their controllers serve them separately\n  path: /\n  severity: Warning\n  type: ShadowedPrefix\n- crossController: true\n  host: foo.example.com\n  ingresses:\n  - backend: missing:80\n    name: my-ingress\n    namespace: default\n    path: /missing\n    pathType: Prefix\n  - backend: bar:80\n    class: traefik\n    name: my-other-ingress\n    namespace: default\n    path: /missing/v2\n    pathType: Prefix\n  message: requests to /missing/v2 are served by default/my-other-ingress instead\n    of default/my-ingress; the ingresses belong to the <none>, traefik classes, so\n    their controllers serve them separately\n  path: /missing\n  severity: Warning\n  type: ShadowedPrefix\nkind: RouteInfo\n",
+		},
+		{
+			"no conflicts",
+			offlineIngress,
+			"",
+			"No conflicts found between ingresses\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		output := runOfflineResource(t, test.manifests, test.output, "conflicts")
+
+		if output != test.expected {
+			t.Errorf("Returned conflicts for %s were incorrect, got: %q, want: %q", test.name, output, test.expected)
+		}
+	}
+}
+
+func TestConflictsPrintDuplicatePath(t *testing.T) {
+
+	duplicate := offlineIngress + "---\n" + strings.Replace(conflictingIngress, "/missing/v2", "/missing", 1)
+	duplicate = strings.Replace(duplicate, "  ingressClassName: traefik\n", "", 1)
+
+	filename := filepath.Join(t.TempDir(), "manifests.yaml")
+	if err := ioutil.WriteFile(filename, []byte(duplicate), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+	r := newNamespaceResource(t, "context-without-namespace", "")
+	r.IOStreams = streams
+	r.filenames = []string{filename}
+
+	args := []string{"conflicts"}
+
+	if err := r.Validate(args); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := r.Complete(nil, args); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err := r.Run()
+
+	expectedError := "1 conflict(s) with error severity found between ingresses"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Returned error was incorrect, got: %v, want: %s", err, expectedError)
+	}
+
+	expected := "SEVERITY   TYPE             HOST              PATH       INGRESSES                                                        MESSAGE\nError      DuplicatePath    foo.example.com   /missing   default/my-ingress (<none>), default/my-other-ingress (<none>)   path is claimed by several ingresses with the backends bar:80, missing:80, only one of them receives the requests\nWarning    ShadowedPrefix   foo.example.com   /          default/my-ingress (<none>), default/my-other-ingress (<none>)   requests to /missing are served by default/my-other-ingress instead of default/my-ingress\n"
+	if out.String() != expected {
+		t.Errorf("Returned conflicts were incorrect, got: %q, want: %q", out.String(), expected)
+	}
+}
@@ -0,0 +1,470 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"kube-route-info/pkg/route"
+)
+
+// Supported diagram output formats
+const (
+	OutputDot     = "dot"
+	OutputMermaid = "mermaid"
+)
+
+// Diagram node kinds, which set the shape of the nodes
+const (
+	nodeEntry    = "entry"
+	nodeHost     = "host"
+	nodePath     = "path"
+	nodeService  = "service"
+	nodePod      = "pod"
+	nodeEndpoint = "endpoint"
+)
+
+// Diagram node styles flagging the broken links of a route graph
+const (
+	styleMissing   = "missing"
+	styleUnhealthy = "unhealthy"
+)
+
+// dotShapes and mermaidShapes map the node kinds to the Graphviz shapes
+// and to the opening and closing brackets of the Mermaid shapes
+var dotShapes = map[string]string{
+	nodeEntry:    "box",
+	nodeHost:     "ellipse",
+	nodePath:     "box",
+	nodeService:  "hexagon",
+	nodePod:      "component",
+	nodeEndpoint: "parallelogram",
+}
+
+var mermaidShapes = map[string][2]string{
+	nodeEntry:    {"[", "]"},
+	nodeHost:     {"([", "])"},
+	nodePath:     {"(", ")"},
+	nodeService:  {"{{", "}}"},
+	nodePod:      {"[[", "]]"},
+	nodeEndpoint: {"[/", "/]"},
+}
+
+type diagramNode struct {
+	id    string
+	kind  string
+	label string
+	style string
+}
+
+type diagramEdge struct {
+	from  string
+	to    string
+	label string
+}
+
+// Diagram is the route graph of a document as a directed acyclic graph, where the
+// services and pods shared by several paths or routes are a single node
+type Diagram struct {
+	nodes []diagramNode
+	ids   map[string]string
+	edges []diagramEdge
+	links map[string]bool
+}
+
+// NewDiagram returns the diagram of the route graphs of a document
+func NewDiagram(document *route.Document) *Diagram {
+	d := &Diagram{
+		ids:   map[string]string{},
+		links: map[string]bool{},
+	}
+
+	routes := append([]*route.Route{document.Ingress, document.HTTPRoute, document.VirtualService,
+		document.OpenShiftRoute, document.IngressRoute, document.HTTPProxy}, document.Ingresses...)
+
+	for _, r := range routes {
+		if r != nil {
+			d.addRoute(r)
+		}
+	}
+
+	if document.Gateway != nil {
+		d.addGateway(document.Gateway)
+	}
+
+	for _, service := range append([]*route.Service{document.Service}, document.Services...) {
+		if service != nil {
+			d.addService(service)
+		}
+	}
+
+	if document.Pod != nil {
+		d.addPodRoute(document.Pod)
+	}
+
+	if document.URL != nil {
+		d.addURLRoute(document.URL)
+	}
+
+	return d
+}
+
+// addNode adds a node once per key and returns its identifier
+func (d *Diagram) addNode(key string, kind string, label string, style string) string {
+	if id, ok := d.ids[key]; ok {
+		return id
+	}
+
+	id := fmt.Sprintf("n%d", len(d.nodes))
+	d.ids[key] = id
+	d.nodes = append(d.nodes, diagramNode{id: id, kind: kind, label: label, style: style})
+
+	return id
+}
+
+// addEdge adds an edge once per pair of nodes and label
+func (d *Diagram) addEdge(from string, to string, label string) {
+	link := from + " " + to + " " + label
+	if d.links[link] {
+		return
+	}
+
+	d.links[link] = true
+	d.edges = append(d.edges, diagramEdge{from: from, to: to, label: label})
+}
+
+func (d *Diagram) addRoute(r *route.Route) string {
+	kind := RouteKindToString(r)
+	key := kind + "/" + r.Namespace + "/" + r.Name
+
+	entry := d.addNode(key, nodeEntry, kind+": "+r.Name, "")
+
+	for _, host := range r.Hosts {
+
+		hostStyle := ""
+		if len(host.Warnings) > 0 {
+			hostStyle = styleUnhealthy
+		}
+
+		hostKey := key + "|" + host.Host
+		hostNode := d.addNode(hostKey, nodeHost, hostLabel(host), hostStyle)
+		d.addEdge(entry, hostNode, "")
+
+		for _, path := range host.Paths {
+
+			label := path.Path
+			if match := MatchToString(path); match != "" {
+				label = strings.TrimSpace(label + " [" + match + "]")
+			}
+
+			pathNode := d.addNode(hostKey+"|"+label, nodePath, label, "")
+			d.addEdge(hostNode, pathNode, "")
+
+			if path.Service != nil {
+				d.addEdge(pathNode, d.addService(path.Service), backendLabel(path))
+			}
+		}
+	}
+
+	if r.DefaultBackend != nil {
+		label := "default backend"
+		if backend := backendLabel(*r.DefaultBackend); backend != "" {
+			label += ", " + backend
+		}
+
+		d.addEdge(entry, d.addService(r.DefaultBackend.Service), label)
+	}
+
+	return entry
+}
+
+func (d *Diagram) addGateway(gateway *route.Gateway) {
+	key := "Gateway/" + gateway.Namespace + "/" + gateway.Name
+
+	entry := d.addNode(key, nodeEntry, "Gateway: "+gateway.Name, "")
+
+	for _, listener := range gateway.Listeners {
+
+		label := fmt.Sprintf("Listener: %s %d/%s", listener.Name, listener.Port, listener.Protocol)
+		if listener.Hostname != "" {
+			label += " " + listener.Hostname
+		}
+
+		listenerNode := d.addNode(key+"|"+listener.Name, nodeEntry, label, "")
+		d.addEdge(entry, listenerNode, "")
+
+		for _, r := range listener.Routes {
+			d.addEdge(listenerNode, d.addRoute(r), "")
+		}
+	}
+}
+
+// addService adds the node of a service along with its pods, endpoints or hostname, and returns its identifier.
+// Services that do not exist are flagged as missing, and services without ready pods as unhealthy
+func (d *Diagram) addService(service *route.Service) string {
+	key := "Service/" + service.Namespace + "/" + service.Name
+	label := "Service: " + service.Name
+	style := ""
+
+	switch {
+	case service.Type == route.BackendTypeResource:
+		key = route.BackendTypeResource + "/" + service.Namespace + "/" + service.Name
+		label = "Resource: " + service.Name
+
+	case !service.Found:
+		label = service.Name + " (not found)"
+		if service.Type != "" {
+			label = service.Type + ": " + label
+		} else {
+			label = "Service: " + label
+		}
+		style = styleMissing
+
+	case service.Hostname == "" && !hasReadyPod(service):
+		style = styleUnhealthy
+	}
+
+	if id, ok := d.ids[key]; ok {
+		return id
+	}
+
+	serviceNode := d.addNode(key, nodeService, label, style)
+
+	if service.Hostname != "" {
+		d.addEdge(serviceNode, d.addNode("Hostname/"+service.Hostname, nodeEndpoint, "Hostname: "+service.Hostname, ""), "")
+	}
+
+	for _, pod := range service.Pods {
+
+		podStyle := ""
+		if !pod.Ready || pod.Terminating || !hasValidTargetPorts(service, pod.Name) {
+			podStyle = styleUnhealthy
+		}
+
+		label := "Pod: " + PodToString(pod)
+		if targets := PodTargetPortsToString(service, pod.Name); targets != "" {
+			label += " (" + targets + ")"
+		}
+
+		d.addEdge(serviceNode, d.addNode("Pod/"+service.Namespace+"/"+pod.Name, nodePod, label, podStyle), "")
+	}
+
+	for _, endpoint := range service.Endpoints {
+		if endpoint.Pod != "" {
+			continue
+		}
+
+		addresses := strings.Join(endpoint.Addresses, ",")
+
+		endpointStyle := ""
+		if !endpoint.Ready {
+			endpointStyle = styleUnhealthy
+		}
+
+		d.addEdge(serviceNode, d.addNode("Endpoint/"+service.Namespace+"/"+addresses, nodeEndpoint, "Endpoint: "+addresses, endpointStyle), "")
+	}
+
+	return serviceNode
+}
+
+func (d *Diagram) addPodRoute(pod *route.PodRoute) {
+	podNode := d.addNode("Pod/"+pod.Namespace+"/"+pod.Name, nodePod, "Pod: "+pod.Name, "")
+
+	for _, service := range pod.Services {
+
+		serviceNode := d.addNode("Service/"+pod.Namespace+"/"+service.Name, nodeService, "Service: "+service.Name, "")
+		d.addEdge(serviceNode, podNode, "")
+
+		for _, backend := range service.Backends {
+			pathNode := d.addIngressPath(pod.Namespace, backend.Ingress, backend.Host, backend.Path)
+			d.addEdge(pathNode, serviceNode, "port "+backend.BackendPort)
+		}
+	}
+}
+
+func (d *Diagram) addURLRoute(url *route.URLRoute) {
+	urlNode := d.addNode("URL/"+url.URL, nodeEntry, "URL: "+url.URL, "")
+
+	candidates := []route.URLCandidate{}
+	if url.Match != nil {
+		candidates = append(candidates, *url.Match)
+	}
+
+	for index, candidate := range append(candidates, url.Candidates...) {
+
+		label := "candidate"
+		if index == 0 && url.Match != nil {
+			label = "match"
+		}
+
+		namespace := ""
+		if candidate.Service != nil {
+			namespace = candidate.Service.Namespace
+		}
+
+		pathNode := d.addIngressPath(namespace, candidate.Ingress, candidate.Host, candidate.Path)
+		d.addEdge(urlNode, pathNode, label)
+
+		if candidate.Service != nil {
+			d.addEdge(pathNode, d.addService(candidate.Service), "port "+candidate.BackendPort)
+		}
+	}
+}
+
+// addIngressPath adds the ingress, host and path nodes of an ingress path and returns the identifier of the path node
+func (d *Diagram) addIngressPath(namespace string, ingress string, host string, path string) string {
+	key := "Ingress/" + namespace + "/" + ingress
+
+	ingressNode := d.addNode(key, nodeEntry, "Ingress: "+ingress, "")
+	hostNode := d.addNode(key+"|"+host, nodeHost, hostLabel(route.Host{Host: host}), "")
+	pathNode := d.addNode(key+"|"+host+"|"+path, nodePath, path, "")
+
+	d.addEdge(ingressNode, hostNode, "")
+	d.addEdge(hostNode, pathNode, "")
+
+	return pathNode
+}
+
+// hostLabel returns the host name, * for rules without host, along with its TLS secret or termination
+func hostLabel(host route.Host) string {
+	label := host.Host
+	if label == "" {
+		label = "*"
+	}
+
+	if tls := TLSToString(host.TLS); tls != "" {
+		label += " (TLS " + tls + ")"
+	}
+
+	return label
+}
+
+// backendLabel returns the port, subset and weight of a path backend separated by commas
+func backendLabel(path route.Path) string {
+	details := []string{}
+
+	if path.BackendPort != "" {
+		details = append(details, "port "+path.BackendPort)
+	}
+
+	if path.Subset != nil {
+		details = append(details, "subset "+SubsetToString(path.Subset))
+	}
+
+	if path.Weight != nil {
+		details = append(details, "weight "+WeightToString(path.Weight))
+	}
+
+	return strings.Join(details, ", ")
+}
+
+// hasValidTargetPorts returns whether the named target ports of a service resolve on a pod to the expected numbers
+func hasValidTargetPorts(service *route.Service, pod string) bool {
+	for _, port := range service.Ports {
+		for _, targetPort := range port.TargetPorts {
+			if targetPort.Pod == pod && (!targetPort.Found || targetPort.Mismatch) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func hasReadyPod(service *route.Service) bool {
+	for _, endpoint := range service.Endpoints {
+		if endpoint.Ready && !endpoint.Terminating {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PrintDot prints the diagram in the Graphviz DOT language
+func (d *Diagram) PrintDot(w io.Writer) {
+	fmt.Fprintln(w, "digraph route {")
+	fmt.Fprintln(w, "    rankdir=LR;")
+
+	for _, node := range d.nodes {
+		attributes := fmt.Sprintf("label=%s, shape=%s", dotQuote(node.label), dotShapes[node.kind])
+
+		styles := []string{}
+		if node.kind == nodePath {
+			styles = append(styles, "rounded")
+		}
+
+		switch node.style {
+		case styleMissing:
+			attributes += ", color=red, fontcolor=red"
+			styles = append(styles, "dashed")
+		case styleUnhealthy:
+			attributes += ", color=orange"
+			styles = append(styles, "bold")
+		}
+
+		if len(styles) > 0 {
+			attributes += ", style=" + dotQuote(strings.Join(styles, ","))
+		}
+
+		fmt.Fprintf(w, "    %s [%s];\n", node.id, attributes)
+	}
+
+	for _, edge := range d.edges {
+		if edge.label == "" {
+			fmt.Fprintf(w, "    %s -> %s;\n", edge.from, edge.to)
+			continue
+		}
+
+		fmt.Fprintf(w, "    %s -> %s [label=%s];\n", edge.from, edge.to, dotQuote(edge.label))
+	}
+
+	fmt.Fprintln(w, "}")
+}
+
+// PrintMermaid prints the diagram as a Mermaid flowchart
+func (d *Diagram) PrintMermaid(w io.Writer) {
+	fmt.Fprintln(w, "flowchart LR")
+
+	styled := map[string][]string{}
+
+	for _, node := range d.nodes {
+		shape := mermaidShapes[node.kind]
+		fmt.Fprintf(w, "    %s%s%s%s\n", node.id, shape[0], mermaidQuote(node.label), shape[1])
+
+		if node.style != "" {
+			styled[node.style] = append(styled[node.style], node.id)
+		}
+	}
+
+	for _, edge := range d.edges {
+		if edge.label == "" {
+			fmt.Fprintf(w, "    %s --> %s\n", edge.from, edge.to)
+			continue
+		}
+
+		fmt.Fprintf(w, "    %s -->|%s| %s\n", edge.from, mermaidQuote(edge.label), edge.to)
+	}
+
+	if len(styled) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "    classDef missing stroke:#d00,color:#d00,stroke-dasharray:5 5")
+	fmt.Fprintln(w, "    classDef unhealthy stroke:#e80,stroke-width:3px")
+
+	for _, style := range []string{styleMissing, styleUnhealthy} {
+		if ids := styled[style]; len(ids) > 0 {
+			fmt.Fprintf(w, "    class %s %s\n", strings.Join(ids, ","), style)
+		}
+	}
+}
+
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// mermaidQuote quotes a Mermaid label, where quotes are written as entity codes
+func mermaidQuote(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "#quot;") + `"`
+}
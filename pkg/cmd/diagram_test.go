@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIngressPrintDiagramSuccessful(t *testing.T) {
+
+	tests := []struct {
+		name            string
+		output          string
+		ingresses       []string
+		expectedDiagram string
+	}{
+		{
+			"dot",
+			OutputDot,
+			[]string{"ingress-tls"},
+			"digraph route {\n    rankdir=LR;\n    n0 [label=\"Ingress: ingress-tls\", shape=box];\n    n1 [label=\"secure.ingress.com (TLS secure-tls (expires 2099-01-01))\", shape=ellipse];\n    n2 [label=\"/bar\", shape=box, style=\"rounded\"];\n    n3 [label=\"Service: service-bar\", shape=hexagon];\n    n4 [label=\"Pod: pod-bar-1 (http=app:8080)\", shape=component];\n    n5 [label=\"plain.ingress.com\", shape=ellipse, color=orange, style=\"bold\"];\n    n6 [label=\"/bar\", shape=box, style=\"rounded\"];\n    n0 -> n1;\n    n1 -> n2;\n    n3 -> n4;\n    n2 -> n3 [label=\"port 80\"];\n    n0 -> n5;\n    n5 -> n6;\n    n6 -> n3 [label=\"port 80\"];\n}\n",
+		},
+		{
+			"mermaid",
+			OutputMermaid,
+			[]string{"ingress-tls"},
+			"flowchart LR\n    n0[\"Ingress: ingress-tls\"]\n    n1([\"secure.ingress.com (TLS secure-tls (expires 2099-01-01))\"])\n    n2(\"/bar\")\n    n3{{\"Service: service-bar\"}}\n    n4[[\"Pod: pod-bar-1 (http=app:8080)\"]]\n    n5([\"plain.ingress.com\"])\n    n6(\"/bar\")\n    n0 --> n1\n    n1 --> n2\n    n3 --> n4\n    n2 -->|\"port 80\"| n3\n    n0 --> n5\n    n5 --> n6\n    n6 -->|\"port 80\"| n3\n    classDef missing stroke:#d00,color:#d00,stroke-dasharray:5 5\n    classDef unhealthy stroke:#e80,stroke-width:3px\n    class n5 unhealthy\n",
+		},
+		{
+			"mermaid shared services",
+			OutputMermaid,
+			[]string{"ingress-2-backends", "ingress-default-backend"},
+			"flowchart LR\n    n0[\"Ingress: ingress-2-backends\"]\n    n1([\"*\"])\n    n2(\"/foo\")\n    n3{{\"Service: service-foo\"}}\n    n4[[\"Pod: pod-foo-1 (https=app:8443)\"]]\n    n5[[\"Pod: pod-foo-2 (https=app:8443)\"]]\n    n6(\"/bar\")\n    n7{{\"Service: service-bar\"}}\n    n8[[\"Pod: pod-bar-1 (http=app:8080)\"]]\n    n9[\"Ingress: ingress-default-backend\"]\n    n10([\"foo.ingress.com\"])\n    n11([\"static.ingress.com\"])\n    n12(\"/assets\")\n    n13{{\"Resource: k8s.example.com/StorageBucket/static-assets\"}}\n    n0 --> n1\n    n1 --> n2\n    n3 --> n4\n    n3 --> n5\n    n2 -->|\"port 80\"| n3\n    n1 --> n6\n    n7 --> n8\n    n6 -->|\"port 80\"| n7\n    n9 --> n10\n    n9 --> n11\n    n11 --> n12\n    n12 --> n13\n    n9 -->|\"default backend, port 80\"| n3\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		ingress := NewIngress(NewIngressMockClient(), "default")
+
+		buf := bytes.NewBuffer([]byte{})
+		if err := ingress.PrintDocumentList(test.ingresses, "", test.output, buf); err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		if buf.String() != test.expectedDiagram {
+			t.Errorf("Returned %s diagram was incorrect,\ngot:\n%s\nwant:\n%s", test.name, buf.String(), test.expectedDiagram)
+		}
+	}
+}
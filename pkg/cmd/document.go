@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"kube-route-info/pkg/route"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Supported machine-readable output formats
+const (
+	OutputJSON = "json"
+	OutputYAML = "yaml"
+)
+
+// PrintDocument prints a route document in the given output format,
+// either machine-readable or as a diagram of its route graph
+func PrintDocument(document *route.Document, output string, w io.Writer) error {
+
+	switch output {
+	case OutputDot:
+		NewDiagram(document).PrintDot(w)
+		return nil
+	case OutputMermaid:
+		NewDiagram(document).PrintMermaid(w)
+		return nil
+	}
+
+	return printObject(document, output, w)
+}
+
+// printObject prints a document in the given machine-readable output format
+func printObject(object interface{}, output string, w io.Writer) error {
+	var data []byte
+	var err error
+
+	switch output {
+	case OutputJSON:
+		data, err = json.MarshalIndent(object, "", "    ")
+		data = append(data, '\n')
+	case OutputYAML:
+		data, err = yaml.Marshal(object)
+	default:
+		return fmt.Errorf("unsupported output format %q", output)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
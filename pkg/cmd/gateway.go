@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"kube-route-info/pkg/route"
+
+	"github.com/xlab/treeprint"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// Gateway defines Gateway attributes
+type Gateway struct {
+	Resolver *route.Resolver
+}
+
+// NewGateway returns a new Gateway struct
+func NewGateway(client route.ClientInterface, namespace string) *Gateway {
+	return &Gateway{
+		Resolver: route.NewResolver(client, namespace),
+	}
+}
+
+// ResetCache drops the cluster scoped objects cached by the resolver, so that they are read again
+func (g *Gateway) ResetCache() {
+	g.Resolver.ResetCache()
+}
+
+// PrintGraph prints gateway route information in a tree graph format
+func (g *Gateway) PrintGraph(name string, w io.Writer) error {
+
+	gateway, err := g.Resolver.ResolveGateway(name)
+	if err != nil {
+		return err
+	}
+
+	tree := treeprint.New()
+
+	gatewayBranch := tree.AddMetaBranch("Gateway", gateway.Name)
+
+	for _, listener := range gateway.Listeners {
+
+		listenerBranch := gatewayBranch.AddMetaBranch("Listener", ListenerToString(listener))
+
+		for _, httpRoute := range listener.Routes {
+
+			routeBranch := listenerBranch.AddMetaBranch(RouteKindToString(httpRoute), RouteNameToString(httpRoute, gateway.Namespace))
+
+			AddHostBranches(routeBranch, httpRoute)
+		}
+	}
+
+	fmt.Fprint(w, gatewayBranch.String())
+
+	return nil
+}
+
+// PrintTable prints gateway route information in table format
+func (g *Gateway) PrintTable(name string, w io.Writer) error {
+
+	gateway, err := g.Resolver.ResolveGateway(name)
+	if err != nil {
+		return err
+	}
+
+	routes := []*route.Route{}
+	for _, listener := range gateway.Listeners {
+		routes = append(routes, listener.Routes...)
+	}
+
+	columns := NewRouteColumns(routes)
+	rows := []metav1.TableRow{}
+
+	for _, listener := range gateway.Listeners {
+
+		// Listeners without attached routes are printed in a row of their own
+		if len(listener.Routes) == 0 {
+			cells := []interface{}{gateway.Name, ListenerToString(listener), ""}
+			for range columns.Definitions() {
+				cells = append(cells, "")
+			}
+
+			rows = append(rows, metav1.TableRow{Cells: cells})
+			continue
+		}
+
+		for _, httpRoute := range listener.Routes {
+			for _, host := range httpRoute.Hosts {
+				for _, path := range host.Paths {
+					for _, pathCells := range columns.Rows(host, path) {
+
+						cells := []interface{}{gateway.Name, ListenerToString(listener), RouteNameToString(httpRoute, gateway.Namespace)}
+
+						rows = append(rows, metav1.TableRow{Cells: append(cells, pathCells...)})
+					}
+				}
+			}
+		}
+	}
+
+	definitions := append([]metav1.TableColumnDefinition{
+		{Name: "Name", Type: "string"},
+		{Name: "Listener", Type: "string"},
+		{Name: "Route", Type: "string"},
+	}, columns.Definitions()...)
+
+	table := &metav1.Table{
+		ColumnDefinitions: definitions,
+		Rows:              rows,
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	printer.PrintObj(table, out)
+
+	fmt.Fprint(w, out.String())
+
+	return nil
+}
+
+// PrintDocument prints gateway route information in a machine-readable format
+func (g *Gateway) PrintDocument(name string, output string, w io.Writer) error {
+
+	gateway, err := g.Resolver.ResolveGateway(name)
+	if err != nil {
+		return err
+	}
+
+	document := route.NewDocument()
+	document.Gateway = gateway
+
+	return PrintDocument(document, output, w)
+}
+
+// ListenerToString returns the listener name along with its protocol, port and hostname
+func ListenerToString(listener route.Listener) string {
+	details := listener.Protocol + "/" + strconv.FormatInt(int64(listener.Port), 10)
+
+	if listener.Hostname != "" {
+		details += " " + listener.Hostname
+	}
+
+	return listener.Name + " (" + details + ")"
+}
+
+// RouteNameToString returns the route name, prefixed with its namespace
+// when it is not the namespace of the object it is attached to
+func RouteNameToString(r *route.Route, namespace string) string {
+	if r.Namespace != namespace {
+		return r.Namespace + "/" + r.Name
+	}
+
+	return r.Name
+}
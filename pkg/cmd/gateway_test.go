@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// gatewayManifests defines the Gateway API objects returned by the gateway mock client
+var gatewayManifests = []string{`
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: gateway
+  namespace: default
+spec:
+  gatewayClassName: example
+  listeners:
+  - name: http
+    port: 80
+    protocol: HTTP
+  - name: https
+    hostname: "*.foo.com"
+    port: 443
+    protocol: HTTPS
+    allowedRoutes:
+      namespaces:
+        from: All
+  - name: tcp
+    port: 9000
+    protocol: TCP
+`, `
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: route-foo
+  namespace: default
+spec:
+  parentRefs:
+  - name: gateway
+    sectionName: http
+  hostnames:
+  - foo.com
+  rules:
+  - matches:
+    - path:
+        type: PathPrefix
+        value: /api
+      headers:
+      - name: x-canary
+        value: "true"
+      method: GET
+    backendRefs:
+    - name: service-foo
+      port: 80
+      weight: 90
+    - name: service-bar
+      port: 80
+      weight: 10
+  - backendRefs:
+    - name: service-bar
+      port: 80
+`, `
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: route-bar
+  namespace: team
+spec:
+  parentRefs:
+  - name: gateway
+    namespace: default
+  hostnames:
+  - bar.foo.com
+  rules:
+  - backendRefs:
+    - name: service-bar
+      port: 8080
+`}
+
+// NewGatewayMockClient returns a mock client of the Gateway API objects
+func NewGatewayMockClient() *CustomResourceMockClient {
+	return NewCustomResourceMockClient(gatewayManifests...)
+}
+
+func TestGatewayPrintGraphSuccessful(t *testing.T) {
+
+	tests := []struct {
+		gatewayName   string
+		expectedGraph string
+	}{
+		{
+			"gateway",
+			"[Gateway]  gateway\n├── [Listener]  http (HTTP/80)\n│\u00a0\u00a0 └── [HTTPRoute]  route-foo\n│\u00a0\u00a0     └── foo.com\n│\u00a0\u00a0         ├── /api [method=GET,header:x-canary=true]\n│\u00a0\u00a0         │\u00a0\u00a0 ├── [Service]  service-foo (weight 90)\n│\u00a0\u00a0         │\u00a0\u00a0 │\u00a0\u00a0 └── [Pod]  pod-foo-1\n│\u00a0\u00a0         │\u00a0\u00a0 └── [Service]  service-bar (weight 10)\n│\u00a0\u00a0         │\u00a0\u00a0     └── [Pod]  pod-bar-1\n│\u00a0\u00a0         └── /\n│\u00a0\u00a0             └── [Service]  service-bar (weight 1)\n│\u00a0\u00a0                 └── [Pod]  pod-bar-1\n├── [Listener]  https (HTTPS/443 *.foo.com)\n│\u00a0\u00a0 └── [HTTPRoute]  team/route-bar\n│\u00a0\u00a0     └── bar.foo.com\n│\u00a0\u00a0         └── /\n│\u00a0\u00a0             └── [Service]  service-bar (weight 1)\n│\u00a0\u00a0                 └── [Pod]  pod-bar-1\n└── [Listener]  tcp (TCP/9000)\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewGatewayMockClient()
+
+		gateway := NewGateway(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		gateway.PrintGraph(test.gatewayName, buf)
+
+		if buf.String() != test.expectedGraph {
+			t.Errorf("Returned tree graph was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedGraph)
+		}
+	}
+}
+
+func TestGatewayPrintTableSuccessful(t *testing.T) {
+
+	tests := []struct {
+		gatewayName   string
+		expectedTable string
+	}{
+		{
+			"gateway",
+			"NAME      LISTENER                      ROUTE            HOST          PATH   MATCH                             PORT   WEIGHT   SERVICE       TYPE        SERVICE PORT(S)   POD(S)\ngateway   http (HTTP/80)                route-foo        foo.com       /api   method=GET,header:x-canary=true   80     90       service-foo   ClusterIP   80 8080           pod-foo-1\ngateway   http (HTTP/80)                route-foo        foo.com       /api   method=GET,header:x-canary=true   80     10       service-bar   ClusterIP   80 8080           pod-bar-1\ngateway   http (HTTP/80)                route-foo        foo.com       /                                        80     1        service-bar   ClusterIP   80 8080           pod-bar-1\ngateway   https (HTTPS/443 *.foo.com)   team/route-bar   bar.foo.com   /                                        8080   1        service-bar   ClusterIP   80 8080           pod-bar-1\ngateway   tcp (TCP/9000)                                                                                                                                                    \n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewGatewayMockClient()
+
+		gateway := NewGateway(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		gateway.PrintTable(test.gatewayName, buf)
+
+		if buf.String() != test.expectedTable {
+			t.Errorf("Returned table was incorrect,\ngot:\n%swant:\n%s", buf.String(), test.expectedTable)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"io"
+
+	"kube-route-info/pkg/route"
+)
+
+// HTTPProxy defines Contour HTTP proxy attributes
+type HTTPProxy struct {
+	Resolver *route.Resolver
+}
+
+// NewHTTPProxy returns a new HTTPProxy struct
+func NewHTTPProxy(client route.ClientInterface, namespace string) *HTTPProxy {
+	return &HTTPProxy{
+		Resolver: route.NewResolver(client, namespace),
+	}
+}
+
+// ResetCache drops the cluster scoped objects cached by the resolver, so that they are read again
+func (h *HTTPProxy) ResetCache() {
+	h.Resolver.ResetCache()
+}
+
+// PrintGraph prints Contour HTTP proxy route information in a tree graph format
+func (h *HTTPProxy) PrintGraph(name string, w io.Writer) error {
+
+	httpProxy, err := h.Resolver.ResolveHTTPProxy(name)
+	if err != nil {
+		return err
+	}
+
+	printRouteGraph([]*route.Route{httpProxy}, w)
+
+	return nil
+}
+
+// PrintTable prints Contour HTTP proxy route information in table format
+func (h *HTTPProxy) PrintTable(name string, w io.Writer) error {
+
+	httpProxy, err := h.Resolver.ResolveHTTPProxy(name)
+	if err != nil {
+		return err
+	}
+
+	printRouteTable([]*route.Route{httpProxy}, false, w)
+
+	return nil
+}
+
+// PrintDocument prints Contour HTTP proxy route information in a machine-readable format
+func (h *HTTPProxy) PrintDocument(name string, output string, w io.Writer) error {
+
+	httpProxy, err := h.Resolver.ResolveHTTPProxy(name)
+	if err != nil {
+		return err
+	}
+
+	document := route.NewDocument()
+	document.HTTPProxy = httpProxy
+
+	return PrintDocument(document, output, w)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// httpProxyManifests defines the Contour HTTP proxies returned by the HTTP proxy mock client
+var httpProxyManifests = []string{`
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: root
+  namespace: default
+spec:
+  virtualhost:
+    fqdn: foo.com
+  routes:
+  - services:
+    - name: service-foo
+      port: 80
+  includes:
+  - name: api
+    namespace: team
+    conditions:
+    - prefix: /api
+`, `
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: api
+  namespace: team
+spec:
+  routes:
+  - conditions:
+    - header:
+        name: X-Canary
+        exact: "true"
+    requestHeadersPolicy:
+      remove:
+      - X-Debug
+    services:
+    - name: service-bar
+      port: 80
+      weight: 90
+    - name: service-foo
+      port: 80
+      weight: 10
+`}
+
+// NewHTTPProxyMockClient returns a mock client of the Contour HTTP proxies
+func NewHTTPProxyMockClient() *CustomResourceMockClient {
+	return NewCustomResourceMockClient(httpProxyManifests...)
+}
+
+func TestHTTPProxyPrintGraphSuccessful(t *testing.T) {
+
+	tests := []struct {
+		httpProxyName string
+		expectedGraph string
+	}{
+		{
+			"root",
+			"[HTTPProxy]  root\n└── foo.com\n    ├── /\n    │\u00a0\u00a0 └── [Service]  service-foo (weight 1)\n    │\u00a0\u00a0     └── [Pod]  pod-foo-1\n    └── /api [header:X-Canary=true]\n        ├── [Include]  team/api\n        ├── [Filter]  requestHeaders:remove X-Debug\n        ├── [Service]  service-bar (weight 90)\n        │\u00a0\u00a0 └── [Pod]  pod-bar-1\n        └── [Service]  service-foo (weight 10)\n            └── [Pod]  pod-foo-1\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewHTTPProxyMockClient()
+
+		httpProxy := NewHTTPProxy(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		httpProxy.PrintGraph(test.httpProxyName, buf)
+
+		if buf.String() != test.expectedGraph {
+			t.Errorf("Returned tree graph was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedGraph)
+		}
+	}
+}
+
+func TestHTTPProxyPrintTableSuccessful(t *testing.T) {
+
+	tests := []struct {
+		httpProxyName string
+		expectedTable string
+	}{
+		{
+			"root",
+			"NAME   HOST      PATH   MATCH                  PORT   WEIGHT   FILTERS                         INCLUDE    SERVICE       TYPE        SERVICE PORT(S)   POD(S)\nroot   foo.com   /                             80     1                                                   service-foo   ClusterIP   80 8080           pod-foo-1\nroot   foo.com   /api   header:X-Canary=true   80     90       requestHeaders:remove X-Debug   team/api   service-bar   ClusterIP   80 8080           pod-bar-1\nroot   foo.com   /api   header:X-Canary=true   80     10       requestHeaders:remove X-Debug   team/api   service-foo   ClusterIP   80 8080           pod-foo-1\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewHTTPProxyMockClient()
+
+		httpProxy := NewHTTPProxy(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		httpProxy.PrintTable(test.httpProxyName, buf)
+
+		if buf.String() != test.expectedTable {
+			t.Errorf("Returned table was incorrect,\ngot:\n%swant:\n%s", buf.String(), test.expectedTable)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"io"
+
+	"kube-route-info/pkg/route"
+)
+
+// HTTPRoute defines HTTPRoute attributes
+type HTTPRoute struct {
+	Resolver *route.Resolver
+}
+
+// NewHTTPRoute returns a new HTTPRoute struct
+func NewHTTPRoute(client route.ClientInterface, namespace string) *HTTPRoute {
+	return &HTTPRoute{
+		Resolver: route.NewResolver(client, namespace),
+	}
+}
+
+// ResetCache drops the cluster scoped objects cached by the resolver, so that they are read again
+func (h *HTTPRoute) ResetCache() {
+	h.Resolver.ResetCache()
+}
+
+// PrintGraph prints HTTPRoute route information in a tree graph format
+func (h *HTTPRoute) PrintGraph(name string, w io.Writer) error {
+
+	httpRoute, err := h.Resolver.ResolveHTTPRoute(name)
+	if err != nil {
+		return err
+	}
+
+	printRouteGraph([]*route.Route{httpRoute}, w)
+
+	return nil
+}
+
+// PrintTable prints HTTPRoute route information in table format
+func (h *HTTPRoute) PrintTable(name string, w io.Writer) error {
+
+	httpRoute, err := h.Resolver.ResolveHTTPRoute(name)
+	if err != nil {
+		return err
+	}
+
+	printRouteTable([]*route.Route{httpRoute}, false, w)
+
+	return nil
+}
+
+// PrintDocument prints HTTPRoute route information in a machine-readable format
+func (h *HTTPRoute) PrintDocument(name string, output string, w io.Writer) error {
+
+	httpRoute, err := h.Resolver.ResolveHTTPRoute(name)
+	if err != nil {
+		return err
+	}
+
+	document := route.NewDocument()
+	document.HTTPRoute = httpRoute
+
+	return PrintDocument(document, output, w)
+}
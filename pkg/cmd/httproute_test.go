@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHTTPRoutePrintGraphSuccessful(t *testing.T) {
+
+	tests := []struct {
+		httpRouteName string
+		expectedGraph string
+	}{
+		{
+			"route-foo",
+			"[HTTPRoute]  route-foo\n└── foo.com\n    ├── /api [method=GET,header:x-canary=true]\n    │\u00a0\u00a0 ├── [Service]  service-foo (weight 90)\n    │\u00a0\u00a0 │\u00a0\u00a0 └── [Pod]  pod-foo-1\n    │\u00a0\u00a0 └── [Service]  service-bar (weight 10)\n    │\u00a0\u00a0     └── [Pod]  pod-bar-1\n    └── /\n        └── [Service]  service-bar (weight 1)\n            └── [Pod]  pod-bar-1\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewGatewayMockClient()
+
+		httpRoute := NewHTTPRoute(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		httpRoute.PrintGraph(test.httpRouteName, buf)
+
+		if buf.String() != test.expectedGraph {
+			t.Errorf("Returned tree graph was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedGraph)
+		}
+	}
+}
+
+func TestHTTPRoutePrintTableSuccessful(t *testing.T) {
+
+	tests := []struct {
+		httpRouteName string
+		expectedTable string
+	}{
+		{
+			"route-foo",
+			"NAME        HOST      PATH   MATCH                             PORT   WEIGHT   SERVICE       TYPE        SERVICE PORT(S)   POD(S)\nroute-foo   foo.com   /api   method=GET,header:x-canary=true   80     90       service-foo   ClusterIP   80 8080           pod-foo-1\nroute-foo   foo.com   /api   method=GET,header:x-canary=true   80     10       service-bar   ClusterIP   80 8080           pod-bar-1\nroute-foo   foo.com   /                                        80     1        service-bar   ClusterIP   80 8080           pod-bar-1\n",
+		},
+		{
+			"route-bar",
+			"NAME        HOST          PATH   PORT   WEIGHT   SERVICE       TYPE        SERVICE PORT(S)   POD(S)\nroute-bar   bar.foo.com   /      8080   1        service-bar   ClusterIP   80 8080           pod-bar-1\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewGatewayMockClient()
+
+		httpRoute := NewHTTPRoute(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		httpRoute.PrintTable(test.httpRouteName, buf)
+
+		if buf.String() != test.expectedTable {
+			t.Errorf("Returned table was incorrect,\ngot:\n%swant:\n%s", buf.String(), test.expectedTable)
+		}
+	}
+}
+
+func TestHTTPRoutePrintRouteDocumentSuccessful(t *testing.T) {
+
+	tests := []struct {
+		httpRouteName    string
+		expectedDocument string
+	}{
+		{
+			"route-bar",
+			"apiVersion: route-info/v1\nhttpRoute:\n  hosts:\n  - host: bar.foo.com\n    paths:\n    - backendPort: \"8080\"\n      path: /\n      pathType: Prefix\n      service:\n        endpoints:\n        - addresses:\n          - 10.0.0.1\n          pod: pod-bar-1\n          ready: true\n        found: true\n        name: service-bar\n        namespace: default\n        pods:\n        - name: pod-bar-1\n          ready: true\n        ports:\n        - port: 80\n          targetPort: \"8080\"\n        type: ClusterIP\n      weight: 1\n  kind: HTTPRoute\n  name: route-bar\n  namespace: default\nkind: RouteInfo\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewGatewayMockClient()
+
+		httpRoute := NewHTTPRoute(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		httpRoute.PrintDocument(test.httpRouteName, "yaml", buf)
+
+		if buf.String() != test.expectedDocument {
+			t.Errorf("Returned document was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedDocument)
+		}
+	}
+}
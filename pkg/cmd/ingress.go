@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 
+	"kube-route-info/pkg/route"
+
 	"github.com/xlab/treeprint"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -12,148 +14,208 @@ import (
 
 // Ingress defines Ingress atributes
 type Ingress struct {
-	Client    ClientInterface
-	Namespace string
+	Resolver *route.Resolver
 }
 
 // NewIngress returns a new Ingress struct
-func NewIngress(client ClientInterface, namespace string) *Ingress {
+func NewIngress(client route.ClientInterface, namespace string) *Ingress {
 	return &Ingress{
-		Client:    client,
-		Namespace: namespace,
+		Resolver: route.NewResolver(client, namespace),
 	}
 }
 
+// ResetCache drops the cluster scoped objects cached by the resolver, so that they are read again
+func (i *Ingress) ResetCache() {
+	i.Resolver.ResetCache()
+}
+
 // PrintGraph prints ingress route information in a tree graph format
 func (i *Ingress) PrintGraph(name string, w io.Writer) (err error) {
 
-	ingress, err := i.Client.GetIngressByName(name)
+	ingress, err := i.Resolver.ResolveIngress(name)
 	if err != nil {
 		return err
 	}
 
-	tree := treeprint.New()
+	printRouteGraph([]*route.Route{ingress}, w)
 
-	ingressBranch := tree.AddMetaBranch("Ingress", ingress.Name)
+	return
+}
 
-	for _, rule := range ingress.Spec.Rules {
+// PrintTable prints ingress route information in table format
+func (i *Ingress) PrintTable(name string, w io.Writer) (err error) {
 
-		hostBranch := ingressBranch.AddBranch(rule.Host)
+	ingress, err := i.Resolver.ResolveIngress(name)
+	if err != nil {
+		return err
+	}
 
-		for _, ingressRule := range rule.IngressRuleValue.HTTP.Paths {
-			ruleBranch := hostBranch.AddBranch(ingressRule.Path)
+	printRouteTable([]*route.Route{ingress}, false, w)
 
-			// Get service resource
-			service, err := i.Client.GetServiceByName(ingressRule.Backend.ServiceName)
-			if err != nil {
-				ruleBranch.AddMetaBranch("Service", ingressRule.Backend.ServiceName+" *Not found*")
+	return
+}
 
-			} else {
-				serviceBranch := ruleBranch.AddMetaBranch("Service", service.Name)
+// PrintDocument prints ingress route information in a machine-readable format
+func (i *Ingress) PrintDocument(name string, output string, w io.Writer) error {
 
-				// Check service type
-				if ServiceTypeToString(service.Spec.Type) == "ExternalName" {
-					serviceBranch.AddMetaNode("Hostname", service.Spec.ExternalName)
+	ingress, err := i.Resolver.ResolveIngress(name)
+	if err != nil {
+		return err
+	}
 
-				} else {
+	document := route.NewDocument()
+	document.Ingress = ingress
 
-					// Get pod resources
-					pods, err := i.Client.GetPodsByLabels(service.Spec.Selector)
-					if err != nil {
-						return err
-					}
+	return PrintDocument(document, output, w)
+}
 
-					for _, pod := range pods.Items {
-						serviceBranch.AddMetaNode("Pod", pod.Name)
-					}
-				}
-			}
-		}
+// PrintGraphList prints the route information of several ingresses as a forest of tree graphs
+func (i *Ingress) PrintGraphList(names []string, selector string, w io.Writer) error {
+
+	ingresses, err := i.Resolver.ResolveIngresses(names, selector)
+	if err != nil {
+		return err
 	}
 
-	fmt.Fprint(w, ingressBranch.String())
+	printRouteGraph(ingresses, w)
 
-	return
+	return nil
 }
 
-// PrintTable prints ingress route information in table format
-func (i *Ingress) PrintTable(name string, w io.Writer) (err error) {
+// PrintTableList prints the route information of several ingresses in one table
+func (i *Ingress) PrintTableList(names []string, selector string, w io.Writer) error {
+
+	ingresses, err := i.Resolver.ResolveIngresses(names, selector)
+	if err != nil {
+		return err
+	}
 
-	ingress, err := i.Client.GetIngressByName(name)
+	printRouteTable(ingresses, true, w)
+
+	return nil
+}
+
+// PrintDocumentList prints the route information of several ingresses in a machine-readable format
+func (i *Ingress) PrintDocumentList(names []string, selector string, output string, w io.Writer) error {
+
+	ingresses, err := i.Resolver.ResolveIngresses(names, selector)
 	if err != nil {
-		return nil
+		return err
+	}
+
+	document := route.NewDocument()
+	document.Ingresses = ingresses
+
+	return PrintDocument(document, output, w)
+}
+
+// printRouteGraph prints the route information of ingresses or HTTPRoutes as a forest of tree graphs
+func printRouteGraph(routes []*route.Route, w io.Writer) {
+
+	if len(routes) == 0 {
+		fmt.Fprintln(w, "No resources found")
+		return
 	}
 
+	for index, r := range routes {
+
+		// Trees of a forest are separated by a blank line
+		if index > 0 {
+			fmt.Fprintln(w)
+		}
+
+		tree := treeprint.New()
+
+		routeBranch := tree.AddMetaBranch(RouteKindToString(r), r.Name)
+
+		if r.IngressClass != nil {
+			addIngressClassBranch(routeBranch, r.IngressClass)
+		}
+
+		addExposureNodes(routeBranch, r.Exposure)
+
+		AddHostBranches(routeBranch, r)
+
+		fmt.Fprint(w, routeBranch.String())
+	}
+}
+
+// printRouteTable prints the route information of ingresses or HTTPRoutes in one table.
+// Match and weight columns are only printed when a path sets them, the class
+// column when an ingress has a class and the address column when an ingress
+// has a load balancer address
+func printRouteTable(routes []*route.Route, withNamespace bool, w io.Writer) {
+
+	if len(routes) == 0 {
+		fmt.Fprintln(w, "No resources found")
+		return
+	}
+
+	columns := NewRouteColumns(routes)
 	rows := []metav1.TableRow{}
 
-	// Default column name for pods
-	podColumnName := "Pod(s)"
-
-	for _, rule := range ingress.Spec.Rules {
-		for _, ingressRule := range rule.IngressRuleValue.HTTP.Paths {
-
-			// If service does not exist
-			serviceName := ingressRule.Backend.ServiceName + " *Not found*"
-			serviceType := ""
-			servicePorts := ""
-			servicePodsHostname := ""
-
-			// Get service resource
-			service, err := i.Client.GetServiceByName(ingressRule.Backend.ServiceName)
-
-			// If service does exist
-			if err == nil {
-				serviceName = service.Name
-				serviceType = ServiceTypeToString(service.Spec.Type)
-				servicePorts = PortsToString(service.Spec.Ports)
-
-				// Check service type
-				if ServiceTypeToString(service.Spec.Type) == "ExternalName" {
-					servicePodsHostname = service.Spec.ExternalName
-					podColumnName = "Pod(s)/Hostname"
-
-				} else {
-					// Get pod resources
-					pods, err := i.Client.GetPodsByLabels(service.Spec.Selector)
-					if err != nil {
-						return nil
+	withClass := false
+	withAddress := false
+	for _, r := range routes {
+		if r.IngressClass != nil {
+			withClass = true
+		}
+
+		if r.Exposure != nil {
+			withAddress = true
+		}
+	}
+
+	for _, r := range routes {
+		for _, host := range TableHosts(r) {
+
+			paths := host.Paths
+			if len(paths) == 0 {
+				paths = []route.Path{{}}
+			}
+
+			for _, path := range paths {
+				for _, cells := range columns.Rows(host, path) {
+
+					if withAddress {
+						cells = append([]interface{}{LoadBalancerToString(r.Exposure)}, cells...)
+					}
+
+					if withClass {
+						cells = append([]interface{}{IngressClassToString(r.IngressClass)}, cells...)
 					}
 
-					servicePodsHostname = PodsToString(pods)
+					cells = append([]interface{}{r.Name}, cells...)
+
+					if withNamespace {
+						cells = append([]interface{}{r.Namespace}, cells...)
+					}
+
+					rows = append(rows, metav1.TableRow{Cells: cells})
 				}
 			}
-
-			rows = append(rows, metav1.TableRow{
-				Cells: []interface{}{
-					ingress.Name,
-					rule.Host,
-					ingressRule.Path,
-					PortToString(
-						ingressRule.Backend.ServicePort.Type,
-						ingressRule.Backend.ServicePort.StrVal,
-						ingressRule.Backend.ServicePort.IntVal,
-					),
-					serviceName,
-					serviceType,
-					servicePorts,
-					servicePodsHostname,
-				},
-			})
 		}
 	}
 
+	definitions := columns.Definitions()
+
+	if withAddress {
+		definitions = append([]metav1.TableColumnDefinition{{Name: "Address", Type: "string"}}, definitions...)
+	}
+
+	if withClass {
+		definitions = append([]metav1.TableColumnDefinition{{Name: "Class", Type: "string"}}, definitions...)
+	}
+
+	definitions = append([]metav1.TableColumnDefinition{{Name: "Name", Type: "string"}}, definitions...)
+
+	if withNamespace {
+		definitions = append([]metav1.TableColumnDefinition{{Name: "Namespace", Type: "string"}}, definitions...)
+	}
+
 	table := &metav1.Table{
-		ColumnDefinitions: []metav1.TableColumnDefinition{
-			{Name: "Name", Type: "string"},
-			{Name: "Host", Type: "string"},
-			{Name: "Path", Type: "string"},
-			{Name: "Port", Type: "string"},
-			{Name: "Service", Type: "string"},
-			{Name: "Type", Type: "string"},
-			{Name: "Service Port(s)", Type: "string"},
-			{Name: podColumnName, Type: "string"},
-		},
-		Rows: rows,
+		ColumnDefinitions: definitions,
+		Rows:              rows,
 	}
 
 	out := bytes.NewBuffer([]byte{})
@@ -161,6 +223,4 @@ func (i *Ingress) PrintTable(name string, w io.Writer) (err error) {
 	printer.PrintObj(table, out)
 
 	fmt.Fprint(w, out.String())
-
-	return
 }
@@ -2,18 +2,35 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
+
+	"kube-route-info/pkg/route"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/networking/v1beta1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 )
 
 // Mock client struct
-type IngressMockClient struct{}
+type IngressMockClient struct {
+	BaseMockClient
+}
 
 func NewIngressMockClient() *IngressMockClient {
 	return &IngressMockClient{}
@@ -30,6 +47,11 @@ func (c *IngressMockClient) GetPodsByLabels(labels map[string]string) (*v1.PodLi
 					"app": "foo",
 				},
 			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "app", Ports: []v1.ContainerPort{{Name: "https", ContainerPort: 8443}}},
+				},
+			},
 		},
 		{
 			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
@@ -40,6 +62,11 @@ func (c *IngressMockClient) GetPodsByLabels(labels map[string]string) (*v1.PodLi
 					"app": "foo",
 				},
 			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "app", Ports: []v1.ContainerPort{{Name: "https", ContainerPort: 8443}}},
+				},
+			},
 		},
 		{
 			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
@@ -50,6 +77,11 @@ func (c *IngressMockClient) GetPodsByLabels(labels map[string]string) (*v1.PodLi
 					"app": "bar",
 				},
 			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "app", Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}}},
+				},
+			},
 		},
 	}
 
@@ -123,28 +155,31 @@ func (c *IngressMockClient) GetServiceByName(name string) (*v1.Service, error) {
 	return nil, nil
 }
 
-func (c *IngressMockClient) GetIngressByName(name string) (*v1beta1.Ingress, error) {
-	ingressList := []v1beta1.Ingress{
+func (c *IngressMockClient) GetIngressByName(name string) (*networkingv1.Ingress, error) {
+	storageAPIGroup := "k8s.example.com"
+
+	ingressList := []networkingv1.Ingress{
 		{
-			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress"},
+			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "ingress-1-backend",
 				Namespace: "default",
 			},
-			Spec: v1beta1.IngressSpec{
-				Rules: []v1beta1.IngressRule{
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
 					{
 						Host: "v1.ingress.com",
-						IngressRuleValue: v1beta1.IngressRuleValue{
-							HTTP: &v1beta1.HTTPIngressRuleValue{
-								Paths: []v1beta1.HTTPIngressPath{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
 									{
 										Path: "",
-										Backend: v1beta1.IngressBackend{
-											ServiceName: "service-foo",
-											ServicePort: intstr.IntOrString{
-												Type:   0,
-												IntVal: 80,
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "service-foo",
+												Port: networkingv1.ServiceBackendPort{
+													Number: 80,
+												},
 											},
 										},
 									},
@@ -156,35 +191,37 @@ func (c *IngressMockClient) GetIngressByName(name string) (*v1beta1.Ingress, err
 			},
 		},
 		{
-			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress"},
+			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "ingress-2-backends",
 				Namespace: "default",
 			},
-			Spec: v1beta1.IngressSpec{
-				Rules: []v1beta1.IngressRule{
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
 					{
 						Host: "",
-						IngressRuleValue: v1beta1.IngressRuleValue{
-							HTTP: &v1beta1.HTTPIngressRuleValue{
-								Paths: []v1beta1.HTTPIngressPath{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
 									{
 										Path: "/foo",
-										Backend: v1beta1.IngressBackend{
-											ServiceName: "service-foo",
-											ServicePort: intstr.IntOrString{
-												Type:   0,
-												IntVal: 80,
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "service-foo",
+												Port: networkingv1.ServiceBackendPort{
+													Number: 80,
+												},
 											},
 										},
 									},
 									{
 										Path: "/bar",
-										Backend: v1beta1.IngressBackend{
-											ServiceName: "service-bar",
-											ServicePort: intstr.IntOrString{
-												Type:   0,
-												IntVal: 80,
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "service-bar",
+												Port: networkingv1.ServiceBackendPort{
+													Number: 80,
+												},
 											},
 										},
 									},
@@ -195,35 +232,37 @@ func (c *IngressMockClient) GetIngressByName(name string) (*v1beta1.Ingress, err
 				},
 			},
 		}, {
-			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress"},
+			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "ingress-2-backends-2-rules",
 				Namespace: "default",
 			},
-			Spec: v1beta1.IngressSpec{
-				Rules: []v1beta1.IngressRule{
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
 					{
 						Host: "1.rule.com",
-						IngressRuleValue: v1beta1.IngressRuleValue{
-							HTTP: &v1beta1.HTTPIngressRuleValue{
-								Paths: []v1beta1.HTTPIngressPath{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
 									{
 										Path: "/foo",
-										Backend: v1beta1.IngressBackend{
-											ServiceName: "service-foo",
-											ServicePort: intstr.IntOrString{
-												Type:   0,
-												IntVal: 80,
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "service-foo",
+												Port: networkingv1.ServiceBackendPort{
+													Number: 80,
+												},
 											},
 										},
 									},
 									{
 										Path: "/bar",
-										Backend: v1beta1.IngressBackend{
-											ServiceName: "service-bar",
-											ServicePort: intstr.IntOrString{
-												Type:   0,
-												IntVal: 80,
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "service-bar",
+												Port: networkingv1.ServiceBackendPort{
+													Number: 80,
+												},
 											},
 										},
 									},
@@ -233,16 +272,17 @@ func (c *IngressMockClient) GetIngressByName(name string) (*v1beta1.Ingress, err
 					},
 					{
 						Host: "2.rule.com",
-						IngressRuleValue: v1beta1.IngressRuleValue{
-							HTTP: &v1beta1.HTTPIngressRuleValue{
-								Paths: []v1beta1.HTTPIngressPath{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
 									{
 										Path: "/externalname",
-										Backend: v1beta1.IngressBackend{
-											ServiceName: "service-externalname",
-											ServicePort: intstr.IntOrString{
-												Type:   0,
-												IntVal: 80,
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "service-externalname",
+												Port: networkingv1.ServiceBackendPort{
+													Number: 80,
+												},
 											},
 										},
 									},
@@ -255,6 +295,127 @@ func (c *IngressMockClient) GetIngressByName(name string) (*v1beta1.Ingress, err
 		},
 	}
 
+	ingressList = append(ingressList, networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-tls",
+			Namespace: "default",
+		},
+		Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{
+				{Hosts: []string{"secure.ingress.com"}, SecretName: "secure-tls"},
+			},
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "secure.ingress.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path: "/bar",
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "service-bar",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Host: "plain.ingress.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path: "/bar",
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "service-bar",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	ingressList = append(ingressList, networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-default-backend",
+			Namespace: "default",
+		},
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: "service-foo",
+					Port: networkingv1.ServiceBackendPort{Number: 80},
+				},
+			},
+			Rules: []networkingv1.IngressRule{
+				{Host: "foo.ingress.com"},
+				{
+					Host: "static.ingress.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path: "/assets",
+									Backend: networkingv1.IngressBackend{
+										Resource: &v1.TypedLocalObjectReference{
+											APIGroup: &storageAPIGroup,
+											Kind:     "StorageBucket",
+											Name:     "static-assets",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// Legacy ingress converted the same way the client does on older clusters
+	ingressList = append(ingressList, *route.IngressFromNetworkingV1beta1(&networkingv1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-v1beta1",
+			Namespace: "default",
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{
+				{
+					Host: "v1beta1.ingress.com",
+					IngressRuleValue: networkingv1beta1.IngressRuleValue{
+						HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+							Paths: []networkingv1beta1.HTTPIngressPath{
+								{
+									Path: "/bar",
+									Backend: networkingv1beta1.IngressBackend{
+										ServiceName: "service-bar",
+										ServicePort: intstr.IntOrString{
+											Type:   1,
+											StrVal: "http",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}))
+
 	for _, ingress := range ingressList {
 		if ingress.Name == name {
 			return &ingress, nil
@@ -264,6 +425,53 @@ func (c *IngressMockClient) GetIngressByName(name string) (*v1beta1.Ingress, err
 	return nil, nil
 }
 
+func (c *IngressMockClient) ListIngresses(selector string) (*networkingv1.IngressList, error) {
+	if selector == "app=missing" {
+		return &networkingv1.IngressList{}, nil
+	}
+
+	ingress2Backends, _ := c.GetIngressByName("ingress-2-backends")
+	ingress1Backend, _ := c.GetIngressByName("ingress-1-backend")
+	ingress1Backend.Namespace = "other"
+
+	return &networkingv1.IngressList{
+		Items: []networkingv1.Ingress{*ingress1Backend, *ingress2Backends},
+	}, nil
+}
+
+func (c *IngressMockClient) GetEndpointSlicesByService(name string) (*discoveryv1.EndpointSliceList, error) {
+	service, _ := c.GetServiceByName(name)
+	pods, _ := c.GetPodsByLabels(service.Spec.Selector)
+	return newEndpointSlices(pods), nil
+}
+
+func (c *IngressMockClient) GetSecretByName(name string) (*v1.Secret, error) {
+	if name != "secure-tls" {
+		return nil, nil
+	}
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "secure.ingress.com"},
+		DNSNames:     []string{"secure.ingress.com"},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	certificate, _ := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	return &v1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Type:       v1.SecretTypeTLS,
+		Data:       map[string][]byte{v1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate})},
+	}, nil
+}
+
+func (c *IngressMockClient) Namespaced(namespace string) route.ClientInterface { return c }
+
 func TestIngressPrintGraphSuccessful(t *testing.T) {
 
 	tests := []struct {
@@ -272,15 +480,23 @@ func TestIngressPrintGraphSuccessful(t *testing.T) {
 	}{
 		{
 			"ingress-1-backend",
-			"[Ingress]  ingress-1-backend\n└── v1.ingress.com\n    └── \n        └── [Service]  service-foo\n            ├── [Pod]  pod-foo-1\n            └── [Pod]  pod-foo-2\n",
+			"[Ingress]  ingress-1-backend\n└── v1.ingress.com\n    └── \n        └── [Service]  service-foo\n            ├── [Pod]  pod-foo-1 (https=app:8443)\n            └── [Pod]  pod-foo-2 (https=app:8443)\n",
 		},
 		{
 			"ingress-2-backends",
-			"[Ingress]  ingress-2-backends\n└── \n    ├── /foo\n    │   └── [Service]  service-foo\n    │       ├── [Pod]  pod-foo-1\n    │       └── [Pod]  pod-foo-2\n    └── /bar\n        └── [Service]  service-bar\n            └── [Pod]  pod-bar-1\n",
+			"[Ingress]  ingress-2-backends\n└── \n    ├── /foo\n    │\u00a0\u00a0 └── [Service]  service-foo\n    │\u00a0\u00a0     ├── [Pod]  pod-foo-1 (https=app:8443)\n    │\u00a0\u00a0     └── [Pod]  pod-foo-2 (https=app:8443)\n    └── /bar\n        └── [Service]  service-bar\n            └── [Pod]  pod-bar-1 (http=app:8080)\n",
 		},
 		{
 			"ingress-2-backends-2-rules",
-			"[Ingress]  ingress-2-backends-2-rules\n├── 1.rule.com\n│   ├── /foo\n│   │   └── [Service]  service-foo\n│   │       ├── [Pod]  pod-foo-1\n│   │       └── [Pod]  pod-foo-2\n│   └── /bar\n│       └── [Service]  service-bar\n│           └── [Pod]  pod-bar-1\n└── 2.rule.com\n    └── /externalname\n        └── [Service]  service-externalname\n            └── [Hostname]  my.external.app.com\n",
+			"[Ingress]  ingress-2-backends-2-rules\n├── 1.rule.com\n│\u00a0\u00a0 ├── /foo\n│\u00a0\u00a0 │\u00a0\u00a0 └── [Service]  service-foo\n│\u00a0\u00a0 │\u00a0\u00a0     ├── [Pod]  pod-foo-1 (https=app:8443)\n│\u00a0\u00a0 │\u00a0\u00a0     └── [Pod]  pod-foo-2 (https=app:8443)\n│\u00a0\u00a0 └── /bar\n│\u00a0\u00a0     └── [Service]  service-bar\n│\u00a0\u00a0         └── [Pod]  pod-bar-1 (http=app:8080)\n└── 2.rule.com\n    └── /externalname\n        └── [Service]  service-externalname\n            └── [Hostname]  my.external.app.com\n",
+		},
+		{
+			"ingress-tls",
+			"[Ingress]  ingress-tls\n├── secure.ingress.com\n│\u00a0\u00a0 ├── [TLS]  secure-tls\n│\u00a0\u00a0 │\u00a0\u00a0 ├── [Subject]  CN=secure.ingress.com\n│\u00a0\u00a0 │\u00a0\u00a0 ├── [SANs]  secure.ingress.com\n│\u00a0\u00a0 │\u00a0\u00a0 ├── [Issuer]  CN=secure.ingress.com\n│\u00a0\u00a0 │\u00a0\u00a0 └── [Expires]  2099-01-01T00:00:00Z\n│\u00a0\u00a0 └── /bar\n│\u00a0\u00a0     └── [Service]  service-bar\n│\u00a0\u00a0         └── [Pod]  pod-bar-1 (http=app:8080)\n└── plain.ingress.com\n    ├── [Warning]  host plain.ingress.com is not covered by any TLS entry\n    └── /bar\n        └── [Service]  service-bar\n            └── [Pod]  pod-bar-1 (http=app:8080)\n",
+		},
+		{
+			"ingress-default-backend",
+			"[Ingress]  ingress-default-backend\n├── foo.ingress.com\n├── static.ingress.com\n│\u00a0\u00a0 └── /assets\n│\u00a0\u00a0     └── [Resource]  k8s.example.com/StorageBucket/static-assets\n└── [Default backend]\n    └── [Service]  service-foo\n        ├── [Pod]  pod-foo-1 (https=app:8443)\n        └── [Pod]  pod-foo-2 (https=app:8443)\n",
 		},
 	}
 
@@ -308,15 +524,27 @@ func TestIngressPrintTableSuccessful(t *testing.T) {
 	}{
 		{
 			"ingress-1-backend",
-			"NAME                HOST             PATH   PORT   SERVICE       TYPE        SERVICE PORT(S)   POD(S)\ningress-1-backend   v1.ingress.com          80     service-foo   ClusterIP   80 80,443 https   pod-foo-1,pod-foo-2\n",
+			"NAME                HOST             PATH   PORT   SERVICE       TYPE        SERVICE PORT(S)        POD(S)\ningress-1-backend   v1.ingress.com          80     service-foo   ClusterIP   80 80,443 https=8443   pod-foo-1,pod-foo-2\n",
 		},
 		{
 			"ingress-2-backends",
-			"NAME                 HOST   PATH   PORT   SERVICE       TYPE        SERVICE PORT(S)   POD(S)\ningress-2-backends          /foo   80     service-foo   ClusterIP   80 80,443 https   pod-foo-1,pod-foo-2\ningress-2-backends          /bar   80     service-bar   ClusterIP   80 http           pod-bar-1\n",
+			"NAME                 HOST   PATH   PORT   SERVICE       TYPE        SERVICE PORT(S)        POD(S)\ningress-2-backends          /foo   80     service-foo   ClusterIP   80 80,443 https=8443   pod-foo-1,pod-foo-2\ningress-2-backends          /bar   80     service-bar   ClusterIP   80 http=8080           pod-bar-1\n",
 		},
 		{
 			"ingress-2-backends-2-rules",
-			"NAME                         HOST         PATH            PORT   SERVICE                TYPE           SERVICE PORT(S)   POD(S)/HOSTNAME\ningress-2-backends-2-rules   1.rule.com   /foo            80     service-foo            ClusterIP      80 80,443 https   pod-foo-1,pod-foo-2\ningress-2-backends-2-rules   1.rule.com   /bar            80     service-bar            ClusterIP      80 http           pod-bar-1\ningress-2-backends-2-rules   2.rule.com   /externalname   80     service-externalname   ExternalName                     my.external.app.com\n",
+			"NAME                         HOST         PATH            PORT   SERVICE                TYPE           SERVICE PORT(S)        POD(S)/HOSTNAME\ningress-2-backends-2-rules   1.rule.com   /foo            80     service-foo            ClusterIP      80 80,443 https=8443   pod-foo-1,pod-foo-2\ningress-2-backends-2-rules   1.rule.com   /bar            80     service-bar            ClusterIP      80 http=8080           pod-bar-1\ningress-2-backends-2-rules   2.rule.com   /externalname   80     service-externalname   ExternalName                          my.external.app.com\n",
+		},
+		{
+			"ingress-v1beta1",
+			"NAME              HOST                  PATH   PORT   SERVICE       TYPE        SERVICE PORT(S)   POD(S)\ningress-v1beta1   v1beta1.ingress.com   /bar   http   service-bar   ClusterIP   80 http=8080      pod-bar-1\n",
+		},
+		{
+			"ingress-tls",
+			"NAME          HOST                 TLS                               PATH   PORT   SERVICE       TYPE        SERVICE PORT(S)   POD(S)      WARNINGS\ningress-tls   secure.ingress.com   secure-tls (expires 2099-01-01)   /bar   80     service-bar   ClusterIP   80 http=8080      pod-bar-1   \ningress-tls   plain.ingress.com                                      /bar   80     service-bar   ClusterIP   80 http=8080      pod-bar-1   host plain.ingress.com is not covered by any TLS entry\n",
+		},
+		{
+			"ingress-default-backend",
+			"NAME                      HOST                 PATH                PORT   SERVICE                                       TYPE        SERVICE PORT(S)        POD(S)\ningress-default-backend   foo.ingress.com                                                                                                                  \ningress-default-backend   static.ingress.com   /assets                    k8s.example.com/StorageBucket/static-assets   Resource                           \ningress-default-backend   *                    (default backend)   80     service-foo                                   ClusterIP   80 80,443 https=8443   pod-foo-1,pod-foo-2\n",
 		},
 	}
 
@@ -335,3 +563,386 @@ func TestIngressPrintTableSuccessful(t *testing.T) {
 		}
 	}
 }
+
+func TestIngressPrintRouteDocumentSuccessful(t *testing.T) {
+
+	tests := []struct {
+		ingressName      string
+		output           string
+		expectedDocument string
+	}{
+		{
+			"ingress-1-backend",
+			"json",
+			"{\n    \"apiVersion\": \"route-info/v1\",\n    \"kind\": \"RouteInfo\",\n    \"ingress\": {\n        \"name\": \"ingress-1-backend\",\n        \"namespace\": \"default\",\n        \"hosts\": [\n            {\n                \"host\": \"v1.ingress.com\",\n                \"paths\": [\n                    {\n                        \"path\": \"\",\n                        \"backendPort\": \"80\",\n                        \"service\": {\n                            \"name\": \"service-foo\",\n                            \"namespace\": \"default\",\n                            \"found\": true,\n                            \"type\": \"ClusterIP\",\n                            \"ports\": [\n                                {\n                                    \"port\": 80,\n                                    \"targetPort\": \"80\"\n                                },\n                                {\n                                    \"port\": 443,\n                                    \"targetPort\": \"https\",\n                                    \"targetPorts\": [\n                                        {\n                                            \"pod\": \"pod-foo-1\",\n                                            \"found\": true,\n                                            \"container\": \"app\",\n                                            \"port\": 8443\n                                        },\n                                        {\n                                            \"pod\": \"pod-foo-2\",\n                                            \"found\": true,\n                                            \"container\": \"app\",\n                                            \"port\": 8443\n                                        }\n                                    ]\n                                }\n                            ],\n                            \"pods\": [\n                                {\n                                    \"name\": \"pod-foo-1\",\n                                    \"ready\": true\n                                },\n                                {\n                                    \"name\": \"pod-foo-2\",\n                                    \"ready\": true\n                                }\n                            ],\n                            \"endpoints\": [\n                                {\n                                    \"addresses\": [\n                                        \"10.0.0.1\"\n                                    ],\n                                    \"ready\": true,\n                                    \"pod\": \"pod-foo-1\"\n                                },\n                                {\n                                    \"addresses\": [\n                                        \"10.0.0.2\"\n                                    ],\n                                    \"ready\": true,\n                                    \"pod\": \"pod-foo-2\"\n                                }\n                            ]\n                        }\n                    }\n                ]\n            }\n        ]\n    }\n}\n",
+		},
+		{
+			"ingress-v1beta1",
+			"yaml",
+			"apiVersion: route-info/v1\ningress:\n  hosts:\n  - host: v1beta1.ingress.com\n    paths:\n    - backendPort: http\n      path: /bar\n      service:\n        endpoints:\n        - addresses:\n          - 10.0.0.1\n          pod: pod-bar-1\n          ready: true\n        found: true\n        name: service-bar\n        namespace: default\n        pods:\n        - name: pod-bar-1\n          ready: true\n        ports:\n        - port: 80\n          targetPort: http\n          targetPorts:\n          - container: app\n            found: true\n            pod: pod-bar-1\n            port: 8080\n        type: ClusterIP\n  name: ingress-v1beta1\n  namespace: default\nkind: RouteInfo\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewIngressMockClient()
+
+		ingress := NewIngress(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		ingress.PrintDocument(test.ingressName, test.output, buf)
+
+		if buf.String() != test.expectedDocument {
+			t.Errorf("Returned document was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedDocument)
+		}
+	}
+}
+
+func TestIngressPrintTableListSuccessful(t *testing.T) {
+
+	tests := []struct {
+		ingressNames  []string
+		selector      string
+		expectedTable string
+	}{
+		{
+			[]string{"ingress-1-backend", "ingress-v1beta1"},
+			"",
+			"NAMESPACE   NAME                HOST                  PATH   PORT   SERVICE       TYPE        SERVICE PORT(S)        POD(S)\ndefault     ingress-1-backend   v1.ingress.com               80     service-foo   ClusterIP   80 80,443 https=8443   pod-foo-1,pod-foo-2\ndefault     ingress-v1beta1     v1beta1.ingress.com   /bar   http   service-bar   ClusterIP   80 http=8080           pod-bar-1\n",
+		},
+		{
+			nil,
+			"app=foo",
+			"NAMESPACE   NAME                 HOST             PATH   PORT   SERVICE       TYPE        SERVICE PORT(S)        POD(S)\ndefault     ingress-2-backends                    /foo   80     service-foo   ClusterIP   80 80,443 https=8443   pod-foo-1,pod-foo-2\ndefault     ingress-2-backends                    /bar   80     service-bar   ClusterIP   80 http=8080           pod-bar-1\nother       ingress-1-backend    v1.ingress.com          80     service-foo   ClusterIP   80 80,443 https=8443   pod-foo-1,pod-foo-2\n",
+		},
+		{
+			nil,
+			"app=missing",
+			"No resources found\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewIngressMockClient()
+
+		ingress := NewIngress(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		ingress.PrintTableList(test.ingressNames, test.selector, buf)
+
+		if buf.String() != test.expectedTable {
+			t.Errorf("Returned table was incorrect,\ngot:\n%swant:\n%s", buf.String(), test.expectedTable)
+		}
+	}
+}
+
+func TestIngressPrintGraphListSuccessful(t *testing.T) {
+
+	tests := []struct {
+		ingressNames  []string
+		selector      string
+		expectedGraph string
+	}{
+		{
+			nil,
+			"",
+			"[Ingress]  ingress-2-backends\n└── \n    ├── /foo\n    │\u00a0\u00a0 └── [Service]  service-foo\n    │\u00a0\u00a0     ├── [Pod]  pod-foo-1 (https=app:8443)\n    │\u00a0\u00a0     └── [Pod]  pod-foo-2 (https=app:8443)\n    └── /bar\n        └── [Service]  service-bar\n            └── [Pod]  pod-bar-1 (http=app:8080)\n\n[Ingress]  ingress-1-backend\n└── v1.ingress.com\n    └── \n        └── [Service]  service-foo\n            ├── [Pod]  pod-foo-1 (https=app:8443)\n            └── [Pod]  pod-foo-2 (https=app:8443)\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewIngressMockClient()
+
+		ingress := NewIngress(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		ingress.PrintGraphList(test.ingressNames, test.selector, buf)
+
+		if buf.String() != test.expectedGraph {
+			t.Errorf("Returned tree graph was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedGraph)
+		}
+	}
+}
+
+const ingressClassManifests = `apiVersion: networking.k8s.io/v1
+kind: IngressClass
+metadata:
+  name: nginx
+  annotations:
+    ingressclass.kubernetes.io/is-default-class: "true"
+spec:
+  controller: k8s.io/ingress-nginx
+---
+apiVersion: networking.k8s.io/v1
+kind: IngressClass
+metadata:
+  name: traefik
+spec:
+  controller: traefik.io/ingress-controller
+  parameters:
+    apiGroup: k8s.example.com
+    kind: IngressParameters
+    name: external-lb
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ingress-nginx-controller
+  namespace: ingress-nginx
+spec:
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: ingress-nginx
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: ingress-nginx
+    spec:
+      containers:
+      - name: controller
+        image: ingress-nginx
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ingress-default-class
+spec:
+  rules:
+  - host: default.ingress.com
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ingress-traefik
+spec:
+  ingressClassName: traefik
+  rules:
+  - host: traefik.ingress.com
+`
+
+// newManifestIngress returns an Ingress struct that resolves the objects of the given manifests
+func newManifestIngress(t *testing.T, manifests string) *Ingress {
+	filename := filepath.Join(t.TempDir(), "manifests.yaml")
+	if err := ioutil.WriteFile(filename, []byte(manifests), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	objects, err := LoadManifests([]string{filename}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client, err := route.NewMemoryClient("default", objects...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return NewIngress(client, "default")
+}
+
+func TestIngressPrintIngressClassSuccessful(t *testing.T) {
+
+	ingress := newManifestIngress(t, ingressClassManifests)
+
+	tests := []struct {
+		name     string
+		print    func(w io.Writer) error
+		expected string
+	}{
+		{
+			"graph",
+			func(w io.Writer) error { return ingress.PrintGraphList(nil, "", w) },
+			"[Ingress]  ingress-default-class\n├── [IngressClass]  nginx (default)\n│\u00a0\u00a0 └── [Controller]  k8s.io/ingress-nginx\n└── default.ingress.com\n\n[Ingress]  ingress-traefik\n├── [IngressClass]  traefik\n│\u00a0\u00a0 ├── [Controller]  traefik.io/ingress-controller\n│\u00a0\u00a0 ├── [Parameters]  k8s.example.com/IngressParameters/external-lb\n│\u00a0\u00a0 └── [Warning]  no running pods found for the controller traefik.io/ingress-controller of IngressClass traefik\n└── traefik.ingress.com\n",
+		},
+		{
+			"table",
+			func(w io.Writer) error { return ingress.PrintTableList(nil, "", w) },
+			"NAMESPACE   NAME                    CLASS             HOST                  PATH   PORT   SERVICE   TYPE   SERVICE PORT(S)   POD(S)\ndefault     ingress-default-class   nginx (default)   default.ingress.com                                                    \ndefault     ingress-traefik         traefik           traefik.ingress.com                                                    \n",
+		},
+	}
+
+	for _, test := range tests {
+
+		buf := &bytes.Buffer{}
+
+		if err := test.print(buf); err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		if buf.String() != test.expected {
+			t.Errorf("Returned %s was incorrect, got: %q, want: %q", test.name, buf.String(), test.expected)
+		}
+	}
+}
+
+const ingressBehaviorManifests = `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ingress-behavior
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /$2
+    nginx.ingress.kubernetes.io/ssl-redirect: "false"
+    nginx.ingress.kubernetes.io/backend-protocol: HTTPS
+    nginx.ingress.kubernetes.io/auth-url: https://auth.example.com/oauth2/auth
+    nginx.ingress.kubernetes.io/limit-rps: "10"
+    nginx.ingress.kubernetes.io/proxy-read-timeout: "120"
+    nginx.ingress.kubernetes.io/whitelist-source-range: 10.0.0.0/8
+spec:
+  rules:
+  - host: behavior.ingress.com
+    http:
+      paths:
+      - path: /api(/|$)(.*)
+        pathType: ImplementationSpecific
+        backend:
+          service:
+            name: api
+            port:
+              number: 443
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ingress-canary
+  annotations:
+    nginx.ingress.kubernetes.io/canary: "true"
+    nginx.ingress.kubernetes.io/canary-weight: "20"
+    nginx.ingress.kubernetes.io/canary-by-header: X-Canary
+spec:
+  rules:
+  - host: behavior.ingress.com
+    http:
+      paths:
+      - path: /api(/|$)(.*)
+        pathType: ImplementationSpecific
+        backend:
+          service:
+            name: api-canary
+            port:
+              number: 443
+`
+
+func TestIngressPrintBehaviorSuccessful(t *testing.T) {
+
+	ingress := newManifestIngress(t, ingressBehaviorManifests)
+
+	tests := []struct {
+		name     string
+		print    func(w io.Writer) error
+		expected string
+	}{
+		{
+			"graph",
+			func(w io.Writer) error { return ingress.PrintGraphList(nil, "", w) },
+			"[Ingress]  ingress-behavior\n└── behavior.ingress.com\n    └── /api(/|$)(.*)\n        ├── [Behavior]\n        │\u00a0\u00a0 ├── [Rewrite]  /api(/|$)(.*) -> /$2 ($2 = (.*))\n        │\u00a0\u00a0 ├── [SSL redirect]  false\n        │\u00a0\u00a0 ├── [Backend protocol]  HTTPS\n        │\u00a0\u00a0 ├── [Auth]  external https://auth.example.com/oauth2/auth\n        │\u00a0\u00a0 ├── [Rate limit]  10 rps\n        │\u00a0\u00a0 ├── [Timeouts]  read 120s\n        │\u00a0\u00a0 └── [Allowed sources]  10.0.0.0/8\n        └── [Service]  api *Not found*\n\n[Ingress]  ingress-canary\n└── behavior.ingress.com\n    └── /api(/|$)(.*)\n        ├── [Behavior]\n        │\u00a0\u00a0 └── [Canary]  weight 20/100, header X-Canary\n        └── [Service]  api-canary *Not found*\n",
+		},
+		{
+			"table",
+			func(w io.Writer) error { return ingress.PrintTableList(nil, "", w) },
+			"NAMESPACE   NAME               HOST                   PATH            PORT   BEHAVIOR                                                                                                                                                                                                             SERVICE                  TYPE   SERVICE PORT(S)   POD(S)\ndefault     ingress-behavior   behavior.ingress.com   /api(/|$)(.*)   443    rewrite: /api(/|$)(.*) -> /$2 ($2 = (.*)); ssl redirect: false; backend protocol: HTTPS; auth: external https://auth.example.com/oauth2/auth; rate limit: 10 rps; timeouts: read 120s; allowed sources: 10.0.0.0/8   api *Not found*                                   \ndefault     ingress-canary     behavior.ingress.com   /api(/|$)(.*)   443    canary: weight 20/100, header X-Canary                                                                                                                                                                               api-canary *Not found*                            \n",
+		},
+	}
+
+	for _, test := range tests {
+
+		buf := &bytes.Buffer{}
+
+		if err := test.print(buf); err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		if buf.String() != test.expected {
+			t.Errorf("Returned %s was incorrect, got: %q, want: %q", test.name, buf.String(), test.expected)
+		}
+	}
+}
+
+const exposureManifests = `apiVersion: v1
+kind: Node
+metadata:
+  name: node-1
+status:
+  addresses:
+  - type: InternalIP
+    address: 10.0.0.1
+  - type: ExternalIP
+    address: 198.51.100.1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+spec:
+  type: LoadBalancer
+  loadBalancerSourceRanges:
+  - 192.0.2.0/24
+  ports:
+  - port: 80
+    nodePort: 30080
+    protocol: TCP
+status:
+  loadBalancer:
+    ingress:
+    - ip: 203.0.113.10
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ingress-exposure
+spec:
+  rules:
+  - host: exposure.ingress.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: foo
+            port:
+              number: 80
+status:
+  loadBalancer:
+    ingress:
+    - hostname: lb.example.com
+`
+
+func TestIngressPrintExposureSuccessful(t *testing.T) {
+
+	ingress := newManifestIngress(t, exposureManifests)
+
+	tests := []struct {
+		name     string
+		print    func(w io.Writer) error
+		expected string
+	}{
+		{
+			"graph",
+			func(w io.Writer) error { return ingress.PrintGraph("ingress-exposure", w) },
+			"[Ingress]  ingress-exposure\n├── [LoadBalancer]  lb.example.com:80\n└── exposure.ingress.com\n    └── /\n        └── [Service]  foo\n            ├── [LoadBalancer]  203.0.113.10:80\n            ├── [NodePort]  10.0.0.1:30080 (node-1 InternalIP)\n            ├── [NodePort]  198.51.100.1:30080 (node-1 ExternalIP)\n            └── [Source ranges]  192.0.2.0/24\n",
+		},
+		{
+			"table",
+			func(w io.Writer) error { return ingress.PrintTable("ingress-exposure", w) },
+			"NAME               ADDRESS          HOST                   PATH   PORT   SERVICE   TYPE           SERVICE PORT(S)    POD(S)\ningress-exposure   lb.example.com   exposure.ingress.com   /      80     foo       LoadBalancer   80 80 node:30080   \n",
+		},
+	}
+
+	for _, test := range tests {
+
+		buf := &bytes.Buffer{}
+
+		if err := test.print(buf); err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		if buf.String() != test.expected {
+			t.Errorf("Returned %s was incorrect, got: %q, want: %q", test.name, buf.String(), test.expected)
+		}
+	}
+}
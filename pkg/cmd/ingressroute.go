@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"io"
+
+	"kube-route-info/pkg/route"
+)
+
+// IngressRoute defines Traefik ingress route attributes
+type IngressRoute struct {
+	Resolver *route.Resolver
+}
+
+// NewIngressRoute returns a new IngressRoute struct
+func NewIngressRoute(client route.ClientInterface, namespace string) *IngressRoute {
+	return &IngressRoute{
+		Resolver: route.NewResolver(client, namespace),
+	}
+}
+
+// ResetCache drops the cluster scoped objects cached by the resolver, so that they are read again
+func (i *IngressRoute) ResetCache() {
+	i.Resolver.ResetCache()
+}
+
+// PrintGraph prints Traefik ingress route information in a tree graph format
+func (i *IngressRoute) PrintGraph(name string, w io.Writer) error {
+
+	ingressRoute, err := i.Resolver.ResolveIngressRoute(name)
+	if err != nil {
+		return err
+	}
+
+	printRouteGraph([]*route.Route{ingressRoute}, w)
+
+	return nil
+}
+
+// PrintTable prints Traefik ingress route information in table format
+func (i *IngressRoute) PrintTable(name string, w io.Writer) error {
+
+	ingressRoute, err := i.Resolver.ResolveIngressRoute(name)
+	if err != nil {
+		return err
+	}
+
+	printRouteTable([]*route.Route{ingressRoute}, false, w)
+
+	return nil
+}
+
+// PrintDocument prints Traefik ingress route information in a machine-readable format
+func (i *IngressRoute) PrintDocument(name string, output string, w io.Writer) error {
+
+	ingressRoute, err := i.Resolver.ResolveIngressRoute(name)
+	if err != nil {
+		return err
+	}
+
+	document := route.NewDocument()
+	document.IngressRoute = ingressRoute
+
+	return PrintDocument(document, output, w)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// ingressRouteManifest defines the Traefik ingress route returned by the ingress route mock client
+var ingressRouteManifest = `
+apiVersion: traefik.io/v1alpha1
+kind: IngressRoute
+metadata:
+  name: ingressroute-foo
+  namespace: default
+spec:
+  routes:
+  - match: Host(` + "`foo.com`" + `) && PathPrefix(` + "`/api`" + `) && Headers(` + "`X-Canary`, `true`" + `)
+    kind: Rule
+    middlewares:
+    - name: strip-prefix
+    services:
+    - name: service-foo
+      port: 80
+      weight: 3
+    - name: service-bar
+      port: 80
+  - match: Host(` + "`foo.com`" + `)
+    kind: Rule
+    services:
+    - name: service-bar
+      port: 80
+  tls:
+    secretName: foo-tls
+`
+
+// NewIngressRouteMockClient returns a mock client of the Traefik ingress route
+func NewIngressRouteMockClient() *CustomResourceMockClient {
+	return NewCustomResourceMockClient(ingressRouteManifest)
+}
+
+func TestIngressRoutePrintGraphSuccessful(t *testing.T) {
+
+	tests := []struct {
+		ingressRouteName string
+		expectedGraph    string
+	}{
+		{
+			"ingressroute-foo",
+			"[IngressRoute]  ingressroute-foo\n└── foo.com\n    ├── [TLS]  edge\n    ├── /api [header:X-Canary=true]\n    │\u00a0\u00a0 ├── [Filter]  middleware:strip-prefix\n    │\u00a0\u00a0 ├── [Service]  service-foo (weight 3)\n    │\u00a0\u00a0 │\u00a0\u00a0 └── [Pod]  pod-foo-1\n    │\u00a0\u00a0 └── [Service]  service-bar (weight 1)\n    │\u00a0\u00a0     └── [Pod]  pod-bar-1\n    └── /\n        └── [Service]  service-bar (weight 1)\n            └── [Pod]  pod-bar-1\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewIngressRouteMockClient()
+
+		ingressRoute := NewIngressRoute(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		ingressRoute.PrintGraph(test.ingressRouteName, buf)
+
+		if buf.String() != test.expectedGraph {
+			t.Errorf("Returned tree graph was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedGraph)
+		}
+	}
+}
+
+func TestIngressRoutePrintTableSuccessful(t *testing.T) {
+
+	tests := []struct {
+		ingressRouteName string
+		expectedTable    string
+	}{
+		{
+			"ingressroute-foo",
+			"NAME               HOST      TLS    PATH   MATCH                  PORT   WEIGHT   FILTERS                   SERVICE       TYPE        SERVICE PORT(S)   POD(S)\ningressroute-foo   foo.com   edge   /api   header:X-Canary=true   80     3        middleware:strip-prefix   service-foo   ClusterIP   80 8080           pod-foo-1\ningressroute-foo   foo.com   edge   /api   header:X-Canary=true   80     1        middleware:strip-prefix   service-bar   ClusterIP   80 8080           pod-bar-1\ningressroute-foo   foo.com   edge   /                             80     1                                  service-bar   ClusterIP   80 8080           pod-bar-1\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewIngressRouteMockClient()
+
+		ingressRoute := NewIngressRoute(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		ingressRoute.PrintTable(test.ingressRouteName, buf)
+
+		if buf.String() != test.expectedTable {
+			t.Errorf("Returned table was incorrect,\ngot:\n%swant:\n%s", buf.String(), test.expectedTable)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// LoadManifests returns the objects of the YAML or JSON manifests of the given files, directories
+// or URLs, or of the standard input for "-". Directories are read recursively when recursive is
+// true, and lists are flattened into their items. The manifests are read without cluster access
+func LoadManifests(filenames []string, recursive bool) ([]*unstructured.Unstructured, error) {
+
+	infos, err := resource.NewLocalBuilder().
+		Unstructured().
+		FilenameParam(false, &resource.FilenameOptions{Filenames: filenames, Recursive: recursive}).
+		Flatten().
+		Do().
+		Infos()
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []*unstructured.Unstructured{}
+
+	for _, info := range infos {
+		object, ok := info.Object.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object %q in %s", info.Name, info.Source)
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"kube-route-info/pkg/route"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BaseMockClient implements the client interface without returning any object. Mock clients
+// embed it and only implement the methods that return the objects of their tests, so that
+// methods added to the client interface only need to be added here
+type BaseMockClient struct{}
+
+func (c *BaseMockClient) GetPodsByLabels(labels map[string]string) (*v1.PodList, error) {
+	return nil, nil
+}
+
+func (c *BaseMockClient) GetServiceByName(name string) (*v1.Service, error) { return nil, nil }
+
+func (c *BaseMockClient) GetIngressByName(name string) (*networkingv1.Ingress, error) {
+	return nil, nil
+}
+
+func (c *BaseMockClient) GetPodByName(name string) (*v1.Pod, error) { return nil, nil }
+
+func (c *BaseMockClient) ListServices(selector string) (*v1.ServiceList, error) { return nil, nil }
+
+func (c *BaseMockClient) ListIngresses(selector string) (*networkingv1.IngressList, error) {
+	return nil, nil
+}
+
+func (c *BaseMockClient) GetEndpointSlicesByService(name string) (*discoveryv1.EndpointSliceList, error) {
+	return nil, nil
+}
+
+func (c *BaseMockClient) GetNamespaceByName(name string) (*v1.Namespace, error) { return nil, nil }
+
+func (c *BaseMockClient) GetSecretByName(name string) (*v1.Secret, error) { return nil, nil }
+
+func (c *BaseMockClient) ListIngressClasses() (*networkingv1.IngressClassList, error) {
+	return nil, nil
+}
+
+func (c *BaseMockClient) ListNodes() (*v1.NodeList, error) { return nil, nil }
+
+func (c *BaseMockClient) GetCustomResourceByName(resource string, groupVersions []string, name string) (*unstructured.Unstructured, error) {
+	return nil, nil
+}
+
+func (c *BaseMockClient) ListCustomResources(resource string, groupVersions []string, selector string) (*unstructured.UnstructuredList, error) {
+	return nil, nil
+}
+
+func (c *BaseMockClient) Namespaced(namespace string) route.ClientInterface { return c }
+
+// customResourceKinds defines the kinds of the custom resources read by the resolvers
+var customResourceKinds = map[string]string{
+	"gateways":         "Gateway",
+	"httproutes":       "HTTPRoute",
+	"virtualservices":  "VirtualService",
+	"destinationrules": "DestinationRule",
+	"serviceentries":   "ServiceEntry",
+	"routes":           "Route",
+	"ingressroutes":    "IngressRoute",
+	"httpproxies":      "HTTPProxy",
+}
+
+// CustomResourceMockClient returns the custom resources of the given manifests, and for each
+// service-<app> service a ClusterIP service that selects its pod-<app>-1 pod
+type CustomResourceMockClient struct {
+	BaseMockClient
+	manifests []string
+}
+
+func NewCustomResourceMockClient(manifests ...string) *CustomResourceMockClient {
+	return &CustomResourceMockClient{manifests: manifests}
+}
+
+func (c *CustomResourceMockClient) GetPodsByLabels(labels map[string]string) (*v1.PodList, error) {
+	return &v1.PodList{
+		Items: []v1.Pod{
+			{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod-" + labels["app"] + "-1",
+					Namespace: "default",
+					Labels:    labels,
+				},
+			},
+		},
+	}, nil
+}
+
+func (c *CustomResourceMockClient) GetServiceByName(name string) (*v1.Service, error) {
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Type: v1.ServiceTypeClusterIP,
+			Ports: []v1.ServicePort{
+				{Port: 80, TargetPort: intstr.IntOrString{Type: 0, IntVal: 8080}},
+			},
+			Selector: map[string]string{
+				"app": name[len("service-"):],
+			},
+		},
+	}, nil
+}
+
+func (c *CustomResourceMockClient) GetEndpointSlicesByService(name string) (*discoveryv1.EndpointSliceList, error) {
+	service, _ := c.GetServiceByName(name)
+	pods, _ := c.GetPodsByLabels(service.Spec.Selector)
+	return newEndpointSlices(pods), nil
+}
+
+func (c *CustomResourceMockClient) GetCustomResourceByName(resource string, groupVersions []string, name string) (*unstructured.Unstructured, error) {
+	list, _ := c.ListCustomResources(resource, groupVersions, "")
+
+	for index := range list.Items {
+		if list.Items[index].GetName() == name {
+			return &list.Items[index], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (c *CustomResourceMockClient) ListCustomResources(resource string, groupVersions []string, selector string) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+
+	for _, manifest := range c.manifests {
+		object := unstructured.Unstructured{}
+		yaml.Unmarshal([]byte(manifest), &object.Object)
+
+		if object.GetKind() == customResourceKinds[resource] {
+			list.Items = append(list.Items, object)
+		}
+	}
+
+	return list, nil
+}
+
+func (c *CustomResourceMockClient) Namespaced(namespace string) route.ClientInterface { return c }
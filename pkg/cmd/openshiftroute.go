@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"io"
+
+	"kube-route-info/pkg/route"
+)
+
+// OpenShiftRoute defines OpenShift route attributes
+type OpenShiftRoute struct {
+	Resolver *route.Resolver
+}
+
+// NewOpenShiftRoute returns a new OpenShiftRoute struct
+func NewOpenShiftRoute(client route.ClientInterface, namespace string) *OpenShiftRoute {
+	return &OpenShiftRoute{
+		Resolver: route.NewResolver(client, namespace),
+	}
+}
+
+// ResetCache drops the cluster scoped objects cached by the resolver, so that they are read again
+func (o *OpenShiftRoute) ResetCache() {
+	o.Resolver.ResetCache()
+}
+
+// PrintGraph prints OpenShift route information in a tree graph format
+func (o *OpenShiftRoute) PrintGraph(name string, w io.Writer) error {
+
+	openShiftRoute, err := o.Resolver.ResolveOpenShiftRoute(name)
+	if err != nil {
+		return err
+	}
+
+	printRouteGraph([]*route.Route{openShiftRoute}, w)
+
+	return nil
+}
+
+// PrintTable prints OpenShift route information in table format
+func (o *OpenShiftRoute) PrintTable(name string, w io.Writer) error {
+
+	openShiftRoute, err := o.Resolver.ResolveOpenShiftRoute(name)
+	if err != nil {
+		return err
+	}
+
+	printRouteTable([]*route.Route{openShiftRoute}, false, w)
+
+	return nil
+}
+
+// PrintDocument prints OpenShift route information in a machine-readable format
+func (o *OpenShiftRoute) PrintDocument(name string, output string, w io.Writer) error {
+
+	openShiftRoute, err := o.Resolver.ResolveOpenShiftRoute(name)
+	if err != nil {
+		return err
+	}
+
+	document := route.NewDocument()
+	document.OpenShiftRoute = openShiftRoute
+
+	return PrintDocument(document, output, w)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+// openShiftRouteManifest defines the OpenShift route returned by the OpenShift route mock client
+var openShiftRouteManifest = `
+apiVersion: route.openshift.io/v1
+kind: Route
+metadata:
+  name: route-foo
+  namespace: default
+spec:
+  host: foo.apps.example.com
+  path: /api
+  to:
+    kind: Service
+    name: service-foo
+    weight: 80
+  alternateBackends:
+  - kind: Service
+    name: service-bar
+    weight: 20
+  port:
+    targetPort: 8080
+  tls:
+    termination: edge
+    insecureEdgeTerminationPolicy: Redirect
+`
+
+// NewOpenShiftRouteMockClient returns a mock client of the OpenShift route
+func NewOpenShiftRouteMockClient() *CustomResourceMockClient {
+	return NewCustomResourceMockClient(openShiftRouteManifest)
+}
+
+func TestOpenShiftRoutePrintGraphSuccessful(t *testing.T) {
+
+	tests := []struct {
+		routeName     string
+		expectedGraph string
+	}{
+		{
+			"route-foo",
+			"[Route]  route-foo\n└── foo.apps.example.com\n    ├── [TLS]  edge (insecure Redirect)\n    └── /api\n        ├── [Service]  service-foo (weight 80)\n        │\u00a0\u00a0 └── [Pod]  pod-foo-1\n        └── [Service]  service-bar (weight 20)\n            └── [Pod]  pod-bar-1\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewOpenShiftRouteMockClient()
+
+		openShiftRoute := NewOpenShiftRoute(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		openShiftRoute.PrintGraph(test.routeName, buf)
+
+		if buf.String() != test.expectedGraph {
+			t.Errorf("Returned tree graph was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedGraph)
+		}
+	}
+}
+
+func TestOpenShiftRoutePrintTableSuccessful(t *testing.T) {
+
+	tests := []struct {
+		routeName     string
+		expectedTable string
+	}{
+		{
+			"route-foo",
+			"NAME        HOST                   TLS                        PATH   PORT   WEIGHT   SERVICE       TYPE        SERVICE PORT(S)   POD(S)\nroute-foo   foo.apps.example.com   edge (insecure Redirect)   /api   8080   80       service-foo   ClusterIP   80 8080           pod-foo-1\nroute-foo   foo.apps.example.com   edge (insecure Redirect)   /api   8080   20       service-bar   ClusterIP   80 8080           pod-bar-1\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewOpenShiftRouteMockClient()
+
+		openShiftRoute := NewOpenShiftRoute(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		openShiftRoute.PrintTable(test.routeName, buf)
+
+		if buf.String() != test.expectedTable {
+			t.Errorf("Returned table was incorrect,\ngot:\n%swant:\n%s", buf.String(), test.expectedTable)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"kube-route-info/pkg/route"
+
+	"github.com/xlab/treeprint"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// Pod defines Pod attributes
+type Pod struct {
+	Resolver *route.Resolver
+}
+
+// NewPod returns a new Pod struct
+func NewPod(client route.ClientInterface, namespace string) *Pod {
+	return &Pod{
+		Resolver: route.NewResolver(client, namespace),
+	}
+}
+
+// ResetCache drops the cluster scoped objects cached by the resolver, so that they are read again
+func (p *Pod) ResetCache() {
+	p.Resolver.ResetCache()
+}
+
+// PrintGraph prints the services and ingresses routing to a pod in a tree graph format
+func (p *Pod) PrintGraph(name string, w io.Writer) (err error) {
+
+	pod, err := p.Resolver.ResolvePod(name)
+	if err != nil {
+		return err
+	}
+
+	tree := treeprint.New()
+
+	podBranch := tree.AddMetaBranch("Pod", pod.Name)
+
+	for _, service := range pod.Services {
+
+		serviceBranch := podBranch.AddMetaBranch("Service", service.Name)
+
+		ingressBranches := map[string]treeprint.Tree{}
+		hostBranches := map[string]treeprint.Tree{}
+
+		for _, backend := range service.Backends {
+
+			ingressBranch, ok := ingressBranches[backend.Ingress]
+			if !ok {
+				ingressBranch = serviceBranch.AddMetaBranch("Ingress", backend.Ingress)
+				ingressBranches[backend.Ingress] = ingressBranch
+			}
+
+			if backend.DefaultBackend {
+				ingressBranch.AddNode("[Default backend]")
+				continue
+			}
+
+			hostKey := backend.Ingress + "/" + backend.Host
+			hostBranch, ok := hostBranches[hostKey]
+			if !ok {
+				hostBranch = ingressBranch.AddBranch(backend.Host)
+				hostBranches[hostKey] = hostBranch
+			}
+
+			hostBranch.AddNode(backend.Path)
+		}
+	}
+
+	fmt.Fprint(w, podBranch.String())
+
+	return
+}
+
+// PrintTable prints the services and ingresses routing to a pod in table format
+func (p *Pod) PrintTable(name string, w io.Writer) (err error) {
+
+	pod, err := p.Resolver.ResolvePod(name)
+	if err != nil {
+		return err
+	}
+
+	rows := []metav1.TableRow{}
+
+	for _, service := range pod.Services {
+
+		if len(service.Backends) == 0 {
+			rows = append(rows, metav1.TableRow{
+				Cells: []interface{}{
+					pod.Name,
+					service.Name,
+					service.Type,
+					PortsToString(service.Ports),
+					"",
+					"",
+					"",
+					"",
+				},
+			})
+		}
+
+		for _, backend := range service.Backends {
+			host, path := IngressPathToHostAndPath(backend)
+
+			rows = append(rows, metav1.TableRow{
+				Cells: []interface{}{
+					pod.Name,
+					service.Name,
+					service.Type,
+					PortsToString(service.Ports),
+					backend.Ingress,
+					host,
+					path,
+					backend.BackendPort,
+				},
+			})
+		}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, metav1.TableRow{
+			Cells: []interface{}{pod.Name, "", "", "", "", "", "", ""},
+		})
+	}
+
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "Service", Type: "string"},
+			{Name: "Type", Type: "string"},
+			{Name: "Service Port(s)", Type: "string"},
+			{Name: "Ingress", Type: "string"},
+			{Name: "Host", Type: "string"},
+			{Name: "Path", Type: "string"},
+			{Name: "Port", Type: "string"},
+		},
+		Rows: rows,
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	printer.PrintObj(table, out)
+
+	fmt.Fprint(w, out.String())
+
+	return
+}
+
+// PrintDocument prints the services and ingresses routing to a pod in a machine-readable format
+func (p *Pod) PrintDocument(name string, output string, w io.Writer) error {
+
+	pod, err := p.Resolver.ResolvePod(name)
+	if err != nil {
+		return err
+	}
+
+	document := route.NewDocument()
+	document.Pod = pod
+
+	return PrintDocument(document, output, w)
+}
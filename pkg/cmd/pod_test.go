@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"kube-route-info/pkg/route"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// Mock client struct
+type PodMockClient struct {
+	BaseMockClient
+}
+
+func NewPodMockClient() *PodMockClient {
+	return &PodMockClient{}
+}
+
+func (c *PodMockClient) GetPodByName(name string) (*v1.Pod, error) {
+	podList := []v1.Pod{
+		{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod-foo-1",
+				Namespace: "default",
+				Labels: map[string]string{
+					"app":     "foo",
+					"version": "v1",
+				},
+			},
+		},
+		{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod-bar-1",
+				Namespace: "default",
+				Labels: map[string]string{
+					"app": "bar",
+				},
+			},
+		},
+		{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod-orphan",
+				Namespace: "default",
+				Labels: map[string]string{
+					"app": "orphan",
+				},
+			},
+		},
+	}
+
+	for _, pod := range podList {
+		if pod.Name == name {
+			return &pod, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (c *PodMockClient) ListServices(selector string) (*v1.ServiceList, error) {
+	return &v1.ServiceList{
+		Items: []v1.Service{
+			{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "service-foo",
+					Namespace: "default",
+				},
+				Spec: v1.ServiceSpec{
+					Type: v1.ServiceTypeClusterIP,
+					Ports: []v1.ServicePort{
+						{Port: 80, TargetPort: intstr.IntOrString{Type: 0, IntVal: 8080}},
+					},
+					Selector: map[string]string{
+						"app": "foo",
+					},
+				},
+			},
+			{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "service-foo-v1",
+					Namespace: "default",
+				},
+				Spec: v1.ServiceSpec{
+					Type: v1.ServiceTypeNodePort,
+					Ports: []v1.ServicePort{
+						{Port: 80, TargetPort: intstr.IntOrString{Type: 1, StrVal: "http"}, NodePort: 1234},
+					},
+					Selector: map[string]string{
+						"app":     "foo",
+						"version": "v1",
+					},
+				},
+			},
+			{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "service-bar",
+					Namespace: "default",
+				},
+				Spec: v1.ServiceSpec{
+					Type: v1.ServiceTypeClusterIP,
+					Ports: []v1.ServicePort{
+						{Port: 80, TargetPort: intstr.IntOrString{Type: 1, StrVal: "http"}},
+					},
+					Selector: map[string]string{
+						"app": "bar",
+					},
+				},
+			},
+			{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "service-no-selector",
+					Namespace: "default",
+				},
+				Spec: v1.ServiceSpec{
+					Type: v1.ServiceTypeClusterIP,
+					Ports: []v1.ServicePort{
+						{Port: 80, TargetPort: intstr.IntOrString{Type: 0, IntVal: 80}},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (c *PodMockClient) ListIngresses(selector string) (*networkingv1.IngressList, error) {
+	return &networkingv1.IngressList{
+		Items: []networkingv1.Ingress{
+			{
+				TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-foo",
+					Namespace: "default",
+				},
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{
+						{
+							Host: "foo.com",
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
+										{
+											Path: "/",
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: "service-foo",
+													Port: networkingv1.ServiceBackendPort{
+														Number: 80,
+													},
+												},
+											},
+										},
+										{
+											Path: "/v1",
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: "service-foo-v1",
+													Port: networkingv1.ServiceBackendPort{
+														Number: 80,
+													},
+												},
+											},
+										},
+										{
+											Path: "/api",
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: "service-foo",
+													Port: networkingv1.ServiceBackendPort{
+														Number: 80,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						{
+							Host: "bar.com",
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
+										{
+											Path: "/foo",
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: "service-foo",
+													Port: networkingv1.ServiceBackendPort{
+														Number: 80,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-default",
+					Namespace: "default",
+				},
+				Spec: networkingv1.IngressSpec{
+					DefaultBackend: &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: "service-foo-v1",
+							Port: networkingv1.ServiceBackendPort{
+								Name: "http",
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (c *PodMockClient) Namespaced(namespace string) route.ClientInterface { return c }
+
+func TestPodPrintGraphSuccessful(t *testing.T) {
+
+	tests := []struct {
+		podName       string
+		expectedGraph string
+	}{
+		{
+			"pod-foo-1",
+			"[Pod]  pod-foo-1\n├── [Service]  service-foo\n│   └── [Ingress]  ingress-foo\n│       ├── foo.com\n│       │   ├── /\n│       │   └── /api\n│       └── bar.com\n│           └── /foo\n└── [Service]  service-foo-v1\n    ├── [Ingress]  ingress-foo\n    │   └── foo.com\n    │       └── /v1\n    └── [Ingress]  ingress-default\n        └── [Default backend]\n",
+		},
+		{
+			"pod-bar-1",
+			"[Pod]  pod-bar-1\n└── [Service]  service-bar\n",
+		},
+		{
+			"pod-orphan",
+			"[Pod]  pod-orphan\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewPodMockClient()
+
+		pod := NewPod(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		pod.PrintGraph(test.podName, buf)
+
+		if buf.String() != test.expectedGraph {
+			t.Errorf("Returned tree graph was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedGraph)
+		}
+	}
+}
+
+func TestPodPrintTableSuccessful(t *testing.T) {
+
+	tests := []struct {
+		podName       string
+		expectedTable string
+	}{
+		{
+			"pod-foo-1",
+			"NAME        SERVICE          TYPE        SERVICE PORT(S)     INGRESS           HOST      PATH                PORT\npod-foo-1   service-foo      ClusterIP   80 8080             ingress-foo       foo.com   /                   80\npod-foo-1   service-foo      ClusterIP   80 8080             ingress-foo       foo.com   /api                80\npod-foo-1   service-foo      ClusterIP   80 8080             ingress-foo       bar.com   /foo                80\npod-foo-1   service-foo-v1   NodePort    80 http node:1234   ingress-foo       foo.com   /v1                 80\npod-foo-1   service-foo-v1   NodePort    80 http node:1234   ingress-default   *         (default backend)   http\n",
+		},
+		{
+			"pod-bar-1",
+			"NAME        SERVICE       TYPE        SERVICE PORT(S)   INGRESS   HOST   PATH   PORT\npod-bar-1   service-bar   ClusterIP   80 http                                   \n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewPodMockClient()
+
+		pod := NewPod(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		pod.PrintTable(test.podName, buf)
+
+		if buf.String() != test.expectedTable {
+			t.Errorf("Returned table was incorrect,\ngot:\n%swant:\n%s", buf.String(), test.expectedTable)
+		}
+	}
+}
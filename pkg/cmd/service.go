@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 
+	"kube-route-info/pkg/route"
+
 	"github.com/xlab/treeprint"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -12,91 +14,213 @@ import (
 
 // Service defines Service attributes
 type Service struct {
-	Client    ClientInterface
-	Namespace string
+	Resolver *route.Resolver
 }
 
 // NewService returns a new Service struct
-func NewService(client ClientInterface, namespace string) *Service {
+func NewService(client route.ClientInterface, namespace string) *Service {
 	return &Service{
-		Client:    client,
-		Namespace: namespace,
+		Resolver: route.NewResolver(client, namespace),
 	}
 }
 
+// ResetCache drops the cluster scoped objects cached by the resolver, so that they are read again
+func (s *Service) ResetCache() {
+	s.Resolver.ResetCache()
+}
+
 // PrintGraph prints service route information in a tree graph format
 func (s *Service) PrintGraph(name string, w io.Writer) (err error) {
 
-	service, err := s.Client.GetServiceByName(name)
+	service, err := s.Resolver.ResolveService(name)
 	if err != nil {
 		return err
 	}
 
-	pods, err := s.Client.GetPodsByLabels(service.Spec.Selector)
+	printServiceGraph([]*route.Service{service}, w)
+
+	return
+}
+
+// PrintTable prints service route information in table format
+func (s *Service) PrintTable(name string, w io.Writer) (err error) {
+
+	service, err := s.Resolver.ResolveService(name)
 	if err != nil {
 		return err
 	}
 
-	tree := treeprint.New()
+	printServiceTable([]*route.Service{service}, false, w)
 
-	serviceBranch := tree.AddMetaBranch("Service", service.Name)
+	return
+}
 
-	if ServiceTypeToString(service.Spec.Type) == "ExternalName" {
-		serviceBranch.AddMetaNode("Hostname", service.Spec.ExternalName)
+// PrintDocument prints service route information in a machine-readable format
+func (s *Service) PrintDocument(name string, output string, w io.Writer) error {
 
-	} else {
-		for _, pod := range pods.Items {
-			serviceBranch.AddMetaNode("Pod", pod.Name)
-		}
+	service, err := s.Resolver.ResolveService(name)
+	if err != nil {
+		return err
 	}
 
-	fmt.Fprint(w, serviceBranch.String())
+	document := route.NewDocument()
+	document.Service = service
 
-	return
+	return PrintDocument(document, output, w)
 }
 
-// PrintTable prints service route information in table format
-func (s *Service) PrintTable(name string, w io.Writer) (err error) {
+// PrintGraphList prints the route information of several services as a forest of tree graphs
+func (s *Service) PrintGraphList(names []string, selector string, w io.Writer) error {
+
+	services, err := s.Resolver.ResolveServices(names, selector)
+	if err != nil {
+		return err
+	}
+
+	printServiceGraph(services, w)
+
+	return nil
+}
 
-	service, err := s.Client.GetServiceByName(name)
+// PrintTableList prints the route information of several services in one table
+func (s *Service) PrintTableList(names []string, selector string, w io.Writer) error {
+
+	services, err := s.Resolver.ResolveServices(names, selector)
 	if err != nil {
 		return err
 	}
 
-	pods, err := s.Client.GetPodsByLabels(service.Spec.Selector)
+	printServiceTable(services, true, w)
+
+	return nil
+}
+
+// PrintDocumentList prints the route information of several services in a machine-readable format
+func (s *Service) PrintDocumentList(names []string, selector string, output string, w io.Writer) error {
+
+	services, err := s.Resolver.ResolveServices(names, selector)
 	if err != nil {
 		return err
 	}
 
-	columnName := ""
-	cellValue := ""
+	document := route.NewDocument()
+	document.Services = services
+
+	return PrintDocument(document, output, w)
+}
+
+func printServiceGraph(services []*route.Service, w io.Writer) {
+
+	if len(services) == 0 {
+		fmt.Fprintln(w, "No resources found")
+		return
+	}
+
+	for index, service := range services {
+
+		// Trees of a forest are separated by a blank line
+		if index > 0 {
+			fmt.Fprintln(w)
+		}
+
+		tree := treeprint.New()
+
+		serviceBranch := AddServiceBranch(tree, service)
 
-	if ServiceTypeToString(service.Spec.Type) == "ExternalName" {
+		fmt.Fprint(w, serviceBranch.String())
+	}
+}
+
+func printServiceTable(services []*route.Service, withNamespace bool, w io.Writer) {
+
+	if len(services) == 0 {
+		fmt.Fprintln(w, "No resources found")
+		return
+	}
+
+	pods := false
+	hostnames := false
+	exposed := false
+	podDetails := false
+
+	rows := []metav1.TableRow{}
+
+	for _, service := range services {
+
+		if service.Hostname != "" {
+			hostnames = true
+		} else {
+			pods = true
+		}
+
+		if service.Exposure != nil {
+			exposed = true
+		}
+
+		if hasPodDetails(service) {
+			podDetails = true
+		}
+	}
+
+	for _, service := range services {
+
+		cells := []interface{}{
+			service.Name,
+			service.Type,
+			PortsToString(service.Ports),
+		}
+
+		if exposed {
+			cells = append(cells, ExposureToString(service.Exposure))
+		}
+
+		if withNamespace {
+			cells = append([]interface{}{service.Namespace}, cells...)
+		}
+
+		if !podDetails {
+			rows = append(rows, metav1.TableRow{Cells: append(cells, ServiceTargetsToString(service))})
+			continue
+		}
+
+		// Wide tables print the service in one row per pod
+		for _, podRow := range PodDetailsRows(service) {
+			rows = append(rows, metav1.TableRow{Cells: append(append([]interface{}{}, cells...), podRow...)})
+		}
+	}
+
+	columnName := "Pod(s)"
+
+	switch {
+	case pods && hostnames:
+		columnName = "Pod(s)/Hostname"
+	case hostnames:
 		columnName = "Hostname"
-		cellValue = service.Spec.ExternalName
+	}
 
-	} else {
-		columnName = "Pod(s)"
-		cellValue = PodsToString(pods)
+	columns := []metav1.TableColumnDefinition{
+		{Name: "Name", Type: "string"},
+		{Name: "Type", Type: "string"},
+		{Name: "Port(s)", Type: "string"},
+	}
+
+	if exposed {
+		columns = append(columns, metav1.TableColumnDefinition{Name: "External", Type: "string"})
+	}
+
+	columns = append(columns, metav1.TableColumnDefinition{Name: columnName, Type: "string"})
+
+	if podDetails {
+		columns = append(columns, PodDetailsDefinitions()...)
+	}
+
+	if withNamespace {
+		columns = append([]metav1.TableColumnDefinition{{Name: "Namespace", Type: "string"}}, columns...)
 	}
 
 	table := &metav1.Table{
-		ColumnDefinitions: []metav1.TableColumnDefinition{
-			{Name: "Name", Type: "string"},
-			{Name: "Type", Type: "string"},
-			{Name: "Port(s)", Type: "string"},
-			{Name: columnName, Type: "string"},
-		},
-		Rows: []metav1.TableRow{
-			{
-				Cells: []interface{}{
-					service.Name,
-					ServiceTypeToString(service.Spec.Type),
-					PortsToString(service.Spec.Ports),
-					cellValue,
-				},
-			},
-		},
+		ColumnDefinitions: columns,
+		Rows:              rows,
 	}
 
 	out := bytes.NewBuffer([]byte{})
@@ -104,6 +228,4 @@ func (s *Service) PrintTable(name string, w io.Writer) (err error) {
 	printer.PrintObj(table, out)
 
 	fmt.Fprint(w, out.String())
-
-	return
 }
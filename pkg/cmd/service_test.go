@@ -2,18 +2,23 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
 	"reflect"
 	"testing"
 
+	"kube-route-info/pkg/route"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/networking/v1beta1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 )
 
 // Mock client struct
-type ServiceMockClient struct{}
+type ServiceMockClient struct {
+	BaseMockClient
+}
 
 func NewServiceMockClient() *ServiceMockClient {
 	return &ServiceMockClient{}
@@ -32,6 +37,11 @@ func (c *ServiceMockClient) GetPodsByLabels(labels map[string]string) (*v1.PodLi
 					"version": "v1",
 				},
 			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "app", Ports: []v1.ContainerPort{{Name: "https", ContainerPort: 8443}}},
+				},
+			},
 		},
 		{
 			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
@@ -43,6 +53,11 @@ func (c *ServiceMockClient) GetPodsByLabels(labels map[string]string) (*v1.PodLi
 					"version": "v1",
 				},
 			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "app", Ports: []v1.ContainerPort{{Name: "https", ContainerPort: 8443}}},
+				},
+			},
 		},
 		{
 			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
@@ -54,6 +69,11 @@ func (c *ServiceMockClient) GetPodsByLabels(labels map[string]string) (*v1.PodLi
 					"version": "v1",
 				},
 			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "app", Ports: []v1.ContainerPort{{Name: "https", ContainerPort: 9443}}},
+				},
+			},
 		},
 		{
 			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
@@ -153,6 +173,19 @@ func (c *ServiceMockClient) GetServiceByName(name string) (*v1.Service, error) {
 				},
 			},
 		},
+		{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "service-selectorless",
+				Namespace: "default",
+			},
+			Spec: v1.ServiceSpec{
+				Type: v1.ServiceTypeClusterIP,
+				Ports: []v1.ServicePort{
+					{Port: 80, TargetPort: intstr.IntOrString{Type: 0, IntVal: 8080}},
+				},
+			},
+		},
 		{
 			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
 			ObjectMeta: metav1.ObjectMeta{
@@ -175,7 +208,80 @@ func (c *ServiceMockClient) GetServiceByName(name string) (*v1.Service, error) {
 	return nil, nil
 }
 
-func (c *ServiceMockClient) GetIngressByName(name string) (*v1beta1.Ingress, error) { return nil, nil }
+func (c *ServiceMockClient) ListServices(selector string) (*v1.ServiceList, error) {
+	serviceNodePort, _ := c.GetServiceByName("service-nodeport")
+	serviceExternalName, _ := c.GetServiceByName("service-externalname")
+	serviceClusterIP, _ := c.GetServiceByName("service-clusterip")
+	serviceClusterIP.Namespace = "other"
+
+	return &v1.ServiceList{
+		Items: []v1.Service{*serviceClusterIP, *serviceNodePort, *serviceExternalName},
+	}, nil
+}
+
+func (c *ServiceMockClient) GetEndpointSlicesByService(name string) (*discoveryv1.EndpointSliceList, error) {
+	ready := true
+	notReady := false
+	nodeName := "node-1"
+
+	if name == "service-selectorless" {
+		return &discoveryv1.EndpointSliceList{
+			Items: []discoveryv1.EndpointSlice{
+				{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []discoveryv1.Endpoint{
+						{
+							Addresses:  []string{"10.0.0.1"},
+							Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+							TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: "pod-foo-2"},
+							NodeName:   &nodeName,
+						},
+						{
+							Addresses:  []string{"10.0.0.2"},
+							Conditions: discoveryv1.EndpointConditions{Ready: &notReady},
+							TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: "pod-foo-1"},
+						},
+						{
+							Addresses:  []string{"10.0.0.3"},
+							Conditions: discoveryv1.EndpointConditions{Ready: &notReady, Terminating: &ready},
+							TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: "pod-foo-3"},
+						},
+						{
+							Addresses:  []string{"192.168.1.10"},
+							Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+						},
+					},
+				},
+			},
+		}, nil
+	}
+
+	service, _ := c.GetServiceByName(name)
+	pods, _ := c.GetPodsByLabels(service.Spec.Selector)
+	return newEndpointSlices(pods), nil
+}
+
+// newEndpointSlices returns an endpoint slice with a ready endpoint per pod
+func newEndpointSlices(pods *v1.PodList) *discoveryv1.EndpointSliceList {
+	ready := true
+
+	endpointSlice := discoveryv1.EndpointSlice{
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   []discoveryv1.Endpoint{},
+	}
+
+	for index, pod := range pods.Items {
+		endpointSlice.Endpoints = append(endpointSlice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{fmt.Sprintf("10.0.0.%d", index+1)},
+			Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+			TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: pod.Name},
+		})
+	}
+
+	return &discoveryv1.EndpointSliceList{Items: []discoveryv1.EndpointSlice{endpointSlice}}
+}
+
+func (c *ServiceMockClient) Namespaced(namespace string) route.ClientInterface { return c }
 
 func TestServicePrintGraphSuccessful(t *testing.T) {
 
@@ -185,7 +291,7 @@ func TestServicePrintGraphSuccessful(t *testing.T) {
 	}{
 		{
 			"service-clusterip",
-			"[Service]  service-clusterip\n├── [Pod]  pod-foo-1\n├── [Pod]  pod-foo-2\n└── [Pod]  pod-foo-3\n",
+			"[Service]  service-clusterip\n├── [Pod]  pod-foo-1 (https=app:8443)\n├── [Pod]  pod-foo-2 (https=app:8443)\n└── [Pod]  pod-foo-3 (https=app:9443 *Mismatch*)\n",
 		},
 		{
 			"service-clusterip-no-pods",
@@ -193,11 +299,15 @@ func TestServicePrintGraphSuccessful(t *testing.T) {
 		},
 		{
 			"service-nodeport",
-			"[Service]  service-nodeport\n└── [Pod]  pod-foo-4\n",
+			"[Service]  service-nodeport\n└── [Pod]  pod-foo-4 (http *Not found*)\n",
 		},
 		{
 			"service-loadbalancer",
-			"[Service]  service-loadbalancer\n├── [Pod]  pod-foo-1\n├── [Pod]  pod-foo-2\n└── [Pod]  pod-foo-3\n",
+			"[Service]  service-loadbalancer\n├── [LoadBalancer]  <pending>\n├── [Pod]  pod-foo-1\n├── [Pod]  pod-foo-2\n└── [Pod]  pod-foo-3\n",
+		},
+		{
+			"service-selectorless",
+			"[Service]  service-selectorless\n├── [Pod]  pod-foo-1 (not ready)\n├── [Pod]  pod-foo-2\n├── [Pod]  pod-foo-3 (terminating)\n└── [Endpoint]  192.168.1.10\n",
 		},
 		{
 			"service-externalname",
@@ -229,7 +339,7 @@ func TestServicePrintTableSuccessful(t *testing.T) {
 	}{
 		{
 			"service-clusterip",
-			"NAME                TYPE        PORT(S)           POD(S)\nservice-clusterip   ClusterIP   80 80,443 https   pod-foo-1,pod-foo-2,pod-foo-3\n",
+			"NAME                TYPE        PORT(S)                                POD(S)\nservice-clusterip   ClusterIP   80 80,443 https=8443|9443 *Mismatch*   pod-foo-1,pod-foo-2,pod-foo-3\n",
 		},
 		{
 			"service-clusterip-no-pods",
@@ -237,11 +347,15 @@ func TestServicePrintTableSuccessful(t *testing.T) {
 		},
 		{
 			"service-nodeport",
-			"NAME               TYPE       PORT(S)        POD(S)\nservice-nodeport   NodePort   80 http 1234   pod-foo-4\n",
+			"NAME               TYPE       PORT(S)                         POD(S)\nservice-nodeport   NodePort   80 http *Not found* node:1234   pod-foo-4\n",
 		},
 		{
 			"service-loadbalancer",
-			"NAME                   TYPE           PORT(S)      POD(S)\nservice-loadbalancer   LoadBalancer   80 80 1234   pod-foo-1,pod-foo-2,pod-foo-3\n",
+			"NAME                   TYPE           PORT(S)           EXTERNAL    POD(S)\nservice-loadbalancer   LoadBalancer   80 80 node:1234   <pending>   pod-foo-1,pod-foo-2,pod-foo-3\n",
+		},
+		{
+			"service-selectorless",
+			"NAME                   TYPE        PORT(S)   POD(S)\nservice-selectorless   ClusterIP   80 8080   pod-foo-1 (not ready),pod-foo-2,pod-foo-3 (terminating),192.168.1.10\n",
 		},
 		{
 			"service-externalname",
@@ -264,3 +378,291 @@ func TestServicePrintTableSuccessful(t *testing.T) {
 		}
 	}
 }
+
+func TestServicePrintRouteDocumentSuccessful(t *testing.T) {
+
+	tests := []struct {
+		serviceName      string
+		expectedDocument string
+	}{
+		{
+			"service-nodeport",
+			"apiVersion: route-info/v1\nkind: RouteInfo\nservice:\n  endpoints:\n  - addresses:\n    - 10.0.0.1\n    pod: pod-foo-4\n    ready: true\n  found: true\n  name: service-nodeport\n  namespace: default\n  pods:\n  - name: pod-foo-4\n    ready: true\n  ports:\n  - nodePort: 1234\n    port: 80\n    targetPort: http\n    targetPorts:\n    - found: false\n      pod: pod-foo-4\n  type: NodePort\n",
+		},
+		{
+			"service-clusterip-no-pods",
+			"apiVersion: route-info/v1\nkind: RouteInfo\nservice:\n  found: true\n  name: service-clusterip-no-pods\n  namespace: default\n  ports:\n  - port: 80\n    targetPort: \"80\"\n  - port: 443\n    targetPort: https\n  type: ClusterIP\n",
+		},
+		{
+			"service-externalname",
+			"apiVersion: route-info/v1\nkind: RouteInfo\nservice:\n  found: true\n  hostname: my.external.app.com\n  name: service-externalname\n  namespace: default\n  type: ExternalName\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewServiceMockClient()
+
+		service := NewService(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		service.PrintDocument(test.serviceName, "yaml", buf)
+
+		if buf.String() != test.expectedDocument {
+			t.Errorf("Returned document was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedDocument)
+		}
+	}
+}
+
+func TestServicePrintTableListSuccessful(t *testing.T) {
+
+	tests := []struct {
+		serviceNames  []string
+		expectedTable string
+	}{
+		{
+			[]string{"service-clusterip", "service-nodeport"},
+			"NAMESPACE   NAME                TYPE        PORT(S)                                POD(S)\ndefault     service-clusterip   ClusterIP   80 80,443 https=8443|9443 *Mismatch*   pod-foo-1,pod-foo-2,pod-foo-3\ndefault     service-nodeport    NodePort    80 http *Not found* node:1234          pod-foo-4\n",
+		},
+		{
+			nil,
+			"NAMESPACE   NAME                   TYPE           PORT(S)                                POD(S)/HOSTNAME\ndefault     service-externalname   ExternalName                                          my.external.app.com\ndefault     service-nodeport       NodePort       80 http *Not found* node:1234          pod-foo-4\nother       service-clusterip      ClusterIP      80 80,443 https=8443|9443 *Mismatch*   pod-foo-1,pod-foo-2,pod-foo-3\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewServiceMockClient()
+
+		service := NewService(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		service.PrintTableList(test.serviceNames, "", buf)
+
+		if buf.String() != test.expectedTable {
+			t.Errorf("Returned table was incorrect,\ngot:\n%swant:\n%s", buf.String(), test.expectedTable)
+		}
+	}
+}
+
+func TestServicePrintGraphListSuccessful(t *testing.T) {
+
+	tests := []struct {
+		serviceNames  []string
+		expectedGraph string
+	}{
+		{
+			nil,
+			"[Service]  service-externalname\n└── [Hostname]  my.external.app.com\n\n[Service]  service-nodeport\n└── [Pod]  pod-foo-4 (http *Not found*)\n\n[Service]  service-clusterip\n├── [Pod]  pod-foo-1 (https=app:8443)\n├── [Pod]  pod-foo-2 (https=app:8443)\n└── [Pod]  pod-foo-3 (https=app:9443 *Mismatch*)\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewServiceMockClient()
+
+		service := NewService(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		service.PrintGraphList(test.serviceNames, "", buf)
+
+		if buf.String() != test.expectedGraph {
+			t.Errorf("Returned tree graph was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedGraph)
+		}
+	}
+}
+
+func TestServicePrintExposureSuccessful(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		output   string
+		args     []string
+		expected string
+	}{
+		{
+			"table",
+			"",
+			[]string{"service", "foo"},
+			"NAME   TYPE           PORT(S)            EXTERNAL                                                                POD(S)\nfoo    LoadBalancer   80 80 node:30080   203.0.113.10:80,10.0.0.1:30080,198.51.100.1:30080 (from 192.0.2.0/24)   \n",
+		},
+		{
+			"yaml",
+			OutputYAML,
+			[]string{"service", "foo"},
+			"apiVersion: route-info/v1\nkind: RouteInfo\nservice:\n  exposure:\n    entrypoints:\n    - address: 203.0.113.10\n      port: 80\n      protocol: TCP\n      source: LoadBalancer\n    - address: 10.0.0.1\n      addressType: InternalIP\n      node: node-1\n      port: 30080\n      protocol: TCP\n      source: NodePort\n    - address: 198.51.100.1\n      addressType: ExternalIP\n      node: node-1\n      port: 30080\n      protocol: TCP\n      source: NodePort\n    loadBalancer:\n    - 203.0.113.10\n    sourceRanges:\n    - 192.0.2.0/24\n  found: true\n  name: foo\n  namespace: default\n  ports:\n  - nodePort: 30080\n    port: 80\n    protocol: TCP\n    targetPort: \"80\"\n  type: LoadBalancer\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		output := runOfflineResource(t, exposureManifests, test.output, test.args...)
+
+		if output != test.expected {
+			t.Errorf("Returned %s was incorrect, got: %q, want: %q", test.name, output, test.expected)
+		}
+	}
+}
+
+const wideManifests = `apiVersion: v1
+kind: Pod
+metadata:
+  name: foo-1
+  labels:
+    app: foo
+spec:
+  nodeName: node-1
+  containers:
+  - name: app
+    image: foo
+  - name: proxy
+    image: proxy
+status:
+  phase: Running
+  podIP: 10.1.0.1
+  conditions:
+  - type: Ready
+    status: "True"
+  containerStatuses:
+  - name: app
+    ready: true
+    restartCount: 2
+  - name: proxy
+    ready: true
+    restartCount: 1
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: foo-2
+  labels:
+    app: foo
+spec:
+  nodeName: node-2
+  containers:
+  - name: app
+    image: foo
+  - name: proxy
+    image: proxy
+status:
+  phase: Running
+  podIP: 10.1.0.2
+  conditions:
+  - type: Ready
+    status: "False"
+  containerStatuses:
+  - name: app
+    ready: false
+    restartCount: 5
+  - name: proxy
+    ready: true
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+spec:
+  selector:
+    app: foo
+  ports:
+  - port: 80
+    targetPort: 8080
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: my-ingress
+spec:
+  rules:
+  - host: foo.example.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: foo
+            port:
+              number: 80
+      - path: /missing
+        pathType: Prefix
+        backend:
+          service:
+            name: missing
+            port:
+              number: 80
+`
+
+func TestServicePrintWideSuccessful(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			"service",
+			[]string{"service", "foo"},
+			"NAME   TYPE        PORT(S)   POD(S)   IP         NODE     STATUS    READY   RESTARTS   AGE\nfoo    ClusterIP   80 8080   foo-1    10.1.0.1   node-1   Running   2/2     3          <unknown>\nfoo    ClusterIP   80 8080   foo-2    10.1.0.2   node-2   Running   1/2     5          <unknown>\n",
+		},
+		{
+			"services",
+			[]string{"service"},
+			"NAMESPACE   NAME   TYPE        PORT(S)   POD(S)   IP         NODE     STATUS    READY   RESTARTS   AGE\ndefault     foo    ClusterIP   80 8080   foo-1    10.1.0.1   node-1   Running   2/2     3          <unknown>\ndefault     foo    ClusterIP   80 8080   foo-2    10.1.0.2   node-2   Running   1/2     5          <unknown>\n",
+		},
+		{
+			"ingress",
+			[]string{"ingress", "my-ingress"},
+			"NAME         HOST              PATH       PORT   SERVICE               TYPE        SERVICE PORT(S)   POD(S)   IP         NODE     STATUS    READY   RESTARTS   AGE\nmy-ingress   foo.example.com   /          80     foo                   ClusterIP   80 8080           foo-1    10.1.0.1   node-1   Running   2/2     3          <unknown>\nmy-ingress   foo.example.com   /          80     foo                   ClusterIP   80 8080           foo-2    10.1.0.2   node-2   Running   1/2     5          <unknown>\nmy-ingress   foo.example.com   /missing   80     missing *Not found*                                                                                           \n",
+		},
+	}
+
+	for _, test := range tests {
+
+		output := runOfflineResource(t, wideManifests, OutputWide, test.args...)
+
+		if output != test.expected {
+			t.Errorf("Returned table for %s was incorrect, got: %q, want: %q", test.name, output, test.expected)
+		}
+	}
+}
+
+func TestServicePrintWideExpectedPods(t *testing.T) {
+
+	manifests := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+spec:
+  replicas: 1
+  template:
+    metadata:
+      labels:
+        app: foo
+    spec:
+      containers:
+      - name: app
+        image: foo
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+spec:
+  selector:
+    app: foo
+  ports:
+  - port: 80
+    targetPort: 8080
+`
+
+	output := runOfflineResource(t, manifests, OutputWide, "service", "foo")
+
+	expected := "NAME   TYPE        PORT(S)   POD(S)   IP    NODE   STATUS    READY   RESTARTS   AGE\nfoo    ClusterIP   80 8080   foo-0                 Running   1/1     0          <unknown>\n"
+	if output != expected {
+		t.Errorf("Returned table was incorrect, got: %q, want: %q", output, expected)
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"kube-route-info/pkg/route"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/yaml"
+)
+
+// Snapshot defines Snapshot atributes
+type Snapshot struct {
+	Resolver *route.Resolver
+}
+
+// NewSnapshot returns a new Snapshot struct
+func NewSnapshot(client route.ClientInterface, namespace string) *Snapshot {
+	return &Snapshot{
+		Resolver: route.NewResolver(client, namespace),
+	}
+}
+
+// Take returns a snapshot of the route graph of the ingresses and services that match a label
+// selector, in the namespace of the resolver or across all namespaces when it is empty
+func (s *Snapshot) Take(selector string) (*route.Document, error) {
+
+	ingresses, err := s.Resolver.ResolveIngresses(nil, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := s.Resolver.ResolveServices(nil, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return route.NewSnapshot(ingresses, services, time.Now()), nil
+}
+
+// LoadSnapshot returns the route snapshot saved in a JSON or YAML file
+func LoadSnapshot(filename string) (*route.Document, error) {
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &route.Document{}
+	if err := yaml.Unmarshal(data, document); err != nil {
+		return nil, fmt.Errorf("unable to read route snapshot %s: %v", filename, err)
+	}
+
+	if document.APIVersion != route.DocumentAPIVersion || document.Kind != route.SnapshotKind {
+		return nil, fmt.Errorf("%s is not a %s %s document", filename, route.DocumentAPIVersion, route.SnapshotKind)
+	}
+
+	return document, nil
+}
+
+// PrintDiff prints the changes between two route snapshots in table format,
+// or in a machine-readable format when an output is given
+func PrintDiff(diff *route.Diff, output string, w io.Writer) error {
+
+	if output != "" {
+		return printObject(diff, output, w)
+	}
+
+	if len(diff.Changes) == 0 {
+		fmt.Fprintln(w, "No changes found in the route graph")
+		return nil
+	}
+
+	rows := []metav1.TableRow{}
+
+	for _, change := range diff.Changes {
+		rows = append(rows, metav1.TableRow{
+			Cells: []interface{}{
+				change.Type,
+				change.Kind,
+				change.Object,
+				change.Name,
+				ChangeDetailsToString(change),
+			},
+		})
+	}
+
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Change", Type: "string"},
+			{Name: "Kind", Type: "string"},
+			{Name: "Object", Type: "string"},
+			{Name: "Name", Type: "string"},
+			{Name: "Details", Type: "string"},
+		},
+		Rows: rows,
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	printer.PrintObj(table, out)
+
+	fmt.Fprint(w, out.String())
+
+	return nil
+}
+
+// ChangeDetailsToString returns the old and new values of a change, such as
+// "foo:80 -> bar:80" for modified backends or "+pod-3 -pod-1" for pod churn
+func ChangeDetailsToString(change route.Change) string {
+
+	if len(change.Added) > 0 || len(change.Removed) > 0 {
+		pods := []string{}
+		for _, name := range change.Added {
+			pods = append(pods, "+"+name)
+		}
+		for _, name := range change.Removed {
+			pods = append(pods, "-"+name)
+		}
+
+		return strings.Join(pods, " ")
+	}
+
+	switch change.Type {
+	case route.ChangeAdded:
+		return change.To
+	case route.ChangeRemoved:
+		return change.From
+	}
+
+	return change.From + " -> " + change.To
+}
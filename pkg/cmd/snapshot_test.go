@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// runOfflineResource runs the command with the given output format and arguments against
+// the objects of a manifest file and returns its output
+func runOfflineResource(t *testing.T, manifests string, output string, args ...string) string {
+	filename := filepath.Join(t.TempDir(), "manifests.yaml")
+	if err := ioutil.WriteFile(filename, []byte(manifests), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+	r := newNamespaceResource(t, "context-without-namespace", "")
+	r.IOStreams = streams
+	r.filenames = []string{filename}
+	r.output = output
+
+	if err := r.Validate(args); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := r.Complete(nil, args); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := r.Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return out.String()
+}
+
+// timestampPattern matches the snapshot timestamps, which change on every run
+var timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z`)
+
+func TestSnapshotPrintDiffSuccessful(t *testing.T) {
+
+	before := offlineManifests + "---\n" + offlineIngress
+
+	after := strings.Replace(before, "replicas: 2", "replicas: 3", 1)
+	after = strings.Replace(after, "port:\n              number: 80", "port:\n              number: 8080", 1)
+	after = strings.Replace(after, "    port: 80\n", "    port: 8080\n", 1)
+
+	dir := t.TempDir()
+	beforeSnapshot := filepath.Join(dir, "before.json")
+	afterSnapshot := filepath.Join(dir, "after.yaml")
+
+	if err := ioutil.WriteFile(beforeSnapshot, []byte(runOfflineResource(t, before, "", "snapshot")), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := ioutil.WriteFile(afterSnapshot, []byte(runOfflineResource(t, after, OutputYAML, "snapshot")), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		manifests string
+		output    string
+		args      []string
+		expected  string
+	}{
+		{
+			"snapshot against cluster",
+			after,
+			"",
+			[]string{"diff", beforeSnapshot},
+			"CHANGE     KIND      OBJECT                       NAME                               DETAILS\nModified   Backend   Ingress/default/my-ingress   foo.example.com/missing (Prefix)   missing:80 -> missing:8080\nModified   Port      Service/default/foo          http                               80:http/TCP -> 8080:http/TCP\nModified   Pods      Service/default/foo                                             +foo-2\n",
+		},
+		{
+			"snapshot against unchanged cluster",
+			before,
+			"",
+			[]string{"diff", beforeSnapshot},
+			"No changes found in the route graph\n",
+		},
+		{
+			"two snapshots",
+			"",
+			OutputJSON,
+			[]string{"diff", beforeSnapshot, afterSnapshot},
+			"{\n    \"apiVersion\": \"route-info/v1\",\n    \"kind\": \"RouteDiff\",\n    \"from\": \"<timestamp>\",\n    \"to\": \"<timestamp>\",\n    \"changes\": [\n        {\n            \"type\": \"Modified\",\n            \"kind\": \"Backend\",\n            \"object\": \"Ingress/default/my-ingress\",\n            \"name\": \"foo.example.com/missing (Prefix)\",\n            \"from\": \"missing:80\",\n            \"to\": \"missing:8080\"\n        },\n        {\n            \"type\": \"Modified\",\n            \"kind\": \"Port\",\n            \"object\": \"Service/default/foo\",\n            \"name\": \"http\",\n            \"from\": \"80:http/TCP\",\n            \"to\": \"8080:http/TCP\"\n        },\n        {\n            \"type\": \"Modified\",\n            \"kind\": \"Pods\",\n            \"object\": \"Service/default/foo\",\n            \"added\": [\n                \"foo-2\"\n            ]\n        }\n    ]\n}\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		output := runOfflineResource(t, test.manifests, test.output, test.args...)
+		output = timestampPattern.ReplaceAllString(output, "<timestamp>")
+
+		if output != test.expected {
+			t.Errorf("Returned diff for %s was incorrect, got: %q, want: %q", test.name, output, test.expected)
+		}
+	}
+}
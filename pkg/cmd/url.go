@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"kube-route-info/pkg/route"
+
+	"github.com/xlab/treeprint"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// URL defines URL attributes
+type URL struct {
+	Resolver   *route.Resolver
+	Candidates bool
+}
+
+// NewURL returns a new URL struct
+func NewURL(client route.ClientInterface, namespace string, candidates bool) *URL {
+	return &URL{
+		Resolver:   route.NewResolver(client, namespace),
+		Candidates: candidates,
+	}
+}
+
+// ResetCache drops the cluster scoped objects cached by the resolver, so that they are read again
+func (u *URL) ResetCache() {
+	u.Resolver.ResetCache()
+}
+
+// PrintGraph prints the ingress path that serves a URL in a tree graph format
+func (u *URL) PrintGraph(rawURL string, w io.Writer) (err error) {
+
+	urlRoute, err := u.Resolver.ResolveURL(rawURL, u.Candidates)
+	if err != nil {
+		return err
+	}
+
+	tree := treeprint.New()
+
+	urlBranch := tree.AddMetaBranch("URL", urlRoute.URL)
+
+	addCandidateBranch(urlBranch, "Ingress", *urlRoute.Match)
+
+	for _, candidate := range urlRoute.Candidates {
+		addCandidateBranch(urlBranch, "Candidate", candidate)
+	}
+
+	fmt.Fprint(w, urlBranch.String())
+
+	return
+}
+
+// PrintTable prints the ingress path that serves a URL in table format
+func (u *URL) PrintTable(rawURL string, w io.Writer) (err error) {
+
+	urlRoute, err := u.Resolver.ResolveURL(rawURL, u.Candidates)
+	if err != nil {
+		return err
+	}
+
+	rows := []metav1.TableRow{
+		{Cells: candidateCells("selected", *urlRoute.Match)},
+	}
+
+	// Default column name for pods
+	podColumnName := "Pod(s)"
+
+	if urlRoute.Match.Service.Hostname != "" {
+		podColumnName = "Pod(s)/Hostname"
+	}
+
+	for _, candidate := range urlRoute.Candidates {
+
+		if candidate.Service.Hostname != "" {
+			podColumnName = "Pod(s)/Hostname"
+		}
+
+		rows = append(rows, metav1.TableRow{Cells: candidateCells("candidate", candidate)})
+	}
+
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Match", Type: "string"},
+			{Name: "Name", Type: "string"},
+			{Name: "Host", Type: "string"},
+			{Name: "Path", Type: "string"},
+			{Name: "Path Type", Type: "string"},
+			{Name: "Port", Type: "string"},
+			{Name: "Service", Type: "string"},
+			{Name: "Type", Type: "string"},
+			{Name: "Service Port(s)", Type: "string"},
+			{Name: podColumnName, Type: "string"},
+		},
+		Rows: rows,
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	printer.PrintObj(table, out)
+
+	fmt.Fprint(w, out.String())
+
+	return
+}
+
+// PrintDocument prints the ingress path that serves a URL in a machine-readable format
+func (u *URL) PrintDocument(rawURL string, output string, w io.Writer) error {
+
+	urlRoute, err := u.Resolver.ResolveURL(rawURL, u.Candidates)
+	if err != nil {
+		return err
+	}
+
+	document := route.NewDocument()
+	document.URL = urlRoute
+
+	return PrintDocument(document, output, w)
+}
+
+func addCandidateBranch(tree treeprint.Tree, meta string, candidate route.URLCandidate) {
+	ingressBranch := tree.AddMetaBranch(meta, candidate.Ingress)
+
+	if candidate.DefaultBackend {
+		AddServiceBranch(ingressBranch.AddBranch("[Default backend]"), candidate.Service)
+		return
+	}
+
+	hostBranch := ingressBranch.AddBranch(candidate.Host)
+	pathBranch := hostBranch.AddMetaBranch(candidate.PathType, candidate.Path)
+
+	AddServiceBranch(pathBranch, candidate.Service)
+}
+
+func candidateCells(match string, candidate route.URLCandidate) []interface{} {
+	host, path := candidate.Host, candidate.Path
+	if candidate.DefaultBackend {
+		host, path = "*", "(default backend)"
+	}
+
+	return []interface{}{
+		match,
+		candidate.Ingress,
+		host,
+		path,
+		candidate.PathType,
+		candidate.BackendPort,
+		ServiceNameToString(candidate.Service),
+		candidate.Service.Type,
+		PortsToString(candidate.Service.Ports),
+		ServiceTargetsToString(candidate.Service),
+	}
+}
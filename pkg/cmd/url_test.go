@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"kube-route-info/pkg/route"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// Mock client struct
+type URLMockClient struct {
+	BaseMockClient
+}
+
+func NewURLMockClient() *URLMockClient {
+	return &URLMockClient{}
+}
+
+func (c *URLMockClient) GetPodsByLabels(labels map[string]string) (*v1.PodList, error) {
+	return &v1.PodList{
+		Items: []v1.Pod{
+			{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod-" + labels["app"] + "-1",
+					Namespace: "default",
+					Labels:    labels,
+				},
+			},
+		},
+	}, nil
+}
+
+func (c *URLMockClient) GetServiceByName(name string) (*v1.Service, error) {
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Type: v1.ServiceTypeClusterIP,
+			Ports: []v1.ServicePort{
+				{Port: 80, TargetPort: intstr.IntOrString{Type: 0, IntVal: 8080}},
+			},
+			Selector: map[string]string{
+				"app": name[len("service-"):],
+			},
+		},
+	}, nil
+}
+
+func (c *URLMockClient) ListIngresses(selector string) (*networkingv1.IngressList, error) {
+	exact := networkingv1.PathTypeExact
+	prefix := networkingv1.PathTypePrefix
+
+	newPath := func(path string, pathType *networkingv1.PathType, serviceName string) networkingv1.HTTPIngressPath {
+		return networkingv1.HTTPIngressPath{
+			Path:     path,
+			PathType: pathType,
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: serviceName,
+					Port: networkingv1.ServiceBackendPort{Number: 80},
+				},
+			},
+		}
+	}
+
+	newIngress := func(name string, host string, paths ...networkingv1.HTTPIngressPath) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					{
+						Host: host,
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// Requests of the wildcard host that match no path are sent to its default backend
+	wildcard := newIngress("ingress-wildcard", "*.example.com", newPath("/api/cart", &prefix, "service-wildcard"))
+	fallback := newPath("", nil, "service-fallback")
+	wildcard.Spec.DefaultBackend = &fallback.Backend
+
+	return &networkingv1.IngressList{
+		Items: []networkingv1.Ingress{
+			newIngress("ingress-catch-all", "", newPath("/", &prefix, "service-default")),
+			wildcard,
+			newIngress("ingress-shop", "shop.example.com",
+				newPath("/", &prefix, "service-web"),
+				newPath("/api", &prefix, "service-api"),
+				newPath("/api/cart", &exact, "service-cart"),
+			),
+		},
+	}, nil
+}
+
+func (c *URLMockClient) GetEndpointSlicesByService(name string) (*discoveryv1.EndpointSliceList, error) {
+	service, _ := c.GetServiceByName(name)
+	pods, _ := c.GetPodsByLabels(service.Spec.Selector)
+	return newEndpointSlices(pods), nil
+}
+
+func (c *URLMockClient) Namespaced(namespace string) route.ClientInterface { return c }
+
+func TestURLPrintGraphSuccessful(t *testing.T) {
+
+	tests := []struct {
+		url           string
+		expectedGraph string
+	}{
+		{
+			"https://shop.example.com/api/cart",
+			"[URL]  https://shop.example.com/api/cart\n└── [Ingress]  ingress-shop\n    └── shop.example.com\n        └── [Exact]  /api/cart\n            └── [Service]  service-cart\n                └── [Pod]  pod-cart-1\n",
+		},
+		{
+			"https://shop.example.com/api/cart/items",
+			"[URL]  https://shop.example.com/api/cart/items\n└── [Ingress]  ingress-shop\n    └── shop.example.com\n        └── [Prefix]  /api\n            └── [Service]  service-api\n                └── [Pod]  pod-api-1\n",
+		},
+		{
+			"http://blog.example.com:8080/api/cart/items",
+			"[URL]  http://blog.example.com:8080/api/cart/items\n└── [Ingress]  ingress-wildcard\n    └── *.example.com\n        └── [Prefix]  /api/cart\n            └── [Service]  service-wildcard\n                └── [Pod]  pod-wildcard-1\n",
+		},
+		{
+			"http://blog.example.com/about",
+			"[URL]  http://blog.example.com/about\n└── [Ingress]  ingress-wildcard\n    └── [Default backend]\n        └── [Service]  service-fallback\n            └── [Pod]  pod-fallback-1\n",
+		},
+		{
+			"http://example.org",
+			"[URL]  http://example.org\n└── [Ingress]  ingress-catch-all\n    └── \n        └── [Prefix]  /\n            └── [Service]  service-default\n                └── [Pod]  pod-default-1\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewURLMockClient()
+
+		url := NewURL(mockClient, "default", false)
+
+		buf := &bytes.Buffer{}
+
+		url.PrintGraph(test.url, buf)
+
+		if buf.String() != test.expectedGraph {
+			t.Errorf("Returned tree graph was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedGraph)
+		}
+	}
+}
+
+func TestURLPrintTableCandidatesSuccessful(t *testing.T) {
+
+	tests := []struct {
+		url           string
+		expectedTable string
+	}{
+		{
+			"https://shop.example.com/api/cart",
+			"MATCH       NAME                HOST               PATH                PATH TYPE   PORT   SERVICE            TYPE        SERVICE PORT(S)   POD(S)\nselected    ingress-shop        shop.example.com   /api/cart           Exact       80     service-cart       ClusterIP   80 8080           pod-cart-1\ncandidate   ingress-shop        shop.example.com   /api                Prefix      80     service-api        ClusterIP   80 8080           pod-api-1\ncandidate   ingress-shop        shop.example.com   /                   Prefix      80     service-web        ClusterIP   80 8080           pod-web-1\ncandidate   ingress-wildcard    *.example.com      /api/cart           Prefix      80     service-wildcard   ClusterIP   80 8080           pod-wildcard-1\ncandidate   ingress-wildcard    *                  (default backend)               80     service-fallback   ClusterIP   80 8080           pod-fallback-1\ncandidate   ingress-catch-all                      /                   Prefix      80     service-default    ClusterIP   80 8080           pod-default-1\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewURLMockClient()
+
+		url := NewURL(mockClient, "default", true)
+
+		buf := &bytes.Buffer{}
+
+		url.PrintTable(test.url, buf)
+
+		if buf.String() != test.expectedTable {
+			t.Errorf("Returned table was incorrect,\ngot:\n%swant:\n%s", buf.String(), test.expectedTable)
+		}
+	}
+}
@@ -1,19 +1,25 @@
 package cmd
 
 import (
+	"net"
 	"strconv"
+	"strings"
+	"time"
 
-	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
+	"kube-route-info/pkg/route"
+
+	"github.com/xlab/treeprint"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
 )
 
 // PodsToString returns a string of pod names separated by semicolons
-func PodsToString(pods *v1.PodList) (podsString string) {
+func PodsToString(pods []route.Pod) (podsString string) {
 	podsString = ""
-	arrayLength := len(pods.Items)
+	arrayLength := len(pods)
 
-	for index, pod := range pods.Items {
-		podsString += pod.Name
+	for index, pod := range pods {
+		podsString += PodToString(pod)
 
 		if (index + 1) != arrayLength {
 			podsString += ","
@@ -23,8 +29,39 @@ func PodsToString(pods *v1.PodList) (podsString string) {
 	return
 }
 
+// PodToString returns the pod name, flagged when the pod is terminating or not ready
+func PodToString(pod route.Pod) string {
+	switch {
+	case pod.Terminating:
+		return pod.Name + " (terminating)"
+	case !pod.Ready:
+		return pod.Name + " (not ready)"
+	}
+
+	return pod.Name
+}
+
+// EndpointsToString returns a string of the addresses of the endpoints
+// that are not backed by pods separated by commas
+func EndpointsToString(endpoints []route.Endpoint) (endpointsString string) {
+	for _, endpoint := range endpoints {
+		if endpoint.Pod != "" {
+			continue
+		}
+
+		for _, address := range endpoint.Addresses {
+			if endpointsString != "" {
+				endpointsString += ","
+			}
+			endpointsString += address
+		}
+	}
+
+	return
+}
+
 // PortsToString returns a string of ports separated by semicolons
-func PortsToString(ports []v1.ServicePort) (portsString string) {
+func PortsToString(ports []route.Port) (portsString string) {
 	portsString = ""
 	arrayLength := len(ports)
 
@@ -32,10 +69,10 @@ func PortsToString(ports []v1.ServicePort) (portsString string) {
 
 		portsString += strconv.FormatInt(int64(port.Port), 10) + " "
 
-		portsString += PortToString(port.TargetPort.Type, port.TargetPort.StrVal, port.TargetPort.IntVal)
+		portsString += port.TargetPort + TargetPortsToString(port.TargetPorts)
 
 		if port.NodePort != 0 {
-			portsString += " " + strconv.FormatInt(int64(port.NodePort), 10)
+			portsString += " node:" + strconv.FormatInt(int64(port.NodePort), 10)
 		}
 
 		if (index + 1) != arrayLength {
@@ -47,30 +84,935 @@ func PortsToString(ports []v1.ServicePort) (portsString string) {
 	return
 }
 
-// PortToString returns a string port
-func PortToString(portType intstr.Type, strVal string, intVal int32) (portString string) {
-	switch portType {
-	case 0:
-		portString = strconv.FormatInt(int64(intVal), 10)
-	case 1:
-		portString = strVal
+// TargetPortsToString returns the port numbers a named target port resolves to, e.g. =8080.
+// Target ports that do not resolve on any pod, or resolve to different numbers or
+// not at all on some of the pods are flagged
+func TargetPortsToString(targetPorts []route.TargetPort) string {
+	if len(targetPorts) == 0 {
+		return ""
 	}
 
-	return
+	numbers := []string{}
+	mismatch := false
+
+	for _, targetPort := range targetPorts {
+		if !targetPort.Found {
+			mismatch = true
+			continue
+		}
+
+		number := strconv.FormatInt(int64(targetPort.Port), 10)
+		if !containsString(numbers, number) {
+			numbers = append(numbers, number)
+		}
+	}
+
+	if len(numbers) == 0 {
+		return " *Not found*"
+	}
+
+	if mismatch || len(numbers) > 1 {
+		return "=" + strings.Join(numbers, "|") + " *Mismatch*"
+	}
+
+	return "=" + numbers[0]
 }
 
-// ServiceTypeToString returns a string service type
-func ServiceTypeToString(serviceType v1.ServiceType) (serviceTypeString string) {
-	switch serviceType {
-	case v1.ServiceTypeLoadBalancer:
-		serviceTypeString = "LoadBalancer"
-	case v1.ServiceTypeNodePort:
-		serviceTypeString = "NodePort"
-	case v1.ServiceTypeClusterIP:
-		serviceTypeString = "ClusterIP"
-	case v1.ServiceTypeExternalName:
-		serviceTypeString = "ExternalName"
+// PodTargetPortsToString returns the container ports the named target ports of a service resolve to
+// on a pod, e.g. http=web:8080, flagged when they do not resolve or differ from the other pods
+func PodTargetPortsToString(service *route.Service, pod string) string {
+	targets := []string{}
+
+	for _, port := range service.Ports {
+		for _, targetPort := range port.TargetPorts {
+			if targetPort.Pod != pod {
+				continue
+			}
+
+			switch {
+			case !targetPort.Found:
+				targets = append(targets, port.TargetPort+" *Not found*")
+			case targetPort.Mismatch:
+				targets = append(targets, port.TargetPort+"="+targetPort.Container+":"+strconv.FormatInt(int64(targetPort.Port), 10)+" *Mismatch*")
+			default:
+				targets = append(targets, port.TargetPort+"="+targetPort.Container+":"+strconv.FormatInt(int64(targetPort.Port), 10))
+			}
+		}
 	}
 
-	return
+	return strings.Join(targets, ", ")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ServiceNameToString returns the service name, flagged when the service does not exist.
+// Resource backends are not resolved, so they are never flagged
+func ServiceNameToString(service *route.Service) string {
+	if !service.Found && service.Type != route.BackendTypeResource {
+		return service.Name + " *Not found*"
+	}
+
+	return service.Name
+}
+
+// ServiceTargetsToString returns the pods and endpoints, or the external hostname behind a service
+func ServiceTargetsToString(service *route.Service) string {
+	if service.Hostname != "" {
+		return service.Hostname
+	}
+
+	targets := PodsToString(service.Pods)
+	endpoints := EndpointsToString(service.Endpoints)
+
+	if targets != "" && endpoints != "" {
+		targets += ","
+	}
+
+	return targets + endpoints
+}
+
+// AddServiceBranch adds a service branch with its pods and endpoints, or external hostname to a tree graph
+func AddServiceBranch(tree treeprint.Tree, service *route.Service) treeprint.Tree {
+	return addServiceBranch(tree, service, "")
+}
+
+// AddPathServiceBranch adds the service branch of a path to a tree graph,
+// along with the subset and weight of the path backend when they are set
+func AddPathServiceBranch(tree treeprint.Tree, path route.Path) treeprint.Tree {
+	details := []string{}
+
+	if path.Subset != nil {
+		details = append(details, "subset "+SubsetToString(path.Subset))
+	}
+
+	if path.Weight != nil {
+		details = append(details, "weight "+WeightToString(path.Weight))
+	}
+
+	if len(details) == 0 {
+		return addServiceBranch(tree, path.Service, "")
+	}
+
+	return addServiceBranch(tree, path.Service, " ("+strings.Join(details, ", ")+")")
+}
+
+// SubsetToString returns the subset name, flagged when the subset is not defined
+func SubsetToString(subset *route.Subset) string {
+	if subset == nil {
+		return ""
+	}
+
+	if !subset.Found {
+		return subset.Name + " *Not found*"
+	}
+
+	return subset.Name
+}
+
+func addServiceBranch(tree treeprint.Tree, service *route.Service, suffix string) treeprint.Tree {
+	if service.Type == route.BackendTypeResource {
+		return tree.AddMetaBranch("Resource", service.Name+suffix)
+	}
+
+	if !service.Found {
+		return tree.AddMetaBranch("Service", ServiceNameToString(service)+suffix)
+	}
+
+	serviceBranch := tree.AddMetaBranch("Service", service.Name+suffix)
+
+	if service.Hostname != "" {
+		serviceBranch.AddMetaNode("Hostname", service.Hostname)
+	}
+
+	addExposureNodes(serviceBranch, service.Exposure)
+
+	for _, pod := range service.Pods {
+		if targets := PodTargetPortsToString(service, pod.Name); targets != "" {
+			serviceBranch.AddMetaNode("Pod", PodToString(pod)+" ("+targets+")")
+			continue
+		}
+
+		serviceBranch.AddMetaNode("Pod", PodToString(pod))
+	}
+
+	for _, endpoint := range service.Endpoints {
+		if endpoint.Pod == "" {
+			serviceBranch.AddMetaNode("Endpoint", strings.Join(endpoint.Addresses, ","))
+		}
+	}
+
+	return serviceBranch
+}
+
+// addExposureNodes adds the entrypoints of a service or an ingress to its branch,
+// along with the source ranges allowed by its load balancer
+func addExposureNodes(tree treeprint.Tree, exposure *route.Exposure) {
+	if exposure == nil {
+		return
+	}
+
+	if exposure.Pending {
+		tree.AddMetaNode(route.EntrypointSourceLoadBalancer, "<pending>")
+	}
+
+	for _, entrypoint := range exposure.Entrypoints {
+		if entrypoint.Node != "" {
+			tree.AddMetaNode(entrypoint.Source, EntrypointToString(entrypoint)+" ("+entrypoint.Node+" "+entrypoint.AddressType+")")
+			continue
+		}
+
+		tree.AddMetaNode(entrypoint.Source, EntrypointToString(entrypoint))
+	}
+
+	if len(exposure.SourceRanges) > 0 {
+		tree.AddMetaNode("Source ranges", strings.Join(exposure.SourceRanges, ","))
+	}
+}
+
+// EntrypointToString returns the address and port of an entrypoint, such as
+// "203.0.113.10:80" or "[2001:db8::1]:30080" for IPv6 addresses
+func EntrypointToString(entrypoint route.Entrypoint) string {
+	return net.JoinHostPort(entrypoint.Address, strconv.FormatInt(int64(entrypoint.Port), 10))
+}
+
+// ExposureToString returns the entrypoints of a service separated by commas, with <pending>
+// for load balancers not provisioned yet, along with the source ranges allowed by its load
+// balancer, e.g. "203.0.113.10:80,10.0.0.1:30080 (from 192.0.2.0/24)"
+func ExposureToString(exposure *route.Exposure) string {
+	if exposure == nil {
+		return ""
+	}
+
+	entrypoints := []string{}
+
+	if exposure.Pending {
+		entrypoints = append(entrypoints, "<pending>")
+	}
+
+	for _, entrypoint := range exposure.Entrypoints {
+		entrypoints = append(entrypoints, EntrypointToString(entrypoint))
+	}
+
+	exposureString := strings.Join(entrypoints, ",")
+
+	if len(exposure.SourceRanges) > 0 {
+		exposureString += " (from " + strings.Join(exposure.SourceRanges, ",") + ")"
+	}
+
+	return exposureString
+}
+
+// LoadBalancerToString returns the load balancer addresses of an ingress separated by commas
+func LoadBalancerToString(exposure *route.Exposure) string {
+	if exposure == nil {
+		return ""
+	}
+
+	return strings.Join(exposure.LoadBalancer, ",")
+}
+
+// RouteKindToString returns the kind of a route, which is only set for objects other than ingresses
+func RouteKindToString(r *route.Route) string {
+	if r.Kind == "" {
+		return "Ingress"
+	}
+
+	return r.Kind
+}
+
+// MatchToString returns the protocol and extra request conditions of a path separated by commas
+func MatchToString(path route.Path) string {
+	conditions := []string{}
+
+	if path.Protocol != "" {
+		conditions = append(conditions, "protocol="+path.Protocol)
+	}
+
+	if path.Method != "" {
+		conditions = append(conditions, "method="+path.Method)
+	}
+
+	for _, header := range path.Headers {
+		conditions = append(conditions, "header:"+header)
+	}
+
+	for _, queryParam := range path.QueryParams {
+		conditions = append(conditions, "query:"+queryParam)
+	}
+
+	conditions = append(conditions, path.Conditions...)
+
+	return strings.Join(conditions, ",")
+}
+
+// WeightToString returns the weight of a path backend, or an empty string when it is not set
+func WeightToString(weight *int32) string {
+	if weight == nil {
+		return ""
+	}
+
+	return strconv.FormatInt(int64(*weight), 10)
+}
+
+// AddHostBranches adds the host, path and service branches of a route to a tree graph.
+// Paths with the same match share a branch, with a service branch per backend
+func AddHostBranches(tree treeprint.Tree, r *route.Route) {
+	for _, host := range r.Hosts {
+
+		hostBranch := tree.AddBranch(host.Host)
+		if host.TLS != nil {
+			addTLSBranch(hostBranch, host.TLS)
+		}
+
+		for _, warning := range host.Warnings {
+			hostBranch.AddMetaNode("Warning", warning)
+		}
+
+		pathBranches := map[string]treeprint.Tree{}
+
+		for _, path := range host.Paths {
+
+			label := path.Path
+			if match := MatchToString(path); match != "" {
+				label = strings.TrimSpace(label + " [" + match + "]")
+			}
+
+			pathBranch, ok := pathBranches[label]
+			if !ok || path.Weight == nil {
+				pathBranch = hostBranch.AddBranch(label)
+				pathBranches[label] = pathBranch
+			}
+
+			if !ok || path.Weight == nil {
+				addPolicyNodes(pathBranch, path)
+			}
+
+			AddPathServiceBranch(pathBranch, path)
+		}
+	}
+
+	if r.DefaultBackend != nil {
+		AddPathServiceBranch(tree.AddBranch("[Default backend]"), *r.DefaultBackend)
+	}
+}
+
+// TableHosts returns the hosts of a route as printed in tables, where the default
+// backend is a host of its own that matches any host and any path
+func TableHosts(r *route.Route) []route.Host {
+	if r.DefaultBackend == nil {
+		return r.Hosts
+	}
+
+	defaultBackend := *r.DefaultBackend
+	defaultBackend.Path = "(default backend)"
+
+	return append(append([]route.Host{}, r.Hosts...), route.Host{
+		Host:  "*",
+		Paths: []route.Path{defaultBackend},
+	})
+}
+
+// IngressPathToHostAndPath returns the host and path of an ingress path as printed in tables,
+// where default backends are printed as the "*" host like in the tables of ingresses
+func IngressPathToHostAndPath(path route.IngressPath) (string, string) {
+	if path.DefaultBackend {
+		return "*", "(default backend)"
+	}
+
+	return path.Host, path.Path
+}
+
+// TLSToString returns the TLS secret of a host or, when it has none, its TLS
+// termination along with the policy applied to insecure requests
+func TLSToString(tls *route.TLS) string {
+	if tls == nil {
+		return ""
+	}
+
+	if tls.Secret != nil {
+		return SecretToString(tls.Secret)
+	}
+
+	if tls.InsecureEdgeTerminationPolicy == "" {
+		return tls.Termination
+	}
+
+	return tls.Termination + " (insecure " + tls.InsecureEdgeTerminationPolicy + ")"
+}
+
+// SecretToString returns the name of a TLS secret, flagged when the secret does not exist or
+// can not be read, along with the expiry date of its certificate
+func SecretToString(secret *route.Secret) string {
+	switch {
+	case secret.Forbidden:
+		return secret.Name + " *Forbidden*"
+	case !secret.Found:
+		return secret.Name + " *Not found*"
+	case secret.Certificate != nil:
+		return secret.Name + " (expires " + secret.Certificate.NotAfter.Format("2006-01-02") + ")"
+	}
+
+	return secret.Name
+}
+
+// addTLSBranch adds the TLS configuration of a host to its branch, with
+// the details of the certificate of its secret when it could be parsed
+func addTLSBranch(tree treeprint.Tree, tls *route.TLS) {
+	if tls.Secret == nil || tls.Secret.Certificate == nil {
+		tree.AddMetaNode("TLS", TLSToString(tls))
+		return
+	}
+
+	certificate := tls.Secret.Certificate
+	tlsBranch := tree.AddMetaBranch("TLS", tls.Secret.Name)
+
+	tlsBranch.AddMetaNode("Subject", certificate.Subject)
+
+	if len(certificate.DNSNames) > 0 {
+		tlsBranch.AddMetaNode("SANs", strings.Join(certificate.DNSNames, ","))
+	}
+
+	tlsBranch.AddMetaNode("Issuer", certificate.Issuer)
+	tlsBranch.AddMetaNode("Expires", certificate.NotAfter.Format(time.RFC3339))
+}
+
+// addIngressClassBranch adds the ingress class of an ingress, its controller,
+// parameters and warnings to the branch of the ingress
+func addIngressClassBranch(tree treeprint.Tree, ingressClass *route.IngressClass) {
+	classBranch := tree.AddMetaBranch("IngressClass", IngressClassToString(ingressClass))
+
+	if ingressClass.Controller != "" {
+		classBranch.AddMetaNode("Controller", ingressClass.Controller)
+	}
+
+	if ingressClass.Parameters != "" {
+		classBranch.AddMetaNode("Parameters", ingressClass.Parameters)
+	}
+
+	for _, warning := range ingressClass.Warnings {
+		classBranch.AddMetaNode("Warning", warning)
+	}
+}
+
+// IngressClassToString returns the name of the class of an ingress along with where it
+// is taken from, such as "nginx (default)", and whether its IngressClass is missing or can not be read
+func IngressClassToString(ingressClass *route.IngressClass) string {
+	if ingressClass != nil && ingressClass.Forbidden && ingressClass.Name == "" {
+		return "<unknown>"
+	}
+
+	if ingressClass == nil || ingressClass.Name == "" {
+		return "<none>"
+	}
+
+	name := ingressClass.Name
+
+	switch ingressClass.Source {
+	case route.IngressClassSourceAnnotation:
+		name += " (annotation)"
+	case route.IngressClassSourceDefault:
+		name += " (default)"
+	}
+
+	switch {
+	case ingressClass.Forbidden:
+		name += " *Forbidden*"
+	case !ingressClass.Found:
+		name += " *Not found*"
+	}
+
+	return name
+}
+
+// addPolicyNodes adds the delegated proxy, filters, and rewrite and retry policies of a path to its branch
+func addPolicyNodes(tree treeprint.Tree, path route.Path) {
+	if path.Include != "" {
+		tree.AddMetaNode("Include", path.Include)
+	}
+
+	for _, filter := range path.Filters {
+		tree.AddMetaNode("Filter", filter)
+	}
+
+	if path.Rewrite != "" {
+		tree.AddMetaNode("Rewrite", path.Rewrite)
+	}
+
+	if path.Retries != "" {
+		tree.AddMetaNode("Retries", path.Retries)
+	}
+
+	if settings := BehaviorSettings(path.Behavior); len(settings) > 0 {
+		behaviorBranch := tree.AddBranch("[Behavior]")
+		for _, setting := range settings {
+			behaviorBranch.AddMetaNode(setting.Name, setting.Value)
+		}
+	}
+}
+
+// BehaviorSetting defines a setting of the effective behavior of a path as printed
+type BehaviorSetting struct {
+	Name  string
+	Value string
+}
+
+// BehaviorSettings returns the settings of the effective behavior of a path in a readable format,
+// such as "Canary" with "weight 20/100, header X-Canary" or "Rate limit" with "10 rps, burst x5"
+func BehaviorSettings(behavior *route.Behavior) []BehaviorSetting {
+	if behavior == nil {
+		return nil
+	}
+
+	settings := []BehaviorSetting{}
+
+	add := func(name string, values ...string) {
+		nonEmpty := []string{}
+		for _, value := range values {
+			if value != "" {
+				nonEmpty = append(nonEmpty, value)
+			}
+		}
+
+		if len(nonEmpty) > 0 {
+			settings = append(settings, BehaviorSetting{Name: name, Value: strings.Join(nonEmpty, ", ")})
+		}
+	}
+
+	add("Rewrite", behavior.Rewrite)
+
+	if behavior.ForceSSLRedirect {
+		add("SSL redirect", "forced")
+	} else if behavior.SSLRedirect != nil {
+		add("SSL redirect", strconv.FormatBool(*behavior.SSLRedirect))
+	}
+
+	add("Backend protocol", behavior.BackendProtocol)
+
+	if canary := behavior.Canary; canary != nil {
+		weight := ""
+		if canary.Weight != nil {
+			weight = "weight " + strconv.Itoa(int(*canary.Weight)) + "/" + strconv.Itoa(int(canary.WeightTotal))
+		}
+
+		header := ""
+		switch {
+		case canary.Header != "" && canary.HeaderValue != "":
+			header = "header " + canary.Header + "=" + canary.HeaderValue
+		case canary.Header != "" && canary.HeaderPattern != "":
+			header = "header " + canary.Header + "~" + canary.HeaderPattern
+		case canary.Header != "":
+			header = "header " + canary.Header
+		}
+
+		cookie := ""
+		if canary.Cookie != "" {
+			cookie = "cookie " + canary.Cookie
+		}
+
+		add("Canary", weight, header, cookie)
+
+		// Canary ingresses without rules only receive requests when the canary is forced
+		if weight == "" && header == "" && cookie == "" {
+			add("Canary", "no traffic")
+		}
+	}
+
+	if auth := behavior.Auth; auth != nil {
+		if auth.Type == "external" {
+			add("Auth", "external "+auth.URL, prefixed("sign in ", auth.SignIn))
+		} else {
+			add("Auth", auth.Type+" secret "+auth.Secret, prefixed("realm ", auth.Realm))
+		}
+	}
+
+	if limit := behavior.RateLimit; limit != nil {
+		add("Rate limit",
+			countToString(limit.RPS, " rps"),
+			countToString(limit.RPM, " rpm"),
+			countToString(limit.Connections, " connections"),
+			prefixed("burst x", countToString(limit.BurstMultiplier, "")),
+		)
+	}
+
+	if timeouts := behavior.Timeouts; timeouts != nil {
+		add("Timeouts", prefixed("connect ", timeouts.Connect), prefixed("read ", timeouts.Read), prefixed("send ", timeouts.Send))
+	}
+
+	if cors := behavior.CORS; cors != nil {
+		credentials := ""
+		if cors.AllowCredentials {
+			credentials = "credentials"
+		}
+
+		add("CORS", "origin "+cors.AllowOrigin, "methods "+cors.AllowMethods, credentials, "max age "+cors.MaxAge)
+	}
+
+	add("Allowed sources", behavior.AllowedSourceRanges...)
+
+	return settings
+}
+
+// BehaviorToString returns the settings of the effective behavior of a path on one line
+func BehaviorToString(behavior *route.Behavior) string {
+	settings := []string{}
+	for _, setting := range BehaviorSettings(behavior) {
+		settings = append(settings, strings.ToLower(setting.Name)+": "+setting.Value)
+	}
+
+	return strings.Join(settings, "; ")
+}
+
+// countToString returns a non-zero count followed by its unit
+func countToString(count int32, unit string) string {
+	if count == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(int(count)) + unit
+}
+
+// prefixed returns a value preceded by a prefix, or an empty string when the value is empty
+func prefixed(prefix string, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return prefix + value
+}
+
+// OutputWide is the output format of the tables that print
+// the details of each pod backing a service, one row per pod
+const OutputWide = "wide"
+
+// hasPodDetails returns whether the details of the pods of a service are set
+func hasPodDetails(service *route.Service) bool {
+	if service == nil {
+		return false
+	}
+
+	for _, pod := range service.Pods {
+		if pod.Details != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PodDetailsDefinitions returns the table column definitions of the pod details,
+// which follow the pod column
+func PodDetailsDefinitions() []metav1.TableColumnDefinition {
+	return []metav1.TableColumnDefinition{
+		{Name: "IP", Type: "string"},
+		{Name: "Node", Type: "string"},
+		{Name: "Status", Type: "string"},
+		{Name: "Ready", Type: "string"},
+		{Name: "Restarts", Type: "string"},
+		{Name: "Age", Type: "string"},
+	}
+}
+
+// PodDetailsRows returns the table cells of the pod column and of the pod details of a service,
+// one row per pod and per endpoint not backed by a pod. Services without pods nor endpoints,
+// such as ExternalName services, are printed in one row with the targets of the service
+func PodDetailsRows(service *route.Service) [][]interface{} {
+	rows := [][]interface{}{}
+
+	for _, pod := range service.Pods {
+		if pod.Details == nil {
+			rows = append(rows, []interface{}{pod.Name, "", "", PodStatusToString(pod), "", "", ""})
+			continue
+		}
+
+		rows = append(rows, []interface{}{
+			pod.Name,
+			pod.Details.IP,
+			pod.Details.Node,
+			PodStatusToString(pod),
+			strconv.Itoa(pod.Details.ReadyContainers) + "/" + strconv.Itoa(pod.Details.Containers),
+			strconv.FormatInt(int64(pod.Details.Restarts), 10),
+			AgeToString(pod.Details.Created),
+		})
+	}
+
+	for _, endpoint := range service.Endpoints {
+		if endpoint.Pod != "" {
+			continue
+		}
+
+		address := strings.Join(endpoint.Addresses, ",")
+		rows = append(rows, []interface{}{address, address, endpoint.Node, "", "", "", ""})
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []interface{}{ServiceTargetsToString(service), "", "", "", "", "", ""})
+	}
+
+	return rows
+}
+
+// PodStatusToString returns the phase of a pod, or Terminating for pods being deleted
+func PodStatusToString(pod route.Pod) string {
+	if pod.Terminating {
+		return "Terminating"
+	}
+
+	if pod.Details == nil {
+		return ""
+	}
+
+	return pod.Details.Phase
+}
+
+// AgeToString returns the time elapsed since a given time in a human readable
+// format, such as "5d", or <unknown> when the time is not known
+func AgeToString(created *time.Time) string {
+	if created == nil {
+		return "<unknown>"
+	}
+
+	return duration.HumanDuration(time.Since(*created))
+}
+
+// RouteColumns defines the path columns of a route table
+type RouteColumns struct {
+	TLS           bool
+	Warnings      bool
+	Match         bool
+	Weight        bool
+	Subset        bool
+	Rewrite       bool
+	Retries       bool
+	Filters       bool
+	Include       bool
+	Behavior      bool
+	PodDetails    bool
+	PodColumnName string
+}
+
+// NewRouteColumns returns the path columns required to print the given routes
+func NewRouteColumns(routes []*route.Route) *RouteColumns {
+	columns := &RouteColumns{PodColumnName: "Pod(s)"}
+
+	for _, r := range routes {
+		for _, host := range TableHosts(r) {
+			if host.TLS != nil {
+				columns.TLS = true
+			}
+
+			if len(host.Warnings) > 0 {
+				columns.Warnings = true
+			}
+
+			for _, path := range host.Paths {
+				if MatchToString(path) != "" {
+					columns.Match = true
+				}
+
+				if path.Weight != nil {
+					columns.Weight = true
+				}
+
+				if path.Subset != nil {
+					columns.Subset = true
+				}
+
+				if path.Rewrite != "" {
+					columns.Rewrite = true
+				}
+
+				if path.Retries != "" {
+					columns.Retries = true
+				}
+
+				if len(path.Filters) > 0 {
+					columns.Filters = true
+				}
+
+				if path.Include != "" {
+					columns.Include = true
+				}
+
+				if path.Behavior != nil {
+					columns.Behavior = true
+				}
+
+				if path.Service.Hostname != "" {
+					columns.PodColumnName = "Pod(s)/Hostname"
+				}
+
+				if hasPodDetails(path.Service) {
+					columns.PodDetails = true
+				}
+			}
+		}
+	}
+
+	return columns
+}
+
+// Definitions returns the table column definitions of the path columns
+func (c *RouteColumns) Definitions() []metav1.TableColumnDefinition {
+	definitions := []metav1.TableColumnDefinition{{Name: "Host", Type: "string"}}
+
+	if c.TLS {
+		definitions = append(definitions, metav1.TableColumnDefinition{Name: "TLS", Type: "string"})
+	}
+
+	definitions = append(definitions, metav1.TableColumnDefinition{Name: "Path", Type: "string"})
+
+	if c.Match {
+		definitions = append(definitions, metav1.TableColumnDefinition{Name: "Match", Type: "string"})
+	}
+
+	definitions = append(definitions, metav1.TableColumnDefinition{Name: "Port", Type: "string"})
+
+	if c.Weight {
+		definitions = append(definitions, metav1.TableColumnDefinition{Name: "Weight", Type: "string"})
+	}
+
+	if c.Subset {
+		definitions = append(definitions, metav1.TableColumnDefinition{Name: "Subset", Type: "string"})
+	}
+
+	if c.Rewrite {
+		definitions = append(definitions, metav1.TableColumnDefinition{Name: "Rewrite", Type: "string"})
+	}
+
+	if c.Retries {
+		definitions = append(definitions, metav1.TableColumnDefinition{Name: "Retries", Type: "string"})
+	}
+
+	if c.Filters {
+		definitions = append(definitions, metav1.TableColumnDefinition{Name: "Filters", Type: "string"})
+	}
+
+	if c.Include {
+		definitions = append(definitions, metav1.TableColumnDefinition{Name: "Include", Type: "string"})
+	}
+
+	if c.Behavior {
+		definitions = append(definitions, metav1.TableColumnDefinition{Name: "Behavior", Type: "string"})
+	}
+
+	definitions = append(definitions,
+		metav1.TableColumnDefinition{Name: "Service", Type: "string"},
+		metav1.TableColumnDefinition{Name: "Type", Type: "string"},
+		metav1.TableColumnDefinition{Name: "Service Port(s)", Type: "string"},
+		metav1.TableColumnDefinition{Name: c.PodColumnName, Type: "string"},
+	)
+
+	if c.PodDetails {
+		definitions = append(definitions, PodDetailsDefinitions()...)
+	}
+
+	if c.Warnings {
+		definitions = append(definitions, metav1.TableColumnDefinition{Name: "Warnings", Type: "string"})
+	}
+
+	return definitions
+}
+
+// Cells returns the table cells of the path columns of a host path
+func (c *RouteColumns) Cells(host route.Host, path route.Path) []interface{} {
+	cells := []interface{}{host.Host}
+
+	if c.TLS {
+		cells = append(cells, TLSToString(host.TLS))
+	}
+
+	cells = append(cells, path.Path)
+
+	if c.Match {
+		cells = append(cells, MatchToString(path))
+	}
+
+	cells = append(cells, path.BackendPort)
+
+	if c.Weight {
+		cells = append(cells, WeightToString(path.Weight))
+	}
+
+	if c.Subset {
+		cells = append(cells, SubsetToString(path.Subset))
+	}
+
+	if c.Rewrite {
+		cells = append(cells, path.Rewrite)
+	}
+
+	if c.Retries {
+		cells = append(cells, path.Retries)
+	}
+
+	if c.Filters {
+		cells = append(cells, strings.Join(path.Filters, ","))
+	}
+
+	if c.Include {
+		cells = append(cells, path.Include)
+	}
+
+	if c.Behavior {
+		cells = append(cells, BehaviorToString(path.Behavior))
+	}
+
+	// Hosts without paths are printed with empty path and service cells
+	if path.Service == nil {
+		cells = append(cells, "", "", "", "")
+	} else {
+		cells = append(cells,
+			ServiceNameToString(path.Service),
+			path.Service.Type,
+			PortsToString(path.Service.Ports),
+			ServiceTargetsToString(path.Service),
+		)
+	}
+
+	if c.Warnings {
+		cells = append(cells, strings.Join(host.Warnings, ";"))
+	}
+
+	return cells
+}
+
+// Rows returns the table rows of the path columns of a host path. When the pod details are
+// printed, the path is printed in one row per pod or endpoint of its service instead of one row
+func (c *RouteColumns) Rows(host route.Host, path route.Path) [][]interface{} {
+	cells := c.Cells(host, path)
+
+	if !c.PodDetails {
+		return [][]interface{}{cells}
+	}
+
+	// The pod column is the last one but the warnings column
+	index := len(cells) - 1
+	if c.Warnings {
+		index--
+	}
+
+	// Hosts without paths have no pods
+	podRows := [][]interface{}{{"", "", "", "", "", "", ""}}
+	if path.Service != nil {
+		podRows = PodDetailsRows(path.Service)
+	}
+
+	rows := [][]interface{}{}
+
+	for _, podRow := range podRows {
+		row := append([]interface{}{}, cells[:index]...)
+		row = append(row, podRow...)
+
+		rows = append(rows, append(row, cells[index+1:]...))
+	}
+
+	return rows
 }
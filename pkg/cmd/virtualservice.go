@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"io"
+
+	"kube-route-info/pkg/route"
+)
+
+// VirtualService defines Istio virtual service attributes
+type VirtualService struct {
+	Resolver *route.Resolver
+}
+
+// NewVirtualService returns a new VirtualService struct
+func NewVirtualService(client route.ClientInterface, namespace string) *VirtualService {
+	return &VirtualService{
+		Resolver: route.NewResolver(client, namespace),
+	}
+}
+
+// ResetCache drops the cluster scoped objects cached by the resolver, so that they are read again
+func (v *VirtualService) ResetCache() {
+	v.Resolver.ResetCache()
+}
+
+// PrintGraph prints Istio virtual service route information in a tree graph format
+func (v *VirtualService) PrintGraph(name string, w io.Writer) error {
+
+	virtualService, err := v.Resolver.ResolveVirtualService(name)
+	if err != nil {
+		return err
+	}
+
+	printRouteGraph([]*route.Route{virtualService}, w)
+
+	return nil
+}
+
+// PrintTable prints Istio virtual service route information in table format
+func (v *VirtualService) PrintTable(name string, w io.Writer) error {
+
+	virtualService, err := v.Resolver.ResolveVirtualService(name)
+	if err != nil {
+		return err
+	}
+
+	printRouteTable([]*route.Route{virtualService}, false, w)
+
+	return nil
+}
+
+// PrintDocument prints Istio virtual service route information in a machine-readable format
+func (v *VirtualService) PrintDocument(name string, output string, w io.Writer) error {
+
+	virtualService, err := v.Resolver.ResolveVirtualService(name)
+	if err != nil {
+		return err
+	}
+
+	document := route.NewDocument()
+	document.VirtualService = virtualService
+
+	return PrintDocument(document, output, w)
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"kube-route-info/pkg/route"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+// istioManifests defines the Istio objects returned by the virtual service mock client
+var istioManifests = []string{`
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: default
+spec:
+  hosts:
+  - reviews.foo.com
+  http:
+  - match:
+    - uri:
+        prefix: /api
+      headers:
+        end-user:
+          exact: jason
+    rewrite:
+      uri: /
+    retries:
+      attempts: 3
+    route:
+    - destination:
+        host: service-reviews
+        subset: v1
+      weight: 80
+    - destination:
+        host: service-reviews
+        subset: v3
+      weight: 20
+  tcp:
+  - match:
+    - port: 27017
+    route:
+    - destination:
+        host: api.example.com
+        port:
+          number: 27017
+`, `
+apiVersion: networking.istio.io/v1beta1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: default
+spec:
+  host: service-reviews
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+`, `
+apiVersion: networking.istio.io/v1beta1
+kind: ServiceEntry
+metadata:
+  name: external
+  namespace: default
+spec:
+  hosts:
+  - api.example.com
+  ports:
+  - number: 27017
+    protocol: TCP
+`}
+
+// Mock client struct
+type VirtualServiceMockClient struct {
+	CustomResourceMockClient
+}
+
+func NewVirtualServiceMockClient() *VirtualServiceMockClient {
+	return &VirtualServiceMockClient{CustomResourceMockClient: *NewCustomResourceMockClient(istioManifests...)}
+}
+
+func (c *VirtualServiceMockClient) GetPodsByLabels(podLabels map[string]string) (*v1.PodList, error) {
+	list := &v1.PodList{}
+
+	for _, version := range []string{"v1", "v2"} {
+		pod := v1.Pod{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod-reviews-" + version,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "reviews", "version": version},
+			},
+		}
+
+		if labels.SelectorFromSet(podLabels).Matches(labels.Set(pod.Labels)) {
+			list.Items = append(list.Items, pod)
+		}
+	}
+
+	return list, nil
+}
+
+func (c *VirtualServiceMockClient) GetEndpointSlicesByService(name string) (*discoveryv1.EndpointSliceList, error) {
+	service, _ := c.GetServiceByName(name)
+	pods, _ := c.GetPodsByLabels(service.Spec.Selector)
+	return newEndpointSlices(pods), nil
+}
+
+func (c *VirtualServiceMockClient) Namespaced(namespace string) route.ClientInterface { return c }
+
+func TestVirtualServicePrintGraphSuccessful(t *testing.T) {
+
+	tests := []struct {
+		virtualServiceName string
+		expectedGraph      string
+	}{
+		{
+			"reviews",
+			"[VirtualService]  reviews\n└── reviews.foo.com\n    ├── /api [header:end-user=jason]\n    │\u00a0\u00a0 ├── [Rewrite]  uri=/\n    │\u00a0\u00a0 ├── [Retries]  attempts=3\n    │\u00a0\u00a0 ├── [Service]  service-reviews (subset v1, weight 80)\n    │\u00a0\u00a0 │\u00a0\u00a0 └── [Pod]  pod-reviews-v1\n    │\u00a0\u00a0 └── [Service]  service-reviews (subset v3 *Not found*, weight 20)\n    │\u00a0\u00a0     ├── [Pod]  pod-reviews-v1\n    │\u00a0\u00a0     └── [Pod]  pod-reviews-v2\n    └── [protocol=TCP,port=27017]\n        └── [Service]  api.example.com (weight 100)\n            └── [Hostname]  api.example.com\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewVirtualServiceMockClient()
+
+		virtualService := NewVirtualService(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		virtualService.PrintGraph(test.virtualServiceName, buf)
+
+		if buf.String() != test.expectedGraph {
+			t.Errorf("Returned tree graph was incorrect,\ngot:\n%s\nwant:\n%s", buf.String(), test.expectedGraph)
+		}
+	}
+}
+
+func TestVirtualServicePrintTableSuccessful(t *testing.T) {
+
+	tests := []struct {
+		virtualServiceName string
+		expectedTable      string
+	}{
+		{
+			"reviews",
+			"NAME      HOST              PATH   MATCH                     PORT    WEIGHT   SUBSET           REWRITE   RETRIES      SERVICE           TYPE           SERVICE PORT(S)   POD(S)/HOSTNAME\nreviews   reviews.foo.com   /api   header:end-user=jason             80       v1               uri=/     attempts=3   service-reviews   ClusterIP      80 8080           pod-reviews-v1\nreviews   reviews.foo.com   /api   header:end-user=jason             20       v3 *Not found*   uri=/     attempts=3   service-reviews   ClusterIP      80 8080           pod-reviews-v1,pod-reviews-v2\nreviews   reviews.foo.com          protocol=TCP,port=27017   27017   100                                              api.example.com   ServiceEntry   27017 27017       api.example.com\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		mockClient := NewVirtualServiceMockClient()
+
+		virtualService := NewVirtualService(mockClient, "default")
+
+		buf := &bytes.Buffer{}
+
+		virtualService.PrintTable(test.virtualServiceName, buf)
+
+		if buf.String() != test.expectedTable {
+			t.Errorf("Returned table was incorrect,\ngot:\n%swant:\n%s", buf.String(), test.expectedTable)
+		}
+	}
+}
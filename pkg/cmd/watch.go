@@ -0,0 +1,384 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"kube-route-info/pkg/route"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DefaultWatchInterval is the time the changes of the watched objects are
+// collected for before the route information is resolved again
+const DefaultWatchInterval = 500 * time.Millisecond
+
+// CustomResource defines a custom resource read to resolve the route information of a
+// resource type, and whether it is read across all namespaces rather than in the namespace
+type CustomResource struct {
+	Kind          string
+	Resource      string
+	GroupVersions []string
+	AllNamespaces bool
+}
+
+// watchedCustomResources lists the custom resources watched per resource type. Other types
+// are resolved from ingresses, services, endpoints and pods, which are always watched
+var watchedCustomResources = map[string][]CustomResource{
+	"httproute": {
+		{Kind: "HTTPRoute", Resource: "httproutes", GroupVersions: route.GatewayAPIGroupVersions},
+	},
+	"gateway": {
+		{Kind: "Gateway", Resource: "gateways", GroupVersions: route.GatewayAPIGroupVersions},
+		{Kind: "HTTPRoute", Resource: "httproutes", GroupVersions: route.GatewayAPIGroupVersions, AllNamespaces: true},
+	},
+	"virtualservice": {
+		{Kind: "VirtualService", Resource: "virtualservices", GroupVersions: route.IstioNetworkingGroupVersions},
+		{Kind: "ServiceEntry", Resource: "serviceentries", GroupVersions: route.IstioNetworkingGroupVersions, AllNamespaces: true},
+		{Kind: "DestinationRule", Resource: "destinationrules", GroupVersions: route.IstioNetworkingGroupVersions, AllNamespaces: true},
+	},
+	"route": {
+		{Kind: "Route", Resource: "routes", GroupVersions: route.OpenShiftRouteGroupVersions},
+	},
+	"ingressroute": {
+		{Kind: "IngressRoute", Resource: "ingressroutes", GroupVersions: route.TraefikGroupVersions},
+	},
+	"httpproxy": {
+		{Kind: "HTTPProxy", Resource: "httpproxies", GroupVersions: route.ContourGroupVersions, AllNamespaces: true},
+	},
+}
+
+// CacheInterface defines the methods implemented by the resource types whose resolver
+// caches cluster scoped objects, which are read again before each update of a watcher
+type CacheInterface interface {
+	ResetCache()
+}
+
+// Watcher defines Watcher atributes
+type Watcher struct {
+	Client    *route.Client
+	Namespace string
+	Interval  time.Duration
+
+	// CustomResources are watched through the dynamic client along with the core objects
+	CustomResources []CustomResource
+
+	// now returns the time printed with each update
+	now func() time.Time
+
+	// mutex guards the reasons collected since the last update and whether
+	// the informers have synced, before which their events are ignored
+	mutex   sync.Mutex
+	synced  bool
+	reasons []string
+	changed chan struct{}
+}
+
+// NewWatcher returns a new Watcher struct
+func NewWatcher(client *route.Client, namespace string) *Watcher {
+	return &Watcher{
+		Client:    client,
+		Namespace: namespace,
+		Interval:  DefaultWatchInterval,
+		now:       time.Now,
+		changed:   make(chan struct{}, 1),
+	}
+}
+
+// Watch prints the route information with the given print function and prints it again whenever the
+// ingresses, services, endpoints, pods, secrets or custom resources of the namespace or the nodes and
+// ingress classes of the cluster change it, until the context is done. Each update is preceded by
+// its timestamp and the changes of the objects that triggered it
+func (w *Watcher) Watch(ctx context.Context, print func(io.Writer) error, out io.Writer) error {
+
+	factory := informers.NewSharedInformerFactoryWithOptions(w.Client.Clientset, 0, informers.WithNamespace(w.Namespace))
+	clusterFactory := informers.NewSharedInformerFactory(w.Client.Clientset, 0)
+
+	if err := w.addInformers(factory); err != nil {
+		return err
+	}
+
+	if err := w.addReadableInformers(ctx, factory, clusterFactory); err != nil {
+		return err
+	}
+
+	dynamicFactories, err := w.addCustomResourceInformers()
+	if err != nil {
+		return err
+	}
+
+	for _, informerFactory := range []informers.SharedInformerFactory{factory, clusterFactory} {
+		informerFactory.Start(ctx.Done())
+
+		for informer, synced := range informerFactory.WaitForCacheSync(ctx.Done()) {
+			if !synced {
+				return fmt.Errorf("unable to sync the %v informer", informer)
+			}
+		}
+	}
+
+	for _, dynamicFactory := range dynamicFactories {
+		dynamicFactory.Start(ctx.Done())
+
+		for resource, synced := range dynamicFactory.WaitForCacheSync(ctx.Done()) {
+			if !synced {
+				return fmt.Errorf("unable to sync the %v informer", resource)
+			}
+		}
+	}
+
+	w.mutex.Lock()
+	w.synced = true
+	w.mutex.Unlock()
+
+	previous, err := w.update(print, "", []string{"initial state"}, out)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.changed:
+		}
+
+		// Changes usually come in bursts, such as a pod and its endpoint slices
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(w.Interval):
+		}
+
+		w.mutex.Lock()
+		reasons := w.reasons
+		w.reasons = nil
+		w.mutex.Unlock()
+
+		// The changes of this burst were already handled by the previous update
+		if len(reasons) == 0 {
+			continue
+		}
+
+		previous, err = w.update(print, previous, reasons, out)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// update resolves the route information again and prints it along with the update reasons,
+// unless it is the same as the previous one. It returns the route information printed
+func (w *Watcher) update(print func(io.Writer) error, previous string, reasons []string, out io.Writer) (string, error) {
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := print(buf); err != nil {
+		return "", err
+	}
+
+	current := buf.String()
+	if current == previous {
+		return previous, nil
+	}
+
+	if previous != "" {
+		fmt.Fprintln(out)
+	}
+
+	fmt.Fprintf(out, "[%s] %s\n", w.now().UTC().Format(time.RFC3339), strings.Join(reasons, ", "))
+	fmt.Fprint(out, current)
+
+	return current, nil
+}
+
+// addInformers registers the informers of the ingresses, services, endpoint slices or
+// endpoints and pods of the namespace, in the API versions served by the cluster
+func (w *Watcher) addInformers(factory informers.SharedInformerFactory) error {
+
+	ingressGroupVersion, err := w.Client.GetIngressGroupVersion()
+	if err != nil {
+		return err
+	}
+
+	switch ingressGroupVersion {
+	case route.IngressNetworkingV1beta1:
+		factory.Networking().V1beta1().Ingresses().Informer().AddEventHandler(w.handler("Ingress"))
+	case route.IngressExtensionsV1beta1:
+		factory.Extensions().V1beta1().Ingresses().Informer().AddEventHandler(w.handler("Ingress"))
+	default:
+		factory.Networking().V1().Ingresses().Informer().AddEventHandler(w.handler("Ingress"))
+	}
+
+	endpointSliceGroupVersion, err := w.Client.GetEndpointSliceGroupVersion()
+	if err != nil {
+		return err
+	}
+
+	switch endpointSliceGroupVersion {
+	case route.EndpointSliceDiscoveryV1:
+		factory.Discovery().V1().EndpointSlices().Informer().AddEventHandler(w.handler("EndpointSlice"))
+	case route.EndpointSliceDiscoveryV1beta1:
+		factory.Discovery().V1beta1().EndpointSlices().Informer().AddEventHandler(w.handler("EndpointSlice"))
+	default:
+		factory.Core().V1().Endpoints().Informer().AddEventHandler(w.handler("Endpoints"))
+	}
+
+	factory.Core().V1().Services().Informer().AddEventHandler(w.handler("Service"))
+	factory.Core().V1().Pods().Informer().AddEventHandler(w.handler("Pod"))
+
+	return nil
+}
+
+// addReadableInformers registers the informers of the objects users are often not allowed to read: the
+// secrets of the namespace, which hold the TLS certificates, and the nodes and ingress classes of the
+// cluster. Informers of objects that can not be listed never sync, so they are only registered when the
+// user is allowed to list the objects. The pods of ingress controllers in other namespaces are not watched
+func (w *Watcher) addReadableInformers(ctx context.Context, factory informers.SharedInformerFactory, clusterFactory informers.SharedInformerFactory) error {
+
+	_, err := w.Client.Clientset.CoreV1().Secrets(w.Namespace).List(ctx, metav1.ListOptions{Limit: 1})
+	if ok, err := listable(err); err != nil {
+		return err
+	} else if ok {
+		factory.Core().V1().Secrets().Informer().AddEventHandler(w.handler("Secret"))
+	}
+
+	_, err = w.Client.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
+	if ok, err := listable(err); err != nil {
+		return err
+	} else if ok {
+		clusterFactory.Core().V1().Nodes().Informer().AddEventHandler(w.handler("Node"))
+	}
+
+	ingressClassGroupVersion, err := w.Client.GetIngressClassGroupVersion()
+	if err != nil || ingressClassGroupVersion == "" {
+		return err
+	}
+
+	_, err = w.Client.ListIngressClasses()
+	if ok, err := listable(err); err != nil || !ok {
+		return err
+	}
+
+	switch ingressClassGroupVersion {
+	case route.IngressNetworkingV1beta1:
+		clusterFactory.Networking().V1beta1().IngressClasses().Informer().AddEventHandler(w.handler("IngressClass"))
+	default:
+		clusterFactory.Networking().V1().IngressClasses().Informer().AddEventHandler(w.handler("IngressClass"))
+	}
+
+	return nil
+}
+
+// listable returns whether objects can be listed from the error returned when listing them
+func listable(err error) (bool, error) {
+	if apierrors.IsForbidden(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// addCustomResourceInformers registers the informers of the custom resources of the watcher in the
+// API versions served by the cluster, and returns their factories, one for the namespace and one
+// for all namespaces. Custom resources not served by the cluster are not watched
+func (w *Watcher) addCustomResourceInformers() ([]dynamicinformer.DynamicSharedInformerFactory, error) {
+
+	if len(w.CustomResources) == 0 {
+		return nil, nil
+	}
+
+	if w.Client.Dynamic == nil {
+		return nil, fmt.Errorf("can not watch custom resources without a dynamic client")
+	}
+
+	namespaced := dynamicinformer.NewFilteredDynamicSharedInformerFactory(w.Client.Dynamic, 0, w.Namespace, nil)
+	cluster := dynamicinformer.NewFilteredDynamicSharedInformerFactory(w.Client.Dynamic, 0, "", nil)
+
+	for _, customResource := range w.CustomResources {
+
+		groupVersion, err := w.Client.GetCustomResourceGroupVersion(customResource.Resource, customResource.GroupVersions)
+		if err != nil {
+			return nil, err
+		}
+
+		if groupVersion == "" {
+			continue
+		}
+
+		gv, err := schema.ParseGroupVersion(groupVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		factory := namespaced
+		if customResource.AllNamespaces {
+			factory = cluster
+		}
+
+		factory.ForResource(gv.WithResource(customResource.Resource)).Informer().AddEventHandler(w.handler(customResource.Kind))
+	}
+
+	return []dynamicinformer.DynamicSharedInformerFactory{namespaced, cluster}, nil
+}
+
+// handler returns the event handler that records the changes of the objects of a kind
+func (w *Watcher) handler(kind string) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(object interface{}) {
+			w.record(kind, object, "added")
+		},
+		UpdateFunc: func(old interface{}, object interface{}) {
+			w.record(kind, object, "updated")
+		},
+		DeleteFunc: func(object interface{}) {
+			if tombstone, ok := object.(cache.DeletedFinalStateUnknown); ok {
+				object = tombstone.Obj
+			}
+
+			w.record(kind, object, "deleted")
+		},
+	}
+}
+
+// record adds the change of an object to the reasons of the next update, once the informers have synced
+func (w *Watcher) record(kind string, object interface{}, change string) {
+
+	meta, ok := object.(metav1.Object)
+	if !ok {
+		return
+	}
+
+	// Cluster scoped objects, such as nodes, have no namespace
+	name := meta.GetName()
+	if meta.GetNamespace() != "" {
+		name = meta.GetNamespace() + "/" + name
+	}
+
+	reason := kind + " " + name + " " + change
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if !w.synced {
+		return
+	}
+
+	if !containsString(w.reasons, reason) {
+		w.reasons = append(w.reasons, reason)
+	}
+
+	select {
+	case w.changed <- struct{}{}:
+	default:
+	}
+}
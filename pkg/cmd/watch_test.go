@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"kube-route-info/pkg/route"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// syncBuffer is a buffer that can be written by the watcher while the test reads it
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buffer.String()
+}
+
+// waitForOutput waits until the output contains the given text
+func waitForOutput(t *testing.T, out *syncBuffer, text string) {
+	for start := time.Now(); !strings.Contains(out.String(), text); time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("Output does not contain %q, got: %q", text, out.String())
+		}
+	}
+}
+
+// newWatchEndpointSlice returns the endpoint slice of the service foo with an endpoint per pod
+func newWatchEndpointSlice(pods ...string) *discoveryv1.EndpointSlice {
+	ready := true
+
+	endpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-abc",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "foo"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+
+	for _, pod := range pods {
+		endpointSlice.Endpoints = append(endpointSlice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{"10.0.0.1"},
+			Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+			TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: pod},
+		})
+	}
+
+	return endpointSlice
+}
+
+func TestWatcherWatch(t *testing.T) {
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec: v1.ServiceSpec{
+			Type:     v1.ServiceTypeClusterIP,
+			Selector: map[string]string{"app": "foo"},
+			Ports:    []v1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080), Protocol: v1.ProtocolTCP}},
+		},
+	}
+
+	clientset := fake.NewSimpleClientset(service, newWatchEndpointSlice("foo-1", "foo-2"))
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{GroupVersion: route.IngressNetworkingV1, APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress", Namespaced: true}}},
+		{GroupVersion: route.EndpointSliceDiscoveryV1, APIResources: []metav1.APIResource{{Name: "endpointslices", Kind: "EndpointSlice", Namespaced: true}}},
+	}
+
+	client := route.NewClient(clientset, "default")
+
+	watcher := NewWatcher(client, "default")
+	watcher.Interval = 10 * time.Millisecond
+	watcher.now = func() time.Time { return time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC) }
+
+	print := func(w io.Writer) error {
+		return NewService(client, "default").PrintTable("foo", w)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error)
+
+	go func() {
+		done <- watcher.Watch(ctx, print, out)
+	}()
+
+	waitForOutput(t, out, "initial state")
+
+	_, err := clientset.DiscoveryV1().EndpointSlices("default").Update(context.TODO(), newWatchEndpointSlice("foo-1"), metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	waitForOutput(t, out, "EndpointSlice default/foo-abc updated")
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "[2021-05-01T10:00:00Z] initial state\nNAME   TYPE        PORT(S)   POD(S)\nfoo    ClusterIP   80 8080   foo-1,foo-2\n\n[2021-05-01T10:00:00Z] EndpointSlice default/foo-abc updated\nNAME   TYPE        PORT(S)   POD(S)\nfoo    ClusterIP   80 8080   foo-1\n"
+
+	if out.String() != expected {
+		t.Errorf("Returned output was incorrect, got: %q, want: %q", out.String(), expected)
+	}
+}
+
+func TestWatcherWatchCustomResources(t *testing.T) {
+
+	openShiftRoute := func(host string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": route.OpenShiftRouteV1,
+			"kind":       "Route",
+			"metadata":   map[string]interface{}{"name": "foo", "namespace": "default"},
+			"spec":       map[string]interface{}{"host": host},
+		}}
+	}
+
+	gvr := schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+	clientset := fake.NewSimpleClientset()
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{GroupVersion: route.IngressNetworkingV1, APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress", Namespaced: true}}},
+		{GroupVersion: route.OpenShiftRouteV1, APIResources: []metav1.APIResource{{Name: "routes", Kind: "Route", Namespaced: true}}},
+	}
+
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "RouteList"}, openShiftRoute("foo.com"))
+
+	client := route.NewClient(clientset, "default")
+	client.Dynamic = dynamicClient
+
+	watcher := NewWatcher(client, "default")
+	watcher.CustomResources = watchedCustomResources["route"]
+	watcher.Interval = 10 * time.Millisecond
+	watcher.now = func() time.Time { return time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC) }
+
+	print := func(w io.Writer) error {
+		object, err := client.GetCustomResourceByName("routes", route.OpenShiftRouteGroupVersions, "foo")
+		if err != nil {
+			return err
+		}
+
+		host, _, _ := unstructured.NestedString(object.Object, "spec", "host")
+		fmt.Fprintln(w, host)
+
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error)
+
+	go func() {
+		done <- watcher.Watch(ctx, print, out)
+	}()
+
+	waitForOutput(t, out, "initial state")
+
+	_, err := dynamicClient.Resource(gvr).Namespace("default").Update(context.TODO(), openShiftRoute("bar.com"), metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	waitForOutput(t, out, "Route default/foo updated")
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "[2021-05-01T10:00:00Z] initial state\nfoo.com\n\n[2021-05-01T10:00:00Z] Route default/foo updated\nbar.com\n"
+
+	if out.String() != expected {
+		t.Errorf("Returned output was incorrect, got: %q, want: %q", out.String(), expected)
+	}
+}
+
+func TestWatcherWatchNodes(t *testing.T) {
+
+	newNode := func(address string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: address}}},
+		}
+	}
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec: v1.ServiceSpec{
+			Type:  v1.ServiceTypeNodePort,
+			Ports: []v1.ServicePort{{Port: 80, NodePort: 30080, Protocol: v1.ProtocolTCP}},
+		},
+	}
+
+	clientset := fake.NewSimpleClientset(service, newNode("10.0.0.1"))
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{GroupVersion: route.IngressNetworkingV1, APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress", Namespaced: true}}},
+	}
+
+	// Informers of objects the user is not allowed to list would never sync
+	clientset.PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", errors.New("forbidden"))
+	})
+
+	client := route.NewClient(clientset, "default")
+
+	watcher := NewWatcher(client, "default")
+	watcher.Interval = 10 * time.Millisecond
+	watcher.now = func() time.Time { return time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC) }
+
+	resource := NewService(client, "default")
+
+	print := func(w io.Writer) error {
+		resource.ResetCache()
+
+		service, err := resource.Resolver.ResolveService("foo")
+		if err != nil {
+			return err
+		}
+
+		for _, entrypoint := range service.Exposure.Entrypoints {
+			fmt.Fprintf(w, "%s:%d\n", entrypoint.Address, entrypoint.Port)
+		}
+
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error)
+
+	go func() {
+		done <- watcher.Watch(ctx, print, out)
+	}()
+
+	waitForOutput(t, out, "initial state")
+
+	_, err := clientset.CoreV1().Nodes().UpdateStatus(context.TODO(), newNode("10.0.0.2"), metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	waitForOutput(t, out, "Node node-1 updated")
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "[2021-05-01T10:00:00Z] initial state\n10.0.0.1:30080\n\n[2021-05-01T10:00:00Z] Node node-1 updated\n10.0.0.2:30080\n"
+
+	if out.String() != expected {
+		t.Errorf("Returned output was incorrect, got: %q, want: %q", out.String(), expected)
+	}
+}
@@ -0,0 +1,341 @@
+package route
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Severity defines how serious a finding is
+type Severity string
+
+// Finding severities
+const (
+	SeverityError   Severity = "Error"
+	SeverityWarning Severity = "Warning"
+)
+
+// FindingType defines the kind of broken link found in a route chain
+type FindingType string
+
+// Finding types
+const (
+	FindingServiceNotFound     FindingType = "ServiceNotFound"
+	FindingServicePortNotFound FindingType = "ServicePortNotFound"
+	FindingExternalNameService FindingType = "ExternalNameService"
+	FindingNoPodsSelected      FindingType = "NoPodsSelected"
+	FindingNoReadyPods         FindingType = "NoReadyPods"
+	FindingPodNotReady         FindingType = "PodNotReady"
+	FindingTargetPortNotFound  FindingType = "TargetPortNotFound"
+	FindingTargetPortMismatch  FindingType = "TargetPortMismatch"
+)
+
+// Finding defines a broken or suspicious link found in a route chain
+type Finding struct {
+	Severity Severity    `json:"severity"`
+	Type     FindingType `json:"type"`
+	Object   string      `json:"object"`
+	Message  string      `json:"message"`
+}
+
+// externalNameSupportedControllers lists the ingress controllers, by the controller name
+// of their IngressClass, that can route traffic to ExternalName services
+var externalNameSupportedControllers = map[string]bool{
+	"k8s.io/ingress-nginx":                   true,
+	"haproxy.org/ingress-controller/haproxy": true,
+	"ingress-controllers.konghq.com/kong":    true,
+}
+
+// externalNameUnsupportedControllers lists the ingress controllers, by the controller name
+// of their IngressClass, that can not route traffic to ExternalName services
+var externalNameUnsupportedControllers = map[string]bool{
+	"k8s.io/ingress-gce":  true,
+	"ingress.k8s.aws/alb": true,
+}
+
+// CountErrors returns the number of findings with error severity
+func CountErrors(findings []Finding) (count int) {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			count++
+		}
+	}
+
+	return
+}
+
+// CheckIngress returns the findings of the route chain of the ingress that matches a given name
+func (r *Resolver) CheckIngress(name string) ([]Finding, error) {
+
+	ingress, err := r.Client.GetIngressByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if ingress == nil {
+		return nil, fmt.Errorf("ingress %q not found", name)
+	}
+
+	object := "Ingress/" + ingress.Name
+	ingressClass, classFindings, err := r.resolveIngressClass(ingress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ingresses without class are served by the controller of the default class. Class names
+	// are chosen freely, so the controller is only known from the IngressClass object
+	controller := ""
+	if ingressClass != nil {
+		controller = ingressClass.Controller
+	}
+
+	checked := map[string]bool{}
+	findings := []Finding{}
+
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service == nil {
+				continue
+			}
+
+			backendFindings, err := r.checkBackend(object, controller, path.Backend.Service, checked)
+			if err != nil {
+				return nil, err
+			}
+
+			findings = append(findings, backendFindings...)
+		}
+	}
+
+	if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+
+		backendFindings, err := r.checkBackend(object, controller, backend.Service, checked)
+		if err != nil {
+			return nil, err
+		}
+
+		findings = append(findings, backendFindings...)
+	}
+
+	for _, finding := range classFindings {
+		finding.Object = object
+		findings = append(findings, finding)
+	}
+
+	secrets := map[string]*Secret{}
+	hosts := map[string]bool{}
+
+	for _, rule := range ingress.Spec.Rules {
+		if hosts[rule.Host] {
+			continue
+		}
+
+		hosts[rule.Host] = true
+
+		_, tlsFindings, err := r.resolveIngressTLS(ingress, rule.Host, secrets)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, finding := range tlsFindings {
+			finding.Object = object
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings, nil
+}
+
+// CheckService returns the findings of the route chain of the service that matches a given name
+func (r *Resolver) CheckService(name string) ([]Finding, error) {
+
+	service, err := r.Client.GetServiceByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if service == nil {
+		return nil, fmt.Errorf("service %q not found", name)
+	}
+
+	return r.checkService(service)
+}
+
+// checkBackend returns the findings of an ingress backend service. Services already
+// checked through other backends only get their port reference checked
+func (r *Resolver) checkBackend(object string, controller string, backend *networkingv1.IngressServiceBackend, checked map[string]bool) ([]Finding, error) {
+
+	service, err := r.Client.GetServiceByName(backend.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if err != nil || service == nil {
+		return []Finding{{
+			Severity: SeverityError,
+			Type:     FindingServiceNotFound,
+			Object:   object,
+			Message:  fmt.Sprintf("backend service %q not found", backend.Name),
+		}}, nil
+	}
+
+	findings := []Finding{}
+
+	if service.Spec.Type == v1.ServiceTypeExternalName {
+		if externalNameSupportedControllers[controller] {
+			return findings, nil
+		}
+
+		severity := SeverityWarning
+		message := fmt.Sprintf("backend service %q is of type ExternalName, which is not supported by every ingress controller", service.Name)
+
+		if externalNameUnsupportedControllers[controller] {
+			severity = SeverityError
+			message = fmt.Sprintf("backend service %q is of type ExternalName, which is not supported by the %q ingress controller", service.Name, controller)
+		}
+
+		return append(findings, Finding{
+			Severity: severity,
+			Type:     FindingExternalNameService,
+			Object:   object,
+			Message:  message,
+		}), nil
+	}
+
+	if !hasServicePort(service, backend.Port) {
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Type:     FindingServicePortNotFound,
+			Object:   object,
+			Message:  fmt.Sprintf("port %q is not defined on backend service %q", IngressBackendPortToString(networkingv1.IngressBackend{Service: backend}), service.Name),
+		})
+	}
+
+	if checked[service.Name] {
+		return findings, nil
+	}
+	checked[service.Name] = true
+
+	serviceFindings, err := r.checkService(service)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(findings, serviceFindings...), nil
+}
+
+// checkService returns the findings of the pods selected by a service
+func (r *Resolver) checkService(service *v1.Service) ([]Finding, error) {
+
+	findings := []Finding{}
+
+	// ExternalName services and services without selector do not select pods
+	if service.Spec.Type == v1.ServiceTypeExternalName || len(service.Spec.Selector) == 0 {
+		return findings, nil
+	}
+
+	object := "Service/" + service.Name
+
+	pods, err := r.Client.GetPodsByLabels(service.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pods.Items) == 0 {
+		return append(findings, Finding{
+			Severity: SeverityError,
+			Type:     FindingNoPodsSelected,
+			Object:   object,
+			Message:  "selector does not match any pod",
+		}), nil
+	}
+
+	readyPods := 0
+
+	for _, pod := range pods.Items {
+		if IsPodReady(&pod) {
+			readyPods++
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			Type:     FindingPodNotReady,
+			Object:   "Pod/" + pod.Name,
+			Message:  fmt.Sprintf("pod selected by service %q is not ready", service.Name),
+		})
+	}
+
+	if readyPods == 0 {
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Type:     FindingNoReadyPods,
+			Object:   object,
+			Message:  "none of the selected pods is ready",
+		})
+	}
+
+	selected := []*v1.Pod{}
+	for index := range pods.Items {
+		selected = append(selected, &pods.Items[index])
+	}
+
+	for _, port := range service.Spec.Ports {
+		if port.TargetPort.StrVal == "" {
+			continue
+		}
+
+		for _, targetPort := range ResolveTargetPorts(selected, port.TargetPort.StrVal) {
+			switch {
+			case !targetPort.Found:
+				findings = append(findings, Finding{
+					Severity: SeverityError,
+					Type:     FindingTargetPortNotFound,
+					Object:   "Pod/" + targetPort.Pod,
+					Message:  fmt.Sprintf("target port %q of service %q is not declared on any container", port.TargetPort.StrVal, service.Name),
+				})
+
+			case targetPort.Mismatch:
+				findings = append(findings, Finding{
+					Severity: SeverityWarning,
+					Type:     FindingTargetPortMismatch,
+					Object:   "Pod/" + targetPort.Pod,
+					Message:  fmt.Sprintf("target port %q of service %q resolves to port %d, which differs from the other pods", port.TargetPort.StrVal, service.Name, targetPort.Port),
+				})
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// IsPodReady returns whether the pod Ready condition is true
+func IsPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func hasServicePort(service *v1.Service, port networkingv1.ServiceBackendPort) bool {
+	for _, servicePort := range service.Spec.Ports {
+		if port.Name != "" && servicePort.Name == port.Name {
+			return true
+		}
+
+		if port.Name == "" && servicePort.Port == port.Number {
+			return true
+		}
+	}
+
+	return false
+}
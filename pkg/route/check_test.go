@@ -0,0 +1,266 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// checkResources lists the ingresses and ingress classes served by the clusters of the check tests
+var checkResources = []*metav1.APIResourceList{
+	{
+		GroupVersion: IngressNetworkingV1,
+		APIResources: []metav1.APIResource{
+			{Name: "ingresses", Kind: "Ingress", Namespaced: true},
+			{Name: "ingressclasses", Kind: "IngressClass"},
+		},
+	},
+}
+
+func NewFakeCheckResolver() *Resolver {
+	class := "gce"
+
+	newPod := func(name string, app string, ready v1.ConditionStatus, portName string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": app},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "app", Ports: []v1.ContainerPort{{Name: portName, ContainerPort: 8080}}},
+				},
+			},
+			Status: v1.PodStatus{
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: ready}},
+			},
+		}
+	}
+
+	newService := func(name string, app string, targetPort string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1.ServiceSpec{
+				Type: v1.ServiceTypeClusterIP,
+				Ports: []v1.ServicePort{
+					{Name: "http", Port: 80, TargetPort: intstr.FromString(targetPort)},
+				},
+				Selector: map[string]string{"app": app},
+			},
+		}
+	}
+
+	newPath := func(serviceName string, port networkingv1.ServiceBackendPort) networkingv1.HTTPIngressPath {
+		return networkingv1.HTTPIngressPath{
+			Path: "/" + serviceName,
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: serviceName, Port: port},
+			},
+		}
+	}
+
+	client := NewFakeClient(
+		checkResources,
+		newPod("pod-healthy", "healthy", v1.ConditionTrue, "http"),
+		newPod("pod-ready", "partial", v1.ConditionTrue, "http"),
+		newPod("pod-not-ready", "partial", v1.ConditionFalse, "http"),
+		newPod("pod-down", "down", v1.ConditionFalse, "web"),
+		newIngressClass("gce", "k8s.io/ingress-gce", false),
+		newService("service-healthy", "healthy", "http"),
+		newService("service-partial", "partial", "http"),
+		newService("service-down", "down", "http"),
+		newService("service-empty", "empty", "http"),
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-external", Namespace: "default"},
+			Spec: v1.ServiceSpec{
+				Type:         v1.ServiceTypeExternalName,
+				ExternalName: "my.external.app.com",
+			},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: &class,
+				Rules: []networkingv1.IngressRule{
+					{
+						Host: "foo.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									newPath("service-healthy", networkingv1.ServiceBackendPort{Name: "http"}),
+									newPath("service-healthy", networkingv1.ServiceBackendPort{Number: 8080}),
+									newPath("service-missing", networkingv1.ServiceBackendPort{Number: 80}),
+									newPath("service-partial", networkingv1.ServiceBackendPort{Number: 80}),
+									newPath("service-down", networkingv1.ServiceBackendPort{Number: 80}),
+									newPath("service-empty", networkingv1.ServiceBackendPort{Number: 80}),
+									newPath("service-external", networkingv1.ServiceBackendPort{Number: 80}),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	return NewResolver(client, "default")
+}
+
+func TestResolverCheckIngress(t *testing.T) {
+
+	expected := []Finding{
+		{SeverityError, FindingServicePortNotFound, "Ingress/ingress", `port "8080" is not defined on backend service "service-healthy"`},
+		{SeverityError, FindingServiceNotFound, "Ingress/ingress", `backend service "service-missing" not found`},
+		{SeverityWarning, FindingPodNotReady, "Pod/pod-not-ready", `pod selected by service "service-partial" is not ready`},
+		{SeverityWarning, FindingPodNotReady, "Pod/pod-down", `pod selected by service "service-down" is not ready`},
+		{SeverityError, FindingNoReadyPods, "Service/service-down", "none of the selected pods is ready"},
+		{SeverityError, FindingTargetPortNotFound, "Pod/pod-down", `target port "http" of service "service-down" is not declared on any container`},
+		{SeverityError, FindingNoPodsSelected, "Service/service-empty", "selector does not match any pod"},
+		{SeverityError, FindingExternalNameService, "Ingress/ingress", `backend service "service-external" is of type ExternalName, which is not supported by the "k8s.io/ingress-gce" ingress controller`},
+	}
+
+	findings, err := NewFakeCheckResolver().CheckIngress("ingress")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Returned findings were incorrect,\ngot:\n%+v\nwant:\n%+v", findings, expected)
+	}
+
+	if CountErrors(findings) != 6 {
+		t.Errorf("Returned error count was incorrect, got: %d, want: %d", CountErrors(findings), 6)
+	}
+}
+
+func TestResolverCheckIngressDefaultClass(t *testing.T) {
+
+	client := NewFakeMemoryClient(t, `
+apiVersion: networking.k8s.io/v1
+kind: IngressClass
+metadata:
+  name: gce
+  annotations:
+    ingressclass.kubernetes.io/is-default-class: "true"
+spec:
+  controller: k8s.io/ingress-gce
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: service-external
+spec:
+  type: ExternalName
+  externalName: my.external.app.com
+`, `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ingress
+spec:
+  defaultBackend:
+    service:
+      name: service-external
+      port:
+        number: 80
+`)
+
+	expected := []Finding{
+		{SeverityError, FindingExternalNameService, "Ingress/ingress", `backend service "service-external" is of type ExternalName, which is not supported by the "k8s.io/ingress-gce" ingress controller`},
+	}
+
+	findings, err := NewResolver(client, "default").CheckIngress("ingress")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Returned findings were incorrect,\ngot:\n%+v\nwant:\n%+v", findings, expected)
+	}
+}
+
+func TestResolverCheckIngressExternalNameController(t *testing.T) {
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "service-external", Namespace: "default"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeExternalName, ExternalName: "my.external.app.com"},
+	}
+
+	newIngress := func(class string) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: &class,
+				DefaultBackend: &networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{Name: "service-external", Port: networkingv1.ServiceBackendPort{Number: 80}},
+				},
+			},
+		}
+	}
+
+	// Class names are chosen freely, so only the controller of the class tells whether ExternalName services are supported
+	tests := []struct {
+		name     string
+		class    *networkingv1.IngressClass
+		expected []Finding
+	}{
+		{
+			"supported controller",
+			newIngressClass("public", "k8s.io/ingress-nginx", false),
+			[]Finding{},
+		},
+		{
+			"unsupported controller",
+			newIngressClass("nginx", "ingress.k8s.aws/alb", false),
+			[]Finding{
+				{SeverityError, FindingExternalNameService, "Ingress/ingress", `backend service "service-external" is of type ExternalName, which is not supported by the "ingress.k8s.aws/alb" ingress controller`},
+			},
+		},
+		{
+			"unknown controller",
+			newIngressClass("nginx", "example.com/custom", false),
+			[]Finding{
+				{SeverityWarning, FindingExternalNameService, "Ingress/ingress", `backend service "service-external" is of type ExternalName, which is not supported by every ingress controller`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+
+		client := NewFakeClient(checkResources, service, test.class, newIngress(test.class.Name))
+
+		findings, err := NewResolver(client, "default").CheckIngress("ingress")
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		// Controllers without running pods are reported as well, which is not checked here
+		externalNameFindings := []Finding{}
+		for _, finding := range findings {
+			if finding.Type == FindingExternalNameService {
+				externalNameFindings = append(externalNameFindings, finding)
+			}
+		}
+
+		if !reflect.DeepEqual(externalNameFindings, test.expected) {
+			t.Errorf("Returned findings for %s were incorrect,\ngot:\n%+v\nwant:\n%+v", test.name, externalNameFindings, test.expected)
+		}
+	}
+}
+
+func TestResolverCheckServiceHealthy(t *testing.T) {
+
+	findings, err := NewFakeCheckResolver().CheckService("service-healthy")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(findings) != 0 {
+		t.Errorf("Expected no findings, got: %+v", findings)
+	}
+}
@@ -0,0 +1,470 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Ingress API versions supported by the client, ordered by preference
+const (
+	IngressNetworkingV1      = "networking.k8s.io/v1"
+	IngressNetworkingV1beta1 = "networking.k8s.io/v1beta1"
+	IngressExtensionsV1beta1 = "extensions/v1beta1"
+)
+
+var ingressGroupVersions = []string{
+	IngressNetworkingV1,
+	IngressNetworkingV1beta1,
+	IngressExtensionsV1beta1,
+}
+
+// ingressClassGroupVersions lists the IngressClass API versions supported by the client, ordered by preference
+var ingressClassGroupVersions = []string{
+	IngressNetworkingV1,
+	IngressNetworkingV1beta1,
+}
+
+// EndpointSlice API versions supported by the client, ordered by preference.
+// Core v1 endpoints are read when none of them is served
+const (
+	EndpointSliceDiscoveryV1      = "discovery.k8s.io/v1"
+	EndpointSliceDiscoveryV1beta1 = "discovery.k8s.io/v1beta1"
+)
+
+var endpointSliceGroupVersions = []string{
+	EndpointSliceDiscoveryV1,
+	EndpointSliceDiscoveryV1beta1,
+}
+
+// Gateway API versions supported by the client, ordered by preference
+const (
+	GatewayAPIV1       = "gateway.networking.k8s.io/v1"
+	GatewayAPIV1beta1  = "gateway.networking.k8s.io/v1beta1"
+	GatewayAPIV1alpha2 = "gateway.networking.k8s.io/v1alpha2"
+)
+
+// GatewayAPIGroupVersions lists the Gateway API versions supported by the client
+var GatewayAPIGroupVersions = []string{
+	GatewayAPIV1,
+	GatewayAPIV1beta1,
+	GatewayAPIV1alpha2,
+}
+
+// Istio networking API versions supported by the client, ordered by preference
+const (
+	IstioNetworkingV1       = "networking.istio.io/v1"
+	IstioNetworkingV1beta1  = "networking.istio.io/v1beta1"
+	IstioNetworkingV1alpha3 = "networking.istio.io/v1alpha3"
+)
+
+// IstioNetworkingGroupVersions lists the Istio networking API versions supported by the client
+var IstioNetworkingGroupVersions = []string{
+	IstioNetworkingV1,
+	IstioNetworkingV1beta1,
+	IstioNetworkingV1alpha3,
+}
+
+// OpenShiftRouteV1 is the OpenShift route API version supported by the client
+const OpenShiftRouteV1 = "route.openshift.io/v1"
+
+// OpenShiftRouteGroupVersions lists the OpenShift route API versions supported by the client
+var OpenShiftRouteGroupVersions = []string{
+	OpenShiftRouteV1,
+}
+
+// Traefik API versions supported by the client, ordered by preference
+const (
+	TraefikV1alpha1           = "traefik.io/v1alpha1"
+	TraefikContainousV1alpha1 = "traefik.containo.us/v1alpha1"
+)
+
+// TraefikGroupVersions lists the Traefik API versions supported by the client
+var TraefikGroupVersions = []string{
+	TraefikV1alpha1,
+	TraefikContainousV1alpha1,
+}
+
+// ContourV1 is the Contour API version supported by the client
+const ContourV1 = "projectcontour.io/v1"
+
+// ContourGroupVersions lists the Contour API versions supported by the client
+var ContourGroupVersions = []string{
+	ContourV1,
+}
+
+// Client defines Client atributes
+type Client struct {
+	Clientset kubernetes.Interface
+	Namespace string
+
+	// Dynamic reads custom resources, such as Gateway API objects, without
+	// typed clients. Custom resources can not be read when it is nil
+	Dynamic dynamic.Interface
+
+	// servedGroupVersions caches the API version served by the cluster per resource
+	servedGroupVersions map[string]string
+}
+
+// NewClient returns a new Client struct
+func NewClient(clientset kubernetes.Interface, namespace string) *Client {
+	return &Client{
+		Clientset:           clientset,
+		Namespace:           namespace,
+		servedGroupVersions: map[string]string{},
+	}
+}
+
+// ClientInterface defines the Client functions
+type ClientInterface interface {
+	GetPodsByLabels(map[string]string) (*v1.PodList, error)
+	GetServiceByName(string) (*v1.Service, error)
+	GetIngressByName(name string) (*networkingv1.Ingress, error)
+	GetPodByName(string) (*v1.Pod, error)
+	ListServices(string) (*v1.ServiceList, error)
+	ListIngresses(string) (*networkingv1.IngressList, error)
+	GetEndpointSlicesByService(string) (*discoveryv1.EndpointSliceList, error)
+	GetNamespaceByName(string) (*v1.Namespace, error)
+	GetSecretByName(string) (*v1.Secret, error)
+	ListIngressClasses() (*networkingv1.IngressClassList, error)
+	ListNodes() (*v1.NodeList, error)
+	GetCustomResourceByName(resource string, groupVersions []string, name string) (*unstructured.Unstructured, error)
+	ListCustomResources(resource string, groupVersions []string, selector string) (*unstructured.UnstructuredList, error)
+	Namespaced(string) ClientInterface
+}
+
+// Namespaced returns a copy of the client scoped to the given namespace.
+// The copy shares the clientset and the discovery cache of the client
+func (c *Client) Namespaced(namespace string) ClientInterface {
+	return &Client{
+		Clientset:           c.Clientset,
+		Namespace:           namespace,
+		Dynamic:             c.Dynamic,
+		servedGroupVersions: c.servedGroupVersions,
+	}
+}
+
+// GetPodsByLabels returns a list of pods that match the given labels
+func (c *Client) GetPodsByLabels(labels map[string]string) (pods *v1.PodList, err error) {
+	pods, err = c.Clientset.CoreV1().Pods(c.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: apilabels.Set(labels).String()})
+	return
+}
+
+// GetServiceByName returns a service that matches a given name
+func (c *Client) GetServiceByName(name string) (service *v1.Service, err error) {
+	service, err = c.Clientset.CoreV1().Services(c.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	return
+}
+
+// GetPodByName returns a pod that matches a given name
+func (c *Client) GetPodByName(name string) (pod *v1.Pod, err error) {
+	pod, err = c.Clientset.CoreV1().Pods(c.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	return
+}
+
+// GetNamespaceByName returns a namespace that matches a given name
+func (c *Client) GetNamespaceByName(name string) (namespace *v1.Namespace, err error) {
+	namespace, err = c.Clientset.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+	return
+}
+
+// ListNodes returns the nodes of the cluster
+func (c *Client) ListNodes() (nodes *v1.NodeList, err error) {
+	nodes, err = c.Clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	return
+}
+
+// GetSecretByName returns a secret that matches a given name
+func (c *Client) GetSecretByName(name string) (secret *v1.Secret, err error) {
+	secret, err = c.Clientset.CoreV1().Secrets(c.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	return
+}
+
+// ListServices returns the services in the namespace that match a label selector.
+// All namespaces are listed when the client namespace is empty
+func (c *Client) ListServices(selector string) (services *v1.ServiceList, err error) {
+	services, err = c.Clientset.CoreV1().Services(c.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	return
+}
+
+// ListIngresses returns the ingresses in the namespace that match a label selector.
+// All namespaces are listed when the client namespace is empty.
+// The ingresses are read from the newest API version served by the cluster
+// and converted to networking.k8s.io/v1
+func (c *Client) ListIngresses(selector string) (*networkingv1.IngressList, error) {
+	groupVersion, err := c.GetIngressGroupVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	switch groupVersion {
+	case IngressNetworkingV1beta1:
+		list, err := c.Clientset.NetworkingV1beta1().Ingresses(c.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+
+		ingresses := &networkingv1.IngressList{}
+		for index := range list.Items {
+			ingresses.Items = append(ingresses.Items, *IngressFromNetworkingV1beta1(&list.Items[index]))
+		}
+		return ingresses, nil
+
+	case IngressExtensionsV1beta1:
+		list, err := c.Clientset.ExtensionsV1beta1().Ingresses(c.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+
+		ingresses := &networkingv1.IngressList{}
+		for index := range list.Items {
+			ingress, err := IngressFromExtensionsV1beta1(&list.Items[index])
+			if err != nil {
+				return nil, err
+			}
+			ingresses.Items = append(ingresses.Items, *ingress)
+		}
+		return ingresses, nil
+	}
+
+	return c.Clientset.NetworkingV1().Ingresses(c.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+}
+
+// ListIngressClasses returns the ingress classes of the cluster. The ingress classes are read
+// from the newest API version served by the cluster and converted to networking.k8s.io/v1.
+// The list is empty on clusters without ingress classes
+func (c *Client) ListIngressClasses() (*networkingv1.IngressClassList, error) {
+	groupVersion, err := c.getServedGroupVersion("ingressclasses", ingressClassGroupVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	switch groupVersion {
+	case IngressNetworkingV1:
+		return c.Clientset.NetworkingV1().IngressClasses().List(context.TODO(), metav1.ListOptions{})
+
+	case IngressNetworkingV1beta1:
+		list, err := c.Clientset.NetworkingV1beta1().IngressClasses().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		ingressClasses := &networkingv1.IngressClassList{}
+		for index := range list.Items {
+			ingressClass, err := IngressClassFromNetworkingV1beta1(&list.Items[index])
+			if err != nil {
+				return nil, err
+			}
+			ingressClasses.Items = append(ingressClasses.Items, *ingressClass)
+		}
+		return ingressClasses, nil
+	}
+
+	return &networkingv1.IngressClassList{}, nil
+}
+
+// GetEndpointSlicesByService returns the endpoint slices of the service that matches a given name.
+// The endpoint slices are read from the newest API version served by the cluster and converted
+// to discovery.k8s.io/v1. On clusters without endpoint slices, the service endpoints are read instead
+// and converted to a single endpoint slice
+func (c *Client) GetEndpointSlicesByService(name string) (*discoveryv1.EndpointSliceList, error) {
+	groupVersion, err := c.GetEndpointSliceGroupVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	options := metav1.ListOptions{LabelSelector: apilabels.Set{discoveryv1.LabelServiceName: name}.String()}
+
+	switch groupVersion {
+	case EndpointSliceDiscoveryV1:
+		return c.Clientset.DiscoveryV1().EndpointSlices(c.Namespace).List(context.TODO(), options)
+
+	case EndpointSliceDiscoveryV1beta1:
+		list, err := c.Clientset.DiscoveryV1beta1().EndpointSlices(c.Namespace).List(context.TODO(), options)
+		if err != nil {
+			return nil, err
+		}
+
+		endpointSlices := &discoveryv1.EndpointSliceList{}
+		for index := range list.Items {
+			endpointSlices.Items = append(endpointSlices.Items, *EndpointSliceFromDiscoveryV1beta1(&list.Items[index]))
+		}
+		return endpointSlices, nil
+	}
+
+	endpoints, err := c.Clientset.CoreV1().Endpoints(c.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return &discoveryv1.EndpointSliceList{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return EndpointSlicesFromEndpoints(endpoints), nil
+}
+
+// GetIngressByName returns an ingress that matches a given name.
+// The ingress is read from the newest API version served by the cluster
+// and converted to networking.k8s.io/v1
+func (c *Client) GetIngressByName(name string) (*networkingv1.Ingress, error) {
+	groupVersion, err := c.GetIngressGroupVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	switch groupVersion {
+	case IngressNetworkingV1beta1:
+		ingress, err := c.Clientset.NetworkingV1beta1().Ingresses(c.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return IngressFromNetworkingV1beta1(ingress), nil
+
+	case IngressExtensionsV1beta1:
+		ingress, err := c.Clientset.ExtensionsV1beta1().Ingresses(c.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return IngressFromExtensionsV1beta1(ingress)
+	}
+
+	return c.Clientset.NetworkingV1().Ingresses(c.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// GetCustomResourceByName returns the custom resource that matches a given name. The
+// resource is read through the dynamic client from the first of the given API versions
+// served by the cluster
+func (c *Client) GetCustomResourceByName(resource string, groupVersions []string, name string) (*unstructured.Unstructured, error) {
+	resourceInterface, err := c.customResourceInterface(resource, groupVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceInterface.Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// ListCustomResources returns the custom resources in the namespace that match a label selector.
+// All namespaces are listed when the client namespace is empty. The resources are read through
+// the dynamic client from the first of the given API versions served by the cluster
+func (c *Client) ListCustomResources(resource string, groupVersions []string, selector string) (*unstructured.UnstructuredList, error) {
+	resourceInterface, err := c.customResourceInterface(resource, groupVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceInterface.List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+}
+
+// customResourceInterface returns the dynamic client interface of a custom resource
+// in the first of the given API versions served by the cluster
+func (c *Client) customResourceInterface(resource string, groupVersions []string) (dynamic.ResourceInterface, error) {
+	if c.Dynamic == nil {
+		return nil, fmt.Errorf("can not read %s without a dynamic client", resource)
+	}
+
+	groupVersion, err := c.getServedGroupVersion(resource, groupVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupVersion == "" {
+		return nil, fmt.Errorf("the server does not serve %s in any of the supported API versions %v", resource, groupVersions)
+	}
+
+	gv, err := schema.ParseGroupVersion(groupVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Dynamic.Resource(gv.WithResource(resource)).Namespace(c.Namespace), nil
+}
+
+// GetCustomResourceGroupVersion returns the first of the given API versions
+// that serves a custom resource, or an empty string when none of them does
+func (c *Client) GetCustomResourceGroupVersion(resource string, groupVersions []string) (string, error) {
+	return c.getServedGroupVersion(resource, groupVersions)
+}
+
+// GetIngressGroupVersion returns the newest ingress API version
+// served by the cluster
+func (c *Client) GetIngressGroupVersion() (string, error) {
+	groupVersion, err := c.getServedGroupVersion("ingresses", ingressGroupVersions)
+	if err != nil {
+		return "", err
+	}
+
+	if groupVersion == "" {
+		return "", fmt.Errorf("the server does not serve ingresses in any of the supported API versions %v", ingressGroupVersions)
+	}
+
+	return groupVersion, nil
+}
+
+// GetIngressClassGroupVersion returns the newest IngressClass API version
+// served by the cluster, or an empty string when ingress classes are not served
+func (c *Client) GetIngressClassGroupVersion() (string, error) {
+	return c.getServedGroupVersion("ingressclasses", ingressClassGroupVersions)
+}
+
+// GetEndpointSliceGroupVersion returns the newest endpoint slice API version
+// served by the cluster, or an empty string when endpoint slices are not served
+func (c *Client) GetEndpointSliceGroupVersion() (string, error) {
+	return c.getServedGroupVersion("endpointslices", endpointSliceGroupVersions)
+}
+
+// getServedGroupVersion returns the first of the given API versions that serves
+// a resource, or an empty string when none of them does
+func (c *Client) getServedGroupVersion(resourceName string, groupVersions []string) (string, error) {
+	// Resources of different groups may share a name, e.g. Gateway API and Istio gateways
+	cacheKey := resourceName + " " + strings.Join(groupVersions, ",")
+
+	if groupVersion, ok := c.servedGroupVersions[cacheKey]; ok {
+		return groupVersion, nil
+	}
+
+	groups, err := c.Clientset.Discovery().ServerGroups()
+	if err != nil {
+		return "", err
+	}
+
+	served := map[string]bool{}
+	for _, group := range groups.Groups {
+		for _, version := range group.Versions {
+			served[version.GroupVersion] = true
+		}
+	}
+
+	for _, groupVersion := range groupVersions {
+		if !served[groupVersion] {
+			continue
+		}
+
+		// The group version may be served without the resource,
+		// e.g. networking.k8s.io/v1 only serves network policies before 1.19
+		resources, err := c.Clientset.Discovery().ServerResourcesForGroupVersion(groupVersion)
+		if err != nil {
+			return "", err
+		}
+
+		for _, resource := range resources.APIResources {
+			if resource.Name == resourceName {
+				c.servedGroupVersions[cacheKey] = groupVersion
+				return groupVersion, nil
+			}
+		}
+	}
+
+	c.servedGroupVersions[cacheKey] = ""
+	return "", nil
+}
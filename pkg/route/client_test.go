@@ -0,0 +1,341 @@
+package route
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// NewFakeClient returns a Client backed by a fake clientset
+// that serves the given API resources
+func NewFakeClient(resources []*metav1.APIResourceList, objects ...runtime.Object) *Client {
+	clientset := fake.NewSimpleClientset(objects...)
+
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = resources
+
+	return NewClient(clientset, "default")
+}
+
+// NewFakeIngressClient returns a Client backed by a fake clientset
+// that serves ingresses in the given API versions
+func NewFakeIngressClient(groupVersions []string, objects ...runtime.Object) *Client {
+	resources := []*metav1.APIResourceList{}
+
+	for _, groupVersion := range groupVersions {
+		resources = append(resources, &metav1.APIResourceList{
+			GroupVersion: groupVersion,
+			APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress", Namespaced: true}},
+		})
+	}
+
+	return NewFakeClient(resources, objects...)
+}
+
+func TestClientGetIngressGroupVersion(t *testing.T) {
+
+	tests := []struct {
+		groupVersions        []string
+		expectedGroupVersion string
+	}{
+		{
+			[]string{IngressExtensionsV1beta1, IngressNetworkingV1beta1, IngressNetworkingV1},
+			IngressNetworkingV1,
+		},
+		{
+			[]string{IngressExtensionsV1beta1, IngressNetworkingV1beta1},
+			IngressNetworkingV1beta1,
+		},
+		{
+			[]string{IngressExtensionsV1beta1},
+			IngressExtensionsV1beta1,
+		},
+	}
+
+	for _, test := range tests {
+
+		client := NewFakeIngressClient(test.groupVersions)
+
+		groupVersion, err := client.GetIngressGroupVersion()
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		if groupVersion != test.expectedGroupVersion {
+			t.Errorf("Returned group version was incorrect, got: %s, want: %s", groupVersion, test.expectedGroupVersion)
+		}
+	}
+}
+
+func TestClientGetIngressGroupVersionNotServed(t *testing.T) {
+
+	client := NewFakeIngressClient([]string{})
+
+	if _, err := client.GetIngressGroupVersion(); err == nil {
+		t.Errorf("Expected an error when no ingress API version is served")
+	}
+}
+
+func TestClientGetIngressByName(t *testing.T) {
+
+	pathType := networkingv1.PathTypePrefix
+	className := "nginx"
+
+	tests := []struct {
+		groupVersion string
+		object       runtime.Object
+	}{
+		{
+			IngressNetworkingV1,
+			&networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: &className,
+					Rules: []networkingv1.IngressRule{
+						{
+							Host: "foo.com",
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
+										{
+											Path:     "/foo",
+											PathType: &pathType,
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: "service-foo",
+													Port: networkingv1.ServiceBackendPort{Name: "http"},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			IngressNetworkingV1beta1,
+			&networkingv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"},
+				Spec: networkingv1beta1.IngressSpec{
+					IngressClassName: &className,
+					Rules: []networkingv1beta1.IngressRule{
+						{
+							Host: "foo.com",
+							IngressRuleValue: networkingv1beta1.IngressRuleValue{
+								HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+									Paths: []networkingv1beta1.HTTPIngressPath{
+										{
+											Path:     "/foo",
+											PathType: (*networkingv1beta1.PathType)(&pathType),
+											Backend: networkingv1beta1.IngressBackend{
+												ServiceName: "service-foo",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			IngressExtensionsV1beta1,
+			&extensionsv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"},
+				Spec: extensionsv1beta1.IngressSpec{
+					IngressClassName: &className,
+					Rules: []extensionsv1beta1.IngressRule{
+						{
+							Host: "foo.com",
+							IngressRuleValue: extensionsv1beta1.IngressRuleValue{
+								HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
+									Paths: []extensionsv1beta1.HTTPIngressPath{
+										{
+											Path:     "/foo",
+											PathType: (*extensionsv1beta1.PathType)(&pathType),
+											Backend: extensionsv1beta1.IngressBackend{
+												ServiceName: "service-foo",
+												ServicePort: intstr.FromString("http"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+
+		client := NewFakeIngressClient([]string{test.groupVersion}, test.object)
+
+		ingress, err := client.GetIngressByName("ingress")
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", test.groupVersion, err)
+			continue
+		}
+
+		if *ingress.Spec.IngressClassName != className {
+			t.Errorf("Returned ingress class was incorrect for %s, got: %s, want: %s", test.groupVersion, *ingress.Spec.IngressClassName, className)
+		}
+
+		path := ingress.Spec.Rules[0].HTTP.Paths[0]
+
+		if path.Path != "/foo" || path.PathType == nil || *path.PathType != pathType {
+			t.Errorf("Returned path was incorrect for %s, got: %+v", test.groupVersion, path)
+		}
+
+		if path.Backend.Service == nil || path.Backend.Service.Name != "service-foo" || path.Backend.Service.Port.Name != "http" {
+			t.Errorf("Returned backend was incorrect for %s, got: %+v", test.groupVersion, path.Backend)
+		}
+	}
+}
+
+func TestClientGetEndpointSlicesByService(t *testing.T) {
+
+	ready := false
+	terminating := true
+	portName := "http"
+	port := int32(8080)
+	protocol := v1.ProtocolTCP
+
+	labels := map[string]string{discoveryv1.LabelServiceName: "service-foo"}
+
+	tests := []struct {
+		groupVersion string
+		object       runtime.Object
+	}{
+		{
+			EndpointSliceDiscoveryV1,
+			&discoveryv1.EndpointSlice{
+				ObjectMeta:  metav1.ObjectMeta{Name: "service-foo-abcde", Namespace: "default", Labels: labels},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses:  []string{"10.0.0.1"},
+						Conditions: discoveryv1.EndpointConditions{Ready: &ready, Terminating: &terminating},
+						TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: "pod-foo-1"},
+					},
+				},
+				Ports: []discoveryv1.EndpointPort{{Name: &portName, Port: &port, Protocol: &protocol}},
+			},
+		},
+		{
+			EndpointSliceDiscoveryV1beta1,
+			&discoveryv1beta1.EndpointSlice{
+				ObjectMeta:  metav1.ObjectMeta{Name: "service-foo-abcde", Namespace: "default", Labels: labels},
+				AddressType: discoveryv1beta1.AddressTypeIPv4,
+				Endpoints: []discoveryv1beta1.Endpoint{
+					{
+						Addresses:  []string{"10.0.0.1"},
+						Conditions: discoveryv1beta1.EndpointConditions{Ready: &ready, Terminating: &terminating},
+						TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: "pod-foo-1"},
+					},
+				},
+				Ports: []discoveryv1beta1.EndpointPort{{Name: &portName, Port: &port, Protocol: &protocol}},
+			},
+		},
+		{
+			"",
+			&v1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{Name: "service-foo", Namespace: "default"},
+				Subsets: []v1.EndpointSubset{
+					{
+						NotReadyAddresses: []v1.EndpointAddress{
+							{IP: "10.0.0.1", TargetRef: &v1.ObjectReference{Kind: "Pod", Name: "pod-foo-1"}},
+						},
+						Ports: []v1.EndpointPort{{Name: portName, Port: port, Protocol: protocol}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+
+		resources := []*metav1.APIResourceList{}
+		if test.groupVersion != "" {
+			resources = append(resources, &metav1.APIResourceList{
+				GroupVersion: test.groupVersion,
+				APIResources: []metav1.APIResource{{Name: "endpointslices", Kind: "EndpointSlice", Namespaced: true}},
+			})
+		}
+
+		client := NewFakeClient(resources, test.object)
+
+		endpointSlices, err := client.GetEndpointSlicesByService("service-foo")
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", test.groupVersion, err)
+			continue
+		}
+
+		if len(endpointSlices.Items) != 1 || len(endpointSlices.Items[0].Endpoints) != 1 {
+			t.Errorf("Returned endpoint slices were incorrect for %q, got: %+v", test.groupVersion, endpointSlices)
+			continue
+		}
+
+		endpoint := endpointSlices.Items[0].Endpoints[0]
+
+		if endpoint.Addresses[0] != "10.0.0.1" || *endpoint.Conditions.Ready || endpoint.TargetRef.Name != "pod-foo-1" {
+			t.Errorf("Returned endpoint was incorrect for %q, got: %+v", test.groupVersion, endpoint)
+		}
+
+		if *endpointSlices.Items[0].Ports[0].Port != port {
+			t.Errorf("Returned endpoint port was incorrect for %q, got: %d, want: %d", test.groupVersion, *endpointSlices.Items[0].Ports[0].Port, port)
+		}
+	}
+}
+
+func TestClientGetCustomResourceByName(t *testing.T) {
+
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: GatewayAPIV1beta1,
+			APIResources: []metav1.APIResource{{Name: "gateways", Kind: "Gateway", Namespaced: true}},
+		},
+	}
+
+	client := NewFakeCustomResourceClient(t, resources, nil, `
+apiVersion: gateway.networking.k8s.io/v1beta1
+kind: Gateway
+metadata:
+  name: gateway
+  namespace: default
+`)
+
+	gateway, err := client.GetCustomResourceByName("gateways", GatewayAPIGroupVersions, "gateway")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if gateway.GetAPIVersion() != GatewayAPIV1beta1 || gateway.GetName() != "gateway" {
+		t.Errorf("Returned custom resource was incorrect, got: %s %s", gateway.GetAPIVersion(), gateway.GetName())
+	}
+
+	if _, err := client.GetCustomResourceByName("httproutes", GatewayAPIGroupVersions, "route"); err == nil {
+		t.Errorf("Expected an error for a custom resource that is not served")
+	}
+
+	client.Dynamic = nil
+
+	if _, err := client.GetCustomResourceByName("gateways", GatewayAPIGroupVersions, "gateway"); err == nil {
+		t.Errorf("Expected an error without a dynamic client")
+	}
+}
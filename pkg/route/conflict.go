@@ -0,0 +1,305 @@
+package route
+
+import (
+	"sort"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// ConflictType defines the kind of overlap found between ingress paths
+type ConflictType string
+
+// Conflict types
+const (
+	ConflictDuplicatePath  ConflictType = "DuplicatePath"
+	ConflictShadowedPrefix ConflictType = "ShadowedPrefix"
+	ConflictHostCollision  ConflictType = "CrossNamespaceHost"
+	ConflictWildcardHost   ConflictType = "ShadowedWildcardHost"
+)
+
+// Conflict defines an overlap between the hosts or paths claimed by several ingresses.
+// Cross controller conflicts are the ones between ingresses of different classes,
+// which are served by different controllers instead of being merged by one of them
+type Conflict struct {
+	Severity        Severity          `json:"severity"`
+	Type            ConflictType      `json:"type"`
+	Host            string            `json:"host"`
+	Path            string            `json:"path,omitempty"`
+	CrossController bool              `json:"crossController,omitempty"`
+	Ingresses       []ConflictIngress `json:"ingresses"`
+	Message         string            `json:"message"`
+}
+
+// ConflictIngress defines an ingress path involved in a conflict, along with the class of its ingress
+type ConflictIngress struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Class     string `json:"class,omitempty"`
+	Path      string `json:"path,omitempty"`
+	PathType  string `json:"pathType,omitempty"`
+	Backend   string `json:"backend,omitempty"`
+}
+
+// conflictEntry defines an ingress path indexed by host
+type conflictEntry struct {
+	ConflictIngress
+	host string
+}
+
+// ResolveConflicts returns the conflicts between the paths of the ingresses that match a label selector,
+// in the namespace of the resolver or across all namespaces when it is empty: identical paths claimed by
+// several ingresses, prefixes of an ingress shadowed by the more specific paths of another ingress, hosts
+// claimed by ingresses of several namespaces, and wildcard hosts shadowed by the hosts they cover.
+// Rules without host are indexed as the "*" host
+func (r *Resolver) ResolveConflicts(selector string) ([]Conflict, error) {
+
+	ingresses, err := r.Client.ListIngresses(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(ingresses.Items, func(i, j int) bool {
+		return objectKey(ingresses.Items[i].ObjectMeta) < objectKey(ingresses.Items[j].ObjectMeta)
+	})
+
+	defaultClass, err := r.defaultIngressClassName()
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := map[string][]conflictEntry{}
+
+	for index := range ingresses.Items {
+		ingress := &ingresses.Items[index]
+
+		class := effectiveIngressClassName(ingress, defaultClass)
+
+		for _, rule := range ingress.Spec.Rules {
+
+			host := strings.ToLower(rule.Host)
+			if host == "" {
+				host = "*"
+			}
+
+			entry := conflictEntry{
+				ConflictIngress: ConflictIngress{Name: ingress.Name, Namespace: ingress.Namespace, Class: class},
+				host:            host,
+			}
+
+			// Rules without paths still claim their host
+			if rule.HTTP == nil {
+				hosts[host] = append(hosts[host], entry)
+				continue
+			}
+
+			for _, ingressPath := range rule.HTTP.Paths {
+				entry.Path = ingressPath.Path
+				entry.PathType = string(networkingv1.PathTypeImplementationSpecific)
+				if ingressPath.PathType != nil {
+					entry.PathType = string(*ingressPath.PathType)
+				}
+
+				entry.Backend = IngressBackendToString(ingressPath.Backend)
+
+				hosts[host] = append(hosts[host], entry)
+			}
+		}
+	}
+
+	conflicts := []Conflict{}
+
+	for _, host := range sortedHosts(hosts) {
+		conflicts = append(conflicts, hostConflicts(host, hosts[host])...)
+		conflicts = append(conflicts, wildcardHostConflicts(host, hosts)...)
+	}
+
+	return conflicts, nil
+}
+
+// hostConflicts returns the conflicts between the ingress paths of a host
+func hostConflicts(host string, entries []conflictEntry) []Conflict {
+
+	conflicts := []Conflict{}
+
+	// Namespaces do not isolate hosts, so they are usually owned by a single team
+	namespaces := map[string]bool{}
+	for _, entry := range entries {
+		namespaces[entry.Namespace] = true
+	}
+
+	if len(namespaces) > 1 && host != "*" {
+		conflicts = append(conflicts, newConflict(ConflictHostCollision, SeverityWarning, host, "", uniqueIngresses(entries),
+			"host is claimed by ingresses of "+joinSorted(namespaces)+" namespaces"))
+	}
+
+	duplicates := map[string][]conflictEntry{}
+	keys := []string{}
+
+	for _, entry := range entries {
+		if entry.Path == "" && entry.PathType == "" {
+			continue
+		}
+
+		key := pathKey(entry)
+		if _, ok := duplicates[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		duplicates[key] = append(duplicates[key], entry)
+	}
+
+	for _, key := range keys {
+		paths := duplicates[key]
+		if len(uniqueIngresses(paths)) < 2 {
+			continue
+		}
+
+		backends := map[string]bool{}
+		for _, entry := range paths {
+			backends[entry.Backend] = true
+		}
+
+		severity := SeverityWarning
+		message := "path is claimed by several ingresses with the same backend"
+
+		if len(backends) > 1 {
+			severity = SeverityError
+			message = "path is claimed by several ingresses with the backends " + joinSorted(backends) + ", only one of them receives the requests"
+		}
+
+		conflicts = append(conflicts, newConflict(ConflictDuplicatePath, severity, host, paths[0].Path, paths, message))
+	}
+
+	for _, prefix := range entries {
+		if prefix.PathType == string(networkingv1.PathTypeExact) || (prefix.Path == "" && prefix.PathType == "") {
+			continue
+		}
+
+		for _, specific := range entries {
+			if specific.Path == "" && specific.PathType == "" {
+				continue
+			}
+
+			sameIngress := specific.Name == prefix.Name && specific.Namespace == prefix.Namespace
+			if sameIngress || pathKey(specific) == pathKey(prefix) || !MatchPath(prefix.PathType, prefix.Path, specific.Path) {
+				continue
+			}
+
+			conflicts = append(conflicts, newConflict(ConflictShadowedPrefix, SeverityWarning, host, prefix.Path, []conflictEntry{prefix, specific},
+				"requests to "+specific.Path+" are served by "+specific.Namespace+"/"+specific.Name+" instead of "+prefix.Namespace+"/"+prefix.Name))
+		}
+	}
+
+	return conflicts
+}
+
+// wildcardHostConflicts returns the conflicts between the ingresses of a host and the ones of the
+// wildcard hosts that cover it. Controllers serve the requests of a host by its own rules rather
+// than the ones of a wildcard host, so the wildcard ingresses do not receive them
+func wildcardHostConflicts(host string, hosts map[string][]conflictEntry) []Conflict {
+
+	conflicts := []Conflict{}
+
+	if host == "*" || strings.HasPrefix(host, "*.") {
+		return conflicts
+	}
+
+	for _, wildcard := range sortedHosts(hosts) {
+		if !strings.HasPrefix(wildcard, "*.") || MatchHost(wildcard, host) != HostMatchWildcard {
+			continue
+		}
+
+		entries := uniqueIngresses(append(append([]conflictEntry{}, hosts[host]...), hosts[wildcard]...))
+		if len(entries) < 2 {
+			continue
+		}
+
+		conflicts = append(conflicts, newConflict(ConflictWildcardHost, SeverityWarning, host, "", entries,
+			"requests to "+host+" are served by the ingresses of the host instead of the ones of "+wildcard))
+	}
+
+	return conflicts
+}
+
+// newConflict returns a conflict between the given ingress paths, flagged
+// as cross controller when their ingresses are of different classes
+func newConflict(conflictType ConflictType, severity Severity, host string, path string, entries []conflictEntry, message string) Conflict {
+	conflict := Conflict{
+		Severity:  severity,
+		Type:      conflictType,
+		Host:      host,
+		Path:      path,
+		Ingresses: []ConflictIngress{},
+		Message:   message,
+	}
+
+	classes := map[string]bool{}
+	for _, entry := range entries {
+		conflict.Ingresses = append(conflict.Ingresses, entry.ConflictIngress)
+		classes[entry.Class] = true
+	}
+
+	if len(classes) > 1 {
+		conflict.CrossController = true
+		conflict.Message += "; the ingresses belong to the " + joinSorted(classes) + " classes, so their controllers serve them separately"
+	}
+
+	return conflict
+}
+
+// pathKey returns the key of an ingress path, the same for equivalent paths.
+// Prefix and ImplementationSpecific paths are compared element by element
+func pathKey(entry conflictEntry) string {
+	if entry.PathType == string(networkingv1.PathTypeExact) {
+		return "Exact " + entry.Path
+	}
+
+	return "Prefix /" + strings.Join(splitPath(entry.Path), "/")
+}
+
+// uniqueIngresses returns the entries of the distinct ingresses among the given ones
+func uniqueIngresses(entries []conflictEntry) []conflictEntry {
+	unique := []conflictEntry{}
+	seen := map[string]bool{}
+
+	for _, entry := range entries {
+		key := entry.Namespace + "/" + entry.Name
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		unique = append(unique, conflictEntry{ConflictIngress: ConflictIngress{Name: entry.Name, Namespace: entry.Namespace, Class: entry.Class}, host: entry.host})
+	}
+
+	return unique
+}
+
+// sortedHosts returns the hosts of an index sorted by name
+func sortedHosts(hosts map[string][]conflictEntry) []string {
+	sorted := []string{}
+	for host := range hosts {
+		sorted = append(sorted, host)
+	}
+
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// joinSorted returns the keys of a set sorted and separated by commas, with "<none>" for an empty key
+func joinSorted(set map[string]bool) string {
+	keys := []string{}
+	for key := range set {
+		if key == "" {
+			key = "<none>"
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return strings.Join(keys, ", ")
+}
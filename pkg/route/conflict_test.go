@@ -0,0 +1,196 @@
+package route
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// newConflictIngress returns an ingress of a class that routes the paths of a host to a service
+func newConflictIngress(name string, namespace string, class string, host string, pathType networkingv1.PathType, paths ...string) *networkingv1.Ingress {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host:             host,
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{}},
+				},
+			},
+		},
+	}
+
+	if class != "" {
+		ingress.Spec.IngressClassName = &class
+	}
+
+	for _, path := range paths {
+		ingress.Spec.Rules[0].HTTP.Paths = append(ingress.Spec.Rules[0].HTTP.Paths, networkingv1.HTTPIngressPath{
+			Path:     path,
+			PathType: &pathType,
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: name, Port: networkingv1.ServiceBackendPort{Number: 80}},
+			},
+		})
+	}
+
+	return ingress
+}
+
+func TestResolverResolveConflicts(t *testing.T) {
+
+	prefix := networkingv1.PathTypePrefix
+	exact := networkingv1.PathTypeExact
+
+	// Copies of an ingress route to the same backend
+	copied := newConflictIngress("shop-copy", "team-a", "nginx", "", exact, "/health")
+	copied.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name = "shop"
+
+	tests := []struct {
+		name     string
+		objects  []runtime.Object
+		expected []string
+	}{
+		{
+			"exact duplicate",
+			[]runtime.Object{
+				newConflictIngress("shop", "team-a", "nginx", "shop.com", prefix, "/api"),
+				newConflictIngress("cart", "team-a", "nginx", "shop.com", prefix, "/api/"),
+			},
+			[]string{"Error DuplicatePath shop.com /api/ team-a/cart (nginx), team-a/shop (nginx)"},
+		},
+		{
+			"shadowed prefix",
+			[]runtime.Object{
+				newConflictIngress("shop", "team-a", "nginx", "shop.com", prefix, "/"),
+				newConflictIngress("cart", "team-a", "nginx", "shop.com", exact, "/cart"),
+			},
+			[]string{"Warning ShadowedPrefix shop.com / team-a/shop (nginx), team-a/cart (nginx)"},
+		},
+		{
+			"cross namespace host",
+			[]runtime.Object{
+				newConflictIngress("shop", "team-a", "nginx", "Shop.com", prefix, "/shop"),
+				newConflictIngress("blog", "team-b", "nginx", "shop.com", prefix, "/blog"),
+			},
+			[]string{"Warning CrossNamespaceHost shop.com  team-a/shop (nginx), team-b/blog (nginx)"},
+		},
+		{
+			"cross controller duplicate",
+			[]runtime.Object{
+				newConflictIngress("shop", "team-a", "nginx", "shop.com", prefix, "/"),
+				newConflictIngress("shop", "team-b", "", "shop.com", prefix, "/"),
+				newIngressClass("traefik", "traefik.io/ingress-controller", true),
+			},
+			[]string{
+				"Warning CrossNamespaceHost shop.com  team-a/shop (nginx), team-b/shop (traefik) cross-controller",
+				"Warning DuplicatePath shop.com / team-a/shop (nginx), team-b/shop (traefik) cross-controller",
+			},
+		},
+		{
+			"cross controller duplicate with different backends",
+			[]runtime.Object{
+				newConflictIngress("shop", "team-a", "nginx", "shop.com", prefix, "/"),
+				newConflictIngress("cart", "team-a", "traefik", "shop.com", prefix, "/"),
+			},
+			[]string{"Error DuplicatePath shop.com / team-a/cart (traefik), team-a/shop (nginx) cross-controller"},
+		},
+		{
+			"wildcard host",
+			[]runtime.Object{
+				newConflictIngress("shop", "team-a", "nginx", "shop.example.com", prefix, "/shop"),
+				newConflictIngress("wildcard", "team-a", "nginx", "*.example.com", prefix, "/"),
+				newConflictIngress("nested", "team-a", "nginx", "a.shop.example.com", prefix, "/"),
+			},
+			[]string{"Warning ShadowedWildcardHost shop.example.com  team-a/shop (nginx), team-a/wildcard (nginx)"},
+		},
+		{
+			"same backend duplicate",
+			[]runtime.Object{
+				newConflictIngress("shop", "team-a", "nginx", "", exact, "/health"),
+				copied,
+			},
+			[]string{"Warning DuplicatePath * /health team-a/shop (nginx), team-a/shop-copy (nginx)"},
+		},
+		{
+			"no conflicts",
+			[]runtime.Object{
+				newConflictIngress("shop", "team-a", "nginx", "shop.com", prefix, "/shop", "/shop/cart"),
+				newConflictIngress("blog", "team-a", "nginx", "blog.com", prefix, "/"),
+				newConflictIngress("api", "team-a", "nginx", "shop.com", exact, "/api"),
+			},
+			[]string{},
+		},
+	}
+
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: IngressNetworkingV1,
+			APIResources: []metav1.APIResource{
+				{Name: "ingresses", Kind: "Ingress", Namespaced: true},
+				{Name: "ingressclasses", Kind: "IngressClass"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+
+		client := NewFakeClient(resources, test.objects...)
+		client.Namespace = ""
+
+		conflicts, err := NewResolver(client, "").ResolveConflicts("")
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		returned := []string{}
+		for _, conflict := range conflicts {
+			ingresses := []string{}
+			for _, ingress := range conflict.Ingresses {
+				ingresses = append(ingresses, ingress.Namespace+"/"+ingress.Name+" ("+ingress.Class+")")
+			}
+
+			summary := string(conflict.Severity) + " " + string(conflict.Type) + " " + conflict.Host + " " + conflict.Path + " " + strings.Join(ingresses, ", ")
+			if conflict.CrossController {
+				summary += " cross-controller"
+			}
+
+			returned = append(returned, summary)
+		}
+
+		if !reflect.DeepEqual(returned, test.expected) {
+			t.Errorf("Returned conflicts for %s were incorrect, got: %q, want: %q", test.name, returned, test.expected)
+		}
+	}
+}
+
+func TestResolverResolveConflictsForbidden(t *testing.T) {
+
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: IngressNetworkingV1,
+			APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress", Namespaced: true}},
+		},
+	}
+
+	fakeClient := NewFakeClient(resources,
+		newConflictIngress("shop", "team-a", "nginx", "shop.com", networkingv1.PathTypePrefix, "/"),
+		newConflictIngress("cart", "team-a", "", "shop.com", networkingv1.PathTypePrefix, "/"),
+	)
+	fakeClient.Namespace = ""
+
+	client := &forbiddenClient{ClientInterface: fakeClient, resources: map[string]bool{"ingressclasses": true}}
+
+	conflicts, err := NewResolver(client, "").ResolveConflicts("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(conflicts) != 1 || conflicts[0].Type != ConflictDuplicatePath || conflicts[0].Ingresses[0].Class != "" {
+		t.Errorf("Returned conflicts were incorrect, got: %+v", conflicts)
+	}
+}
@@ -0,0 +1,382 @@
+package route
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KindHTTPProxy is the kind of Contour HTTP proxies
+const KindHTTPProxy = "HTTPProxy"
+
+// httpProxyObject declares the fields of a Contour HTTP proxy used by the resolver
+type httpProxyObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		VirtualHost *struct {
+			FQDN string `json:"fqdn"`
+			TLS  *struct {
+				SecretName  string `json:"secretName,omitempty"`
+				Passthrough bool   `json:"passthrough,omitempty"`
+			} `json:"tls,omitempty"`
+		} `json:"virtualhost,omitempty"`
+		Routes []struct {
+			Conditions        []contourCondition `json:"conditions,omitempty"`
+			Services          []contourService   `json:"services,omitempty"`
+			PathRewritePolicy *struct {
+				ReplacePrefix []struct {
+					Prefix      string `json:"prefix,omitempty"`
+					Replacement string `json:"replacement"`
+				} `json:"replacePrefix,omitempty"`
+			} `json:"pathRewritePolicy,omitempty"`
+			RetryPolicy *struct {
+				Count         int64    `json:"count,omitempty"`
+				PerTryTimeout string   `json:"perTryTimeout,omitempty"`
+				RetryOn       []string `json:"retryOn,omitempty"`
+			} `json:"retryPolicy,omitempty"`
+			RequestHeadersPolicy  *contourHeadersPolicy `json:"requestHeadersPolicy,omitempty"`
+			ResponseHeadersPolicy *contourHeadersPolicy `json:"responseHeadersPolicy,omitempty"`
+		} `json:"routes,omitempty"`
+		Includes []struct {
+			Name       string             `json:"name"`
+			Namespace  string             `json:"namespace,omitempty"`
+			Conditions []contourCondition `json:"conditions,omitempty"`
+		} `json:"includes,omitempty"`
+		TCPProxy *struct {
+			Services []contourService `json:"services,omitempty"`
+		} `json:"tcpproxy,omitempty"`
+	} `json:"spec"`
+}
+
+type contourCondition struct {
+	Prefix         string                 `json:"prefix,omitempty"`
+	Exact          string                 `json:"exact,omitempty"`
+	Regex          string                 `json:"regex,omitempty"`
+	Header         *contourMatchCondition `json:"header,omitempty"`
+	QueryParameter *contourMatchCondition `json:"queryParameter,omitempty"`
+}
+
+type contourMatchCondition struct {
+	Name        string `json:"name"`
+	Present     bool   `json:"present,omitempty"`
+	NotPresent  bool   `json:"notpresent,omitempty"`
+	Exact       string `json:"exact,omitempty"`
+	NotExact    string `json:"notexact,omitempty"`
+	Contains    string `json:"contains,omitempty"`
+	NotContains string `json:"notcontains,omitempty"`
+	Prefix      string `json:"prefix,omitempty"`
+	Suffix      string `json:"suffix,omitempty"`
+	Regex       string `json:"regex,omitempty"`
+}
+
+type contourService struct {
+	Name   string `json:"name"`
+	Port   int32  `json:"port"`
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+type contourHeadersPolicy struct {
+	Set []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"set,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// ResolveHTTPProxy returns the route graph of the Contour HTTP proxy that matches a given name.
+// Included proxies of any namespace are followed, with the conditions of the includes
+// prepended to the conditions of their routes
+func (r *Resolver) ResolveHTTPProxy(name string) (*Route, error) {
+
+	object, err := r.Client.GetCustomResourceByName("httpproxies", ContourGroupVersions, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if object == nil {
+		return nil, fmt.Errorf("httpproxy %q not found", name)
+	}
+
+	httpProxy := &httpProxyObject{}
+	if err := fromUnstructured(object, httpProxy); err != nil {
+		return nil, err
+	}
+
+	// Proxies without virtual host are only reachable through the proxies that include them
+	host := Host{Paths: []Path{}}
+
+	if virtualHost := httpProxy.Spec.VirtualHost; virtualHost != nil {
+		host.Host = virtualHost.FQDN
+
+		if virtualHost.TLS != nil {
+			host.TLS = &TLS{Termination: "edge"}
+
+			if virtualHost.TLS.Passthrough {
+				host.TLS.Termination = "passthrough"
+			}
+		}
+	}
+
+	visited := map[string]bool{objectKey(metav1.ObjectMeta{Namespace: r.Namespace, Name: httpProxy.Name}): true}
+
+	paths, err := r.newHTTPProxyPaths(httpProxy, "", nil, visited)
+	if err != nil {
+		return nil, err
+	}
+
+	host.Paths = paths
+
+	return &Route{
+		Kind:      KindHTTPProxy,
+		Name:      httpProxy.Name,
+		Namespace: r.Namespace,
+		Hosts:     []Host{host},
+	}, nil
+}
+
+// newHTTPProxyPaths returns the paths of the routes of an HTTP proxy and of the proxies it includes.
+// The resolver namespace is the one of the proxy, and parent conditions are the ones of the includes
+// that lead to it. The paths of included proxies are flagged with their namespace and name
+func (r *Resolver) newHTTPProxyPaths(httpProxy *httpProxyObject, include string, parentConditions []contourCondition, visited map[string]bool) ([]Path, error) {
+
+	paths := []Path{}
+
+	for _, proxyRoute := range httpProxy.Spec.Routes {
+
+		backends, err := r.newContourBackends(proxyRoute.Services)
+		if err != nil {
+			return nil, err
+		}
+
+		template := newContourPath(append(append([]contourCondition{}, parentConditions...), proxyRoute.Conditions...))
+		template.Include = include
+
+		if policy := proxyRoute.PathRewritePolicy; policy != nil {
+			rewrites := []string{}
+
+			for _, replacePrefix := range policy.ReplacePrefix {
+				rewrites = append(rewrites, "uri="+replacePrefix.Replacement)
+			}
+
+			template.Rewrite = strings.Join(rewrites, ",")
+		}
+
+		if policy := proxyRoute.RetryPolicy; policy != nil {
+			retries := []string{"attempts=" + strconv.FormatInt(policy.Count, 10)}
+
+			if policy.PerTryTimeout != "" {
+				retries = append(retries, "perTryTimeout="+policy.PerTryTimeout)
+			}
+
+			if len(policy.RetryOn) > 0 {
+				retries = append(retries, "retryOn="+strings.Join(policy.RetryOn, ","))
+			}
+
+			template.Retries = strings.Join(retries, ",")
+		}
+
+		template.Filters = append(contourHeadersPolicyToFilters("requestHeaders", proxyRoute.RequestHeadersPolicy),
+			contourHeadersPolicyToFilters("responseHeaders", proxyRoute.ResponseHeadersPolicy)...)
+
+		if len(template.Filters) == 0 {
+			template.Filters = nil
+		}
+
+		for _, backend := range backends {
+			path := template
+			path.BackendPort = backend.BackendPort
+			path.Weight = backend.Weight
+			path.Service = backend.Service
+
+			paths = append(paths, path)
+		}
+	}
+
+	if tcpProxy := httpProxy.Spec.TCPProxy; tcpProxy != nil {
+
+		backends, err := r.newContourBackends(tcpProxy.Services)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, backend := range backends {
+			backend.Protocol = "TCP"
+			backend.Include = include
+
+			paths = append(paths, backend)
+		}
+	}
+
+	for _, proxyInclude := range httpProxy.Spec.Includes {
+
+		namespace := r.Namespace
+		if proxyInclude.Namespace != "" {
+			namespace = proxyInclude.Namespace
+		}
+
+		conditions := append(append([]contourCondition{}, parentConditions...), proxyInclude.Conditions...)
+		key := objectKey(metav1.ObjectMeta{Namespace: namespace, Name: proxyInclude.Name})
+
+		// Include cycles are rejected by Contour, so they are not followed again
+		if visited[key] {
+			continue
+		}
+
+		resolver := r.forNamespace(namespace)
+
+		object, err := resolver.Client.GetCustomResourceByName("httpproxies", ContourGroupVersions, proxyInclude.Name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		// Missing included proxies are reported as backends that are not found
+		if err != nil || object == nil {
+			path := newContourPath(conditions)
+			path.Service = &Service{Name: proxyInclude.Name, Namespace: namespace, Type: KindHTTPProxy}
+
+			paths = append(paths, path)
+			continue
+		}
+
+		included := &httpProxyObject{}
+		if err := fromUnstructured(object, included); err != nil {
+			return nil, err
+		}
+
+		visited[key] = true
+
+		includedPaths, err := resolver.newHTTPProxyPaths(included, key, conditions, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		delete(visited, key)
+
+		paths = append(paths, includedPaths...)
+	}
+
+	return paths, nil
+}
+
+// newContourBackends returns a path per service of a route. Contour spreads the traffic evenly
+// between services when none has a weight, and services without weight get no traffic otherwise
+func (r *Resolver) newContourBackends(services []contourService) ([]Path, error) {
+
+	weighted := false
+	for _, service := range services {
+		if service.Weight != nil {
+			weighted = true
+		}
+	}
+
+	backends := []Path{}
+
+	for _, service := range services {
+
+		weight := int32(1)
+		if weighted {
+			weight = 0
+		}
+
+		if service.Weight != nil {
+			weight = *service.Weight
+		}
+
+		backendService, err := r.resolveBackendService(service.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		backends = append(backends, Path{
+			BackendPort: strconv.FormatInt(int64(service.Port), 10),
+			Weight:      &weight,
+			Service:     backendService,
+		})
+	}
+
+	return backends, nil
+}
+
+// newContourPath returns a path with the request conditions of Contour match conditions.
+// Prefixes of the includes and of the route are joined, and values are written as
+// name=value for exact matches, name!=value for negated ones, name^=value and name$=value
+// for prefix and suffix matches, name*=value for substring matches and name~=value for
+// regular expression matches
+func newContourPath(conditions []contourCondition) Path {
+
+	path := Path{PathType: "Prefix"}
+	prefix := ""
+
+	for _, condition := range conditions {
+		switch {
+		case condition.Prefix != "":
+			prefix = strings.TrimSuffix(prefix, "/") + condition.Prefix
+
+		case condition.Exact != "":
+			prefix = strings.TrimSuffix(prefix, "/") + condition.Exact
+			path.PathType = "Exact"
+
+		case condition.Regex != "":
+			prefix = strings.TrimSuffix(prefix, "/") + condition.Regex
+			path.PathType = "RegularExpression"
+
+		case condition.Header != nil:
+			path.Headers = append(path.Headers, contourMatchConditionToString(condition.Header))
+
+		case condition.QueryParameter != nil:
+			path.QueryParams = append(path.QueryParams, contourMatchConditionToString(condition.QueryParameter))
+		}
+	}
+
+	path.Path = prefix
+	if path.Path == "" {
+		path.Path = "/"
+	}
+
+	return path
+}
+
+func contourMatchConditionToString(condition *contourMatchCondition) string {
+	switch {
+	case condition.Present:
+		return condition.Name
+	case condition.NotPresent:
+		return "!" + condition.Name
+	case condition.NotExact != "":
+		return condition.Name + "!=" + condition.NotExact
+	case condition.Contains != "":
+		return condition.Name + "*=" + condition.Contains
+	case condition.NotContains != "":
+		return condition.Name + "!*=" + condition.NotContains
+	case condition.Prefix != "":
+		return condition.Name + "^=" + condition.Prefix
+	case condition.Suffix != "":
+		return condition.Name + "$=" + condition.Suffix
+	case condition.Regex != "":
+		return condition.Name + "~=" + condition.Regex
+	}
+
+	return condition.Name + "=" + condition.Exact
+}
+
+// contourHeadersPolicyToFilters returns the header changes of a policy, e.g. requestHeaders:set x-foo=bar
+func contourHeadersPolicyToFilters(kind string, policy *contourHeadersPolicy) []string {
+	filters := []string{}
+
+	if policy == nil {
+		return filters
+	}
+
+	for _, header := range policy.Set {
+		filters = append(filters, kind+":set "+header.Name+"="+header.Value)
+	}
+
+	for _, header := range policy.Remove {
+		filters = append(filters, kind+":remove "+header)
+	}
+
+	return filters
+}
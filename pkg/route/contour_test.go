@@ -0,0 +1,163 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestResolverResolveHTTPProxy(t *testing.T) {
+
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: ContourV1,
+			APIResources: []metav1.APIResource{{Name: "httpproxies", Kind: "HTTPProxy", Namespaced: true}},
+		},
+	}
+
+	objects := []runtime.Object{
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-web", Namespace: "default"},
+			Spec: v1.ServiceSpec{
+				Type:  v1.ServiceTypeClusterIP,
+				Ports: []v1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(8080), Protocol: v1.ProtocolTCP}},
+			},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-api", Namespace: "team"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeExternalName, ExternalName: "api.example.com"},
+		},
+	}
+
+	client := NewFakeCustomResourceClient(t, resources, objects, `
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: root
+  namespace: default
+spec:
+  virtualhost:
+    fqdn: foo.com
+    tls:
+      secretName: foo-tls
+  routes:
+  - services:
+    - name: service-web
+      port: 80
+    requestHeadersPolicy:
+      set:
+      - name: X-Team
+        value: web
+  includes:
+  - name: api
+    namespace: team
+    conditions:
+    - prefix: /api
+  - name: missing
+    conditions:
+    - prefix: /missing
+`, `
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: api
+  namespace: team
+spec:
+  routes:
+  - conditions:
+    - prefix: /v1
+    - header:
+        name: X-Canary
+        present: true
+    pathRewritePolicy:
+      replacePrefix:
+      - replacement: /
+    retryPolicy:
+      count: 2
+      retryOn:
+      - 5xx
+    services:
+    - name: service-api
+      port: 443
+      weight: 90
+    - name: service-api-next
+      port: 443
+  includes:
+  - name: root
+    namespace: default
+`)
+
+	weight0 := int32(0)
+	weight1 := int32(1)
+	weight90 := int32(90)
+
+	serviceAPI := &Service{Name: "service-api", Namespace: "team", Found: true, Type: "ExternalName", Hostname: "api.example.com"}
+
+	expected := &Route{
+		Kind:      "HTTPProxy",
+		Name:      "root",
+		Namespace: "default",
+		Hosts: []Host{
+			{
+				Host: "foo.com",
+				TLS:  &TLS{Termination: "edge"},
+				Paths: []Path{
+					{
+						Path:        "/",
+						PathType:    "Prefix",
+						Filters:     []string{"requestHeaders:set X-Team=web"},
+						BackendPort: "80",
+						Weight:      &weight1,
+						Service: &Service{
+							Name:      "service-web",
+							Namespace: "default",
+							Found:     true,
+							Type:      "ClusterIP",
+							Ports:     []Port{{Port: 80, TargetPort: "8080", Protocol: "TCP"}},
+						},
+					},
+					{
+						Path:        "/api/v1",
+						PathType:    "Prefix",
+						Headers:     []string{"X-Canary"},
+						Rewrite:     "uri=/",
+						Retries:     "attempts=2,retryOn=5xx",
+						Include:     "team/api",
+						BackendPort: "443",
+						Weight:      &weight90,
+						Service:     serviceAPI,
+					},
+					{
+						Path:        "/api/v1",
+						PathType:    "Prefix",
+						Headers:     []string{"X-Canary"},
+						Rewrite:     "uri=/",
+						Retries:     "attempts=2,retryOn=5xx",
+						Include:     "team/api",
+						BackendPort: "443",
+						Weight:      &weight0,
+						Service:     &Service{Name: "service-api-next", Namespace: "team"},
+					},
+					{
+						Path:     "/missing",
+						PathType: "Prefix",
+						Service:  &Service{Name: "missing", Namespace: "default", Type: "HTTPProxy"},
+					},
+				},
+			},
+		},
+	}
+
+	route, err := NewResolver(client, "default").ResolveHTTPProxy("root")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(route, expected) {
+		t.Errorf("Returned route was incorrect,\ngot:\n%+v\nwant:\n%+v", route, expected)
+	}
+}
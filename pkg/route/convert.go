@@ -0,0 +1,224 @@
+package route
+
+import (
+	"encoding/json"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// IngressFromExtensionsV1beta1 converts an extensions/v1beta1 ingress to networking.k8s.io/v1
+func IngressFromExtensionsV1beta1(in *extensionsv1beta1.Ingress) (*networkingv1.Ingress, error) {
+
+	// Both v1beta1 ingress types share the same serialized schema
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	ingress := &networkingv1beta1.Ingress{}
+	if err := json.Unmarshal(data, ingress); err != nil {
+		return nil, err
+	}
+
+	return IngressFromNetworkingV1beta1(ingress), nil
+}
+
+// IngressClassFromNetworkingV1beta1 converts a networking.k8s.io/v1beta1 ingress class to networking.k8s.io/v1
+func IngressClassFromNetworkingV1beta1(in *networkingv1beta1.IngressClass) (*networkingv1.IngressClass, error) {
+
+	// Both ingress class versions share the same serialized schema
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	ingressClass := &networkingv1.IngressClass{}
+	if err := json.Unmarshal(data, ingressClass); err != nil {
+		return nil, err
+	}
+
+	ingressClass.APIVersion = IngressNetworkingV1
+
+	return ingressClass, nil
+}
+
+// IngressFromNetworkingV1beta1 converts a networking.k8s.io/v1beta1 ingress to networking.k8s.io/v1
+func IngressFromNetworkingV1beta1(in *networkingv1beta1.Ingress) *networkingv1.Ingress {
+	out := &networkingv1.Ingress{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: in.Spec.IngressClassName,
+		},
+		Status: networkingv1.IngressStatus{
+			LoadBalancer: in.Status.LoadBalancer,
+		},
+	}
+
+	out.APIVersion = IngressNetworkingV1
+
+	if in.Spec.Backend != nil {
+		out.Spec.DefaultBackend = ingressBackendFromNetworkingV1beta1(*in.Spec.Backend)
+	}
+
+	for _, tls := range in.Spec.TLS {
+		out.Spec.TLS = append(out.Spec.TLS, networkingv1.IngressTLS{
+			Hosts:      tls.Hosts,
+			SecretName: tls.SecretName,
+		})
+	}
+
+	for _, rule := range in.Spec.Rules {
+		outRule := networkingv1.IngressRule{Host: rule.Host}
+
+		if rule.HTTP != nil {
+			outRule.HTTP = &networkingv1.HTTPIngressRuleValue{}
+
+			for _, path := range rule.HTTP.Paths {
+				outPath := networkingv1.HTTPIngressPath{
+					Path:    path.Path,
+					Backend: *ingressBackendFromNetworkingV1beta1(path.Backend),
+				}
+
+				if path.PathType != nil {
+					pathType := networkingv1.PathType(*path.PathType)
+					outPath.PathType = &pathType
+				}
+
+				outRule.HTTP.Paths = append(outRule.HTTP.Paths, outPath)
+			}
+		}
+
+		out.Spec.Rules = append(out.Spec.Rules, outRule)
+	}
+
+	return out
+}
+
+func ingressBackendFromNetworkingV1beta1(in networkingv1beta1.IngressBackend) *networkingv1.IngressBackend {
+	out := &networkingv1.IngressBackend{
+		Resource: in.Resource,
+	}
+
+	if in.ServiceName != "" {
+		out.Service = &networkingv1.IngressServiceBackend{
+			Name: in.ServiceName,
+		}
+
+		switch in.ServicePort.Type {
+		case intstr.Int:
+			out.Service.Port.Number = in.ServicePort.IntVal
+		case intstr.String:
+			out.Service.Port.Name = in.ServicePort.StrVal
+		}
+	}
+
+	return out
+}
+
+// EndpointSliceFromDiscoveryV1beta1 converts a discovery.k8s.io/v1beta1 endpoint slice to discovery.k8s.io/v1
+func EndpointSliceFromDiscoveryV1beta1(in *discoveryv1beta1.EndpointSlice) *discoveryv1.EndpointSlice {
+	out := &discoveryv1.EndpointSlice{
+		TypeMeta:    in.TypeMeta,
+		ObjectMeta:  in.ObjectMeta,
+		AddressType: discoveryv1.AddressType(in.AddressType),
+	}
+
+	out.APIVersion = EndpointSliceDiscoveryV1
+
+	for _, endpoint := range in.Endpoints {
+		outEndpoint := discoveryv1.Endpoint{
+			Addresses: endpoint.Addresses,
+			Conditions: discoveryv1.EndpointConditions{
+				Ready:       endpoint.Conditions.Ready,
+				Serving:     endpoint.Conditions.Serving,
+				Terminating: endpoint.Conditions.Terminating,
+			},
+			Hostname:           endpoint.Hostname,
+			TargetRef:          endpoint.TargetRef,
+			DeprecatedTopology: endpoint.Topology,
+			NodeName:           endpoint.NodeName,
+		}
+
+		if zone, ok := endpoint.Topology[v1.LabelTopologyZone]; ok {
+			outEndpoint.Zone = &zone
+		}
+
+		out.Endpoints = append(out.Endpoints, outEndpoint)
+	}
+
+	for _, port := range in.Ports {
+		out.Ports = append(out.Ports, discoveryv1.EndpointPort{
+			Name:        port.Name,
+			Protocol:    port.Protocol,
+			Port:        port.Port,
+			AppProtocol: port.AppProtocol,
+		})
+	}
+
+	return out
+}
+
+// EndpointSlicesFromEndpoints converts core v1 endpoints to discovery.k8s.io/v1 endpoint slices,
+// one per endpoints subset. Endpoints do not report terminating addresses
+func EndpointSlicesFromEndpoints(in *v1.Endpoints) *discoveryv1.EndpointSliceList {
+	out := &discoveryv1.EndpointSliceList{}
+
+	for _, subset := range in.Subsets {
+		endpointSlice := discoveryv1.EndpointSlice{
+			ObjectMeta: in.ObjectMeta,
+		}
+
+		endpointSlice.AddressType = discoveryv1.AddressTypeIPv4
+
+		for _, address := range subset.Addresses {
+			endpointSlice.Endpoints = append(endpointSlice.Endpoints, endpointFromAddress(address, true))
+		}
+
+		for _, address := range subset.NotReadyAddresses {
+			endpointSlice.Endpoints = append(endpointSlice.Endpoints, endpointFromAddress(address, false))
+		}
+
+		for _, endpoint := range endpointSlice.Endpoints {
+			if strings.Contains(endpoint.Addresses[0], ":") {
+				endpointSlice.AddressType = discoveryv1.AddressTypeIPv6
+			}
+		}
+
+		for index := range subset.Ports {
+			port := subset.Ports[index]
+			endpointSlice.Ports = append(endpointSlice.Ports, discoveryv1.EndpointPort{
+				Name:        &port.Name,
+				Protocol:    &port.Protocol,
+				Port:        &port.Port,
+				AppProtocol: port.AppProtocol,
+			})
+		}
+
+		out.Items = append(out.Items, endpointSlice)
+	}
+
+	return out
+}
+
+func endpointFromAddress(address v1.EndpointAddress, ready bool) discoveryv1.Endpoint {
+	endpoint := discoveryv1.Endpoint{
+		Addresses:  []string{address.IP},
+		Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+		TargetRef:  address.TargetRef,
+		NodeName:   address.NodeName,
+	}
+
+	if address.Hostname != "" {
+		endpoint.Hostname = &address.Hostname
+	}
+
+	return endpoint
+}
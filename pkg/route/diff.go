@@ -0,0 +1,346 @@
+package route
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Route diff schema identifiers
+const (
+	SnapshotKind = "RouteSnapshot"
+	DiffKind     = "RouteDiff"
+)
+
+// ChangeType defines whether an element of the route graph was added, removed or modified
+type ChangeType string
+
+// Change types
+const (
+	ChangeAdded    ChangeType = "Added"
+	ChangeRemoved  ChangeType = "Removed"
+	ChangeModified ChangeType = "Modified"
+)
+
+// ChangeKind defines the element of the route graph that changed
+type ChangeKind string
+
+// Change kinds
+const (
+	ChangeKindIngress ChangeKind = "Ingress"
+	ChangeKindService ChangeKind = "Service"
+	ChangeKindHost    ChangeKind = "Host"
+	ChangeKindPath    ChangeKind = "Path"
+	ChangeKindBackend ChangeKind = "Backend"
+	ChangeKindPort    ChangeKind = "Port"
+	ChangeKindPods    ChangeKind = "Pods"
+)
+
+// Change defines a difference between two route graphs. Name is the host, path or port that
+// changed within the object, from and to are the old and new values of modified backends and
+// ports, and added and removed are the pods that joined or left a service
+type Change struct {
+	Type    ChangeType `json:"type"`
+	Kind    ChangeKind `json:"kind"`
+	Object  string     `json:"object"`
+	Name    string     `json:"name,omitempty"`
+	From    string     `json:"from,omitempty"`
+	To      string     `json:"to,omitempty"`
+	Added   []string   `json:"added,omitempty"`
+	Removed []string   `json:"removed,omitempty"`
+}
+
+// Diff is the machine-readable representation of the changes between two route
+// snapshots, or between a route snapshot and the live cluster
+type Diff struct {
+	APIVersion string     `json:"apiVersion"`
+	Kind       string     `json:"kind"`
+	From       *time.Time `json:"from,omitempty"`
+	To         *time.Time `json:"to,omitempty"`
+	Changes    []Change   `json:"changes"`
+}
+
+// NewSnapshot returns a route document with the given ingresses and services, taken at the given time
+func NewSnapshot(ingresses []*Route, services []*Service, timestamp time.Time) *Document {
+	timestamp = timestamp.UTC().Truncate(time.Second)
+
+	return &Document{
+		APIVersion: DocumentAPIVersion,
+		Kind:       SnapshotKind,
+		Timestamp:  &timestamp,
+		Ingresses:  ingresses,
+		Services:   services,
+	}
+}
+
+// DiffDocuments returns the changes of the ingresses and services of a route document into the ones
+// of another document: added and removed objects, hosts, paths and ports, modified backends and ports,
+// and the pods that joined or left each service. Changes are sorted by object
+func DiffDocuments(from *Document, to *Document) *Diff {
+	diff := &Diff{
+		APIVersion: DocumentAPIVersion,
+		Kind:       DiffKind,
+		From:       from.Timestamp,
+		To:         to.Timestamp,
+		Changes:    []Change{},
+	}
+
+	fromIngresses := routesByObject(from.Ingresses)
+	toIngresses := routesByObject(to.Ingresses)
+
+	for _, object := range unionKeys(fromIngresses, toIngresses) {
+		diff.Changes = append(diff.Changes, diffRoutes(object, fromIngresses[object], toIngresses[object])...)
+	}
+
+	fromServices := servicesByObject(from.Services)
+	toServices := servicesByObject(to.Services)
+
+	for _, object := range unionKeys(fromServices, toServices) {
+		diff.Changes = append(diff.Changes, diffServices(object, fromServices[object], toServices[object])...)
+	}
+
+	return diff
+}
+
+// diffRoutes returns the changes between two versions of an ingress, either of which may be missing
+func diffRoutes(object string, from *Route, to *Route) []Change {
+
+	switch {
+	case from == nil:
+		return []Change{{Type: ChangeAdded, Kind: ChangeKindIngress, Object: object}}
+	case to == nil:
+		return []Change{{Type: ChangeRemoved, Kind: ChangeKindIngress, Object: object}}
+	}
+
+	changes := []Change{}
+
+	fromHosts := hostsByName(from.Hosts)
+	toHosts := hostsByName(to.Hosts)
+
+	for _, name := range unionKeys(fromHosts, toHosts) {
+		fromHost, inFrom := fromHosts[name]
+		toHost, inTo := toHosts[name]
+
+		switch {
+		case !inFrom:
+			changes = append(changes, Change{Type: ChangeAdded, Kind: ChangeKindHost, Object: object, Name: name})
+			continue
+		case !inTo:
+			changes = append(changes, Change{Type: ChangeRemoved, Kind: ChangeKindHost, Object: object, Name: name})
+			continue
+		}
+
+		fromPaths := pathsByKey(name, fromHost.Paths)
+		toPaths := pathsByKey(name, toHost.Paths)
+
+		for _, key := range unionKeys(fromPaths, toPaths) {
+			fromPath, inFrom := fromPaths[key]
+			toPath, inTo := toPaths[key]
+
+			switch {
+			case !inFrom:
+				changes = append(changes, Change{Type: ChangeAdded, Kind: ChangeKindPath, Object: object, Name: key, To: backendToString(toPath)})
+			case !inTo:
+				changes = append(changes, Change{Type: ChangeRemoved, Kind: ChangeKindPath, Object: object, Name: key, From: backendToString(fromPath)})
+			case backendToString(fromPath) != backendToString(toPath):
+				changes = append(changes, Change{Type: ChangeModified, Kind: ChangeKindBackend, Object: object, Name: key, From: backendToString(fromPath), To: backendToString(toPath)})
+			}
+		}
+	}
+
+	if fromBackend, toBackend := backendToString(from.DefaultBackend), backendToString(to.DefaultBackend); fromBackend != toBackend {
+		change := Change{Kind: ChangeKindBackend, Object: object, Name: "(default backend)", From: fromBackend, To: toBackend}
+
+		switch {
+		case fromBackend == "":
+			change.Type = ChangeAdded
+		case toBackend == "":
+			change.Type = ChangeRemoved
+		default:
+			change.Type = ChangeModified
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// diffServices returns the changes between two versions of a service, either of which may be missing
+func diffServices(object string, from *Service, to *Service) []Change {
+
+	switch {
+	case from == nil:
+		return []Change{{Type: ChangeAdded, Kind: ChangeKindService, Object: object}}
+	case to == nil:
+		return []Change{{Type: ChangeRemoved, Kind: ChangeKindService, Object: object}}
+	}
+
+	changes := []Change{}
+
+	fromPorts := portsByName(from.Ports)
+	toPorts := portsByName(to.Ports)
+
+	for _, name := range unionKeys(fromPorts, toPorts) {
+		fromPort, inFrom := fromPorts[name]
+		toPort, inTo := toPorts[name]
+
+		switch {
+		case !inFrom:
+			changes = append(changes, Change{Type: ChangeAdded, Kind: ChangeKindPort, Object: object, Name: name, To: toPort})
+		case !inTo:
+			changes = append(changes, Change{Type: ChangeRemoved, Kind: ChangeKindPort, Object: object, Name: name, From: fromPort})
+		case fromPort != toPort:
+			changes = append(changes, Change{Type: ChangeModified, Kind: ChangeKindPort, Object: object, Name: name, From: fromPort, To: toPort})
+		}
+	}
+
+	fromPods := podsByName(from.Pods)
+	toPods := podsByName(to.Pods)
+
+	change := Change{Type: ChangeModified, Kind: ChangeKindPods, Object: object}
+
+	for _, name := range unionKeys(fromPods, toPods) {
+		_, inFrom := fromPods[name]
+		_, inTo := toPods[name]
+
+		switch {
+		case !inFrom:
+			change.Added = append(change.Added, name)
+		case !inTo:
+			change.Removed = append(change.Removed, name)
+		}
+	}
+
+	if len(change.Added) > 0 || len(change.Removed) > 0 {
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// routesByObject returns the given routes indexed by their kind, namespace and name
+func routesByObject(routes []*Route) map[string]*Route {
+	indexed := map[string]*Route{}
+	for _, route := range routes {
+		indexed["Ingress/"+route.Namespace+"/"+route.Name] = route
+	}
+
+	return indexed
+}
+
+// servicesByObject returns the given services indexed by their kind, namespace and name
+func servicesByObject(services []*Service) map[string]*Service {
+	indexed := map[string]*Service{}
+	for _, service := range services {
+		indexed["Service/"+service.Namespace+"/"+service.Name] = service
+	}
+
+	return indexed
+}
+
+// hostsByName returns the given hosts indexed by name, with "*" for rules without host
+func hostsByName(hosts []Host) map[string]Host {
+	indexed := map[string]Host{}
+	for _, host := range hosts {
+		name := host.Host
+		if name == "" {
+			name = "*"
+		}
+
+		indexed[name] = host
+	}
+
+	return indexed
+}
+
+// pathsByKey returns the given paths of a host indexed by host, path and path type,
+// along with the extra request conditions that tell apart paths with the same value
+func pathsByKey(host string, paths []Path) map[string]*Path {
+	indexed := map[string]*Path{}
+	for index, path := range paths {
+		key := host + path.Path
+		if path.PathType != "" {
+			key += " (" + path.PathType + ")"
+		}
+
+		conditions := append(append(append([]string{}, path.Headers...), path.QueryParams...), path.Conditions...)
+		if path.Method != "" {
+			conditions = append(conditions, path.Method)
+		}
+
+		if len(conditions) > 0 {
+			key += " [" + strings.Join(conditions, ", ") + "]"
+		}
+
+		indexed[key] = &paths[index]
+	}
+
+	return indexed
+}
+
+// portsByName returns the given service ports in the "port:targetPort/protocol" format,
+// indexed by name, or by port number for unnamed ports
+func portsByName(ports []Port) map[string]string {
+	indexed := map[string]string{}
+	for _, port := range ports {
+		name := port.Name
+		if name == "" {
+			name = fmt.Sprint(port.Port)
+		}
+
+		value := fmt.Sprintf("%d:%s", port.Port, port.TargetPort)
+		if port.Protocol != "" {
+			value += "/" + port.Protocol
+		}
+
+		indexed[name] = value
+	}
+
+	return indexed
+}
+
+// podsByName returns the given pods indexed by name
+func podsByName(pods []Pod) map[string]Pod {
+	indexed := map[string]Pod{}
+	for _, pod := range pods {
+		indexed[pod.Name] = pod
+	}
+
+	return indexed
+}
+
+// backendToString returns the backend of a path in the "service:port" format, an empty
+// string for missing paths, and only the name for backends without port
+func backendToString(path *Path) string {
+	if path == nil || path.Service == nil {
+		return ""
+	}
+
+	if path.BackendPort == "" {
+		return path.Service.Name
+	}
+
+	return path.Service.Name + ":" + path.BackendPort
+}
+
+// unionKeys returns the sorted keys present in any of two maps indexed by string
+func unionKeys(from interface{}, to interface{}) []string {
+	keys := map[string]bool{}
+	for _, indexed := range []interface{}{from, to} {
+		for _, key := range reflect.ValueOf(indexed).MapKeys() {
+			keys[key.String()] = true
+		}
+	}
+
+	sorted := []string{}
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+
+	sort.Strings(sorted)
+
+	return sorted
+}
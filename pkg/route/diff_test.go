@@ -0,0 +1,97 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffDocuments(t *testing.T) {
+
+	newService := func(name string, pods ...string) *Service {
+		service := &Service{Name: name, Namespace: "default", Found: true}
+		for _, pod := range pods {
+			service.Pods = append(service.Pods, Pod{Name: pod, Ready: true})
+		}
+
+		return service
+	}
+
+	newPath := func(path string, service string, port string) Path {
+		return Path{Path: path, PathType: "Prefix", BackendPort: port, Service: newService(service)}
+	}
+
+	fromTimestamp := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	toTimestamp := time.Date(2021, 5, 1, 11, 0, 0, 0, time.UTC)
+
+	fooService := newService("foo", "foo-1", "foo-2")
+	fooService.Ports = []Port{{Name: "http", Port: 80, TargetPort: "8080", Protocol: "TCP"}}
+
+	fooChangedService := newService("foo", "foo-2", "foo-3")
+	fooChangedService.Ports = []Port{
+		{Name: "http", Port: 80, TargetPort: "9090", Protocol: "TCP"},
+		{Port: 443, TargetPort: "8443", Protocol: "TCP"},
+	}
+
+	from := NewSnapshot(
+		[]*Route{
+			{
+				Name:      "my-ingress",
+				Namespace: "default",
+				Hosts: []Host{
+					{Host: "foo.example.com", Paths: []Path{newPath("/", "foo", "http"), newPath("/api", "foo", "http")}},
+					{Host: "old.example.com", Paths: []Path{newPath("/", "foo", "http")}},
+				},
+			},
+			{Name: "removed-ingress", Namespace: "default"},
+		},
+		[]*Service{fooService, newService("bar")},
+		fromTimestamp,
+	)
+
+	to := NewSnapshot(
+		[]*Route{
+			{
+				Name:      "my-ingress",
+				Namespace: "default",
+				Hosts: []Host{
+					{Host: "foo.example.com", Paths: []Path{newPath("/", "foo", "http"), newPath("/api", "api", "http"), newPath("/v2", "foo", "http")}},
+					{Host: "", Paths: []Path{newPath("/", "foo", "http")}},
+				},
+				DefaultBackend: &Path{BackendPort: "80", Service: newService("default")},
+			},
+		},
+		[]*Service{fooChangedService, newService("baz")},
+		toTimestamp,
+	)
+
+	expected := &Diff{
+		APIVersion: DocumentAPIVersion,
+		Kind:       DiffKind,
+		From:       &fromTimestamp,
+		To:         &toTimestamp,
+		Changes: []Change{
+			{Type: ChangeAdded, Kind: ChangeKindHost, Object: "Ingress/default/my-ingress", Name: "*"},
+			{Type: ChangeModified, Kind: ChangeKindBackend, Object: "Ingress/default/my-ingress", Name: "foo.example.com/api (Prefix)", From: "foo:http", To: "api:http"},
+			{Type: ChangeAdded, Kind: ChangeKindPath, Object: "Ingress/default/my-ingress", Name: "foo.example.com/v2 (Prefix)", To: "foo:http"},
+			{Type: ChangeRemoved, Kind: ChangeKindHost, Object: "Ingress/default/my-ingress", Name: "old.example.com"},
+			{Type: ChangeAdded, Kind: ChangeKindBackend, Object: "Ingress/default/my-ingress", Name: "(default backend)", To: "default:80"},
+			{Type: ChangeRemoved, Kind: ChangeKindIngress, Object: "Ingress/default/removed-ingress"},
+			{Type: ChangeRemoved, Kind: ChangeKindService, Object: "Service/default/bar"},
+			{Type: ChangeAdded, Kind: ChangeKindService, Object: "Service/default/baz"},
+			{Type: ChangeAdded, Kind: ChangeKindPort, Object: "Service/default/foo", Name: "443", To: "443:8443/TCP"},
+			{Type: ChangeModified, Kind: ChangeKindPort, Object: "Service/default/foo", Name: "http", From: "80:8080/TCP", To: "80:9090/TCP"},
+			{Type: ChangeModified, Kind: ChangeKindPods, Object: "Service/default/foo", Added: []string{"foo-3"}, Removed: []string{"foo-1"}},
+		},
+	}
+
+	diff := DiffDocuments(from, to)
+
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Returned diff was incorrect, got: %+v, want: %+v", diff, expected)
+	}
+
+	if diff := DiffDocuments(from, from); len(diff.Changes) != 0 {
+		t.Errorf("Returned diff of the same document was not empty, got: %+v", diff.Changes)
+	}
+}
@@ -0,0 +1,156 @@
+package route
+
+import (
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// resolveServiceExposure returns how traffic from outside the cluster enters a service: its load
+// balancer addresses and source ranges, its external IPs and its node ports on the addresses of
+// each node. No exposure is returned for services without known addresses reachable from outside the cluster
+func (r *Resolver) resolveServiceExposure(service *v1.Service) (*Exposure, error) {
+
+	exposure := &Exposure{
+		ExternalIPs: service.Spec.ExternalIPs,
+	}
+
+	if service.Spec.Type == v1.ServiceTypeLoadBalancer {
+		exposure.LoadBalancer = loadBalancerAddresses(service.Status.LoadBalancer)
+		exposure.Pending = len(exposure.LoadBalancer) == 0
+		exposure.SourceRanges = service.Spec.LoadBalancerSourceRanges
+	}
+
+	nodePorts := false
+	for _, port := range service.Spec.Ports {
+		if port.NodePort != 0 {
+			nodePorts = true
+		}
+	}
+
+	var nodes []v1.Node
+	if nodePorts {
+		var err error
+		if nodes, err = r.listNodes(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, port := range service.Spec.Ports {
+		protocol := string(port.Protocol)
+
+		for _, address := range exposure.LoadBalancer {
+			exposure.Entrypoints = append(exposure.Entrypoints, Entrypoint{Source: EntrypointSourceLoadBalancer, Address: address, Port: port.Port, Protocol: protocol})
+		}
+
+		for _, address := range exposure.ExternalIPs {
+			exposure.Entrypoints = append(exposure.Entrypoints, Entrypoint{Source: EntrypointSourceExternalIP, Address: address, Port: port.Port, Protocol: protocol})
+		}
+
+		if port.NodePort == 0 {
+			continue
+		}
+
+		for _, node := range nodes {
+			for _, address := range node.Status.Addresses {
+				if address.Type != v1.NodeInternalIP && address.Type != v1.NodeExternalIP {
+					continue
+				}
+
+				exposure.Entrypoints = append(exposure.Entrypoints, Entrypoint{
+					Source:      EntrypointSourceNodePort,
+					Address:     address.Address,
+					Port:        port.NodePort,
+					Protocol:    protocol,
+					Node:        node.Name,
+					AddressType: string(address.Type),
+				})
+			}
+		}
+	}
+
+	// Node ports are not reachable through any known address when nodes can not be listed
+	if len(exposure.Entrypoints) == 0 && !exposure.Pending {
+		return nil, nil
+	}
+
+	return exposure, nil
+}
+
+// listNodes returns the nodes of the cluster, listed once per resolver. Users allowed to read
+// the objects of a namespace are usually not allowed to list nodes, so no nodes are returned then
+func (r *Resolver) listNodes() ([]v1.Node, error) {
+
+	if r.cache.nodesListed {
+		return r.cache.nodes, nil
+	}
+
+	nodes, err := r.Client.ListNodes()
+	if err != nil && !apierrors.IsForbidden(err) {
+		return nil, err
+	}
+
+	r.cache.nodesListed = true
+	if err == nil && nodes != nil {
+		r.cache.nodes = nodes.Items
+	}
+
+	return r.cache.nodes, nil
+}
+
+// ingressExposure returns the addresses of the load balancer of an ingress, reachable
+// on port 80 and, when the ingress terminates TLS, on port 443 unless the load balancer
+// reports its ports. No exposure is returned for ingresses without load balancer address
+func ingressExposure(ingress *networkingv1.Ingress) *Exposure {
+
+	addresses := loadBalancerAddresses(ingress.Status.LoadBalancer)
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	exposure := &Exposure{LoadBalancer: addresses}
+
+	for _, loadBalancer := range ingress.Status.LoadBalancer.Ingress {
+
+		address := loadBalancerAddress(loadBalancer)
+
+		ports := []v1.PortStatus{{Port: 80, Protocol: v1.ProtocolTCP}}
+		if len(ingress.Spec.TLS) > 0 {
+			ports = append(ports, v1.PortStatus{Port: 443, Protocol: v1.ProtocolTCP})
+		}
+
+		if len(loadBalancer.Ports) > 0 {
+			ports = loadBalancer.Ports
+		}
+
+		for _, port := range ports {
+			exposure.Entrypoints = append(exposure.Entrypoints, Entrypoint{Source: EntrypointSourceLoadBalancer, Address: address, Port: port.Port, Protocol: string(port.Protocol)})
+		}
+	}
+
+	return exposure
+}
+
+// loadBalancerAddresses returns the IPs or hostnames of the ingress points of a load balancer
+func loadBalancerAddresses(status v1.LoadBalancerStatus) []string {
+	addresses := []string{}
+
+	for _, loadBalancer := range status.Ingress {
+		addresses = append(addresses, loadBalancerAddress(loadBalancer))
+	}
+
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	return addresses
+}
+
+// loadBalancerAddress returns the IP of an ingress point of a load balancer, or its hostname
+func loadBalancerAddress(loadBalancer v1.LoadBalancerIngress) string {
+	if loadBalancer.IP != "" {
+		return loadBalancer.IP
+	}
+
+	return loadBalancer.Hostname
+}
@@ -0,0 +1,188 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolverResolveServiceExposure(t *testing.T) {
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{
+				{Type: v1.NodeHostName, Address: "node-1"},
+				{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+				{Type: v1.NodeExternalIP, Address: "198.51.100.1"},
+			},
+		},
+	}
+
+	newService := func(serviceType v1.ServiceType, nodePort int32) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service", Namespace: "default"},
+			Spec: v1.ServiceSpec{
+				Type:  serviceType,
+				Ports: []v1.ServicePort{{Port: 80, NodePort: nodePort, Protocol: v1.ProtocolTCP}},
+			},
+		}
+	}
+
+	loadBalancer := newService(v1.ServiceTypeLoadBalancer, 30080)
+	loadBalancer.Spec.LoadBalancerSourceRanges = []string{"192.0.2.0/24"}
+	loadBalancer.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{IP: "203.0.113.10"}, {Hostname: "lb.example.com"}}
+
+	pending := newService(v1.ServiceTypeLoadBalancer, 0)
+
+	externalIPs := newService(v1.ServiceTypeClusterIP, 0)
+	externalIPs.Spec.ExternalIPs = []string{"198.51.100.7"}
+
+	tests := []struct {
+		name     string
+		objects  []runtime.Object
+		expected *Exposure
+	}{
+		{
+			"load balancer",
+			[]runtime.Object{loadBalancer, node},
+			&Exposure{
+				LoadBalancer: []string{"203.0.113.10", "lb.example.com"},
+				SourceRanges: []string{"192.0.2.0/24"},
+				Entrypoints: []Entrypoint{
+					{Source: EntrypointSourceLoadBalancer, Address: "203.0.113.10", Port: 80, Protocol: "TCP"},
+					{Source: EntrypointSourceLoadBalancer, Address: "lb.example.com", Port: 80, Protocol: "TCP"},
+					{Source: EntrypointSourceNodePort, Address: "10.0.0.1", Port: 30080, Protocol: "TCP", Node: "node-1", AddressType: "InternalIP"},
+					{Source: EntrypointSourceNodePort, Address: "198.51.100.1", Port: 30080, Protocol: "TCP", Node: "node-1", AddressType: "ExternalIP"},
+				},
+			},
+		},
+		{
+			"pending load balancer",
+			[]runtime.Object{pending, node},
+			&Exposure{Pending: true},
+		},
+		{
+			"node port",
+			[]runtime.Object{newService(v1.ServiceTypeNodePort, 30080), node},
+			&Exposure{
+				Entrypoints: []Entrypoint{
+					{Source: EntrypointSourceNodePort, Address: "10.0.0.1", Port: 30080, Protocol: "TCP", Node: "node-1", AddressType: "InternalIP"},
+					{Source: EntrypointSourceNodePort, Address: "198.51.100.1", Port: 30080, Protocol: "TCP", Node: "node-1", AddressType: "ExternalIP"},
+				},
+			},
+		},
+		{
+			"node port without nodes",
+			[]runtime.Object{newService(v1.ServiceTypeNodePort, 30080)},
+			nil,
+		},
+		{
+			"external IPs",
+			[]runtime.Object{externalIPs, node},
+			&Exposure{
+				ExternalIPs: []string{"198.51.100.7"},
+				Entrypoints: []Entrypoint{{Source: EntrypointSourceExternalIP, Address: "198.51.100.7", Port: 80, Protocol: "TCP"}},
+			},
+		},
+		{
+			"cluster IP",
+			[]runtime.Object{newService(v1.ServiceTypeClusterIP, 0), node},
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+
+		resolver := NewResolver(NewFakeClient(nil, test.objects...), "default")
+
+		service, err := resolver.ResolveService("service")
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(service.Exposure, test.expected) {
+			t.Errorf("Returned exposure for %s was incorrect, got: %+v, want: %+v", test.name, service.Exposure, test.expected)
+		}
+	}
+}
+
+func TestIngressExposure(t *testing.T) {
+
+	ingress := &networkingv1.Ingress{
+		Spec: networkingv1.IngressSpec{TLS: []networkingv1.IngressTLS{{Hosts: []string{"foo.com"}}}},
+		Status: networkingv1.IngressStatus{
+			LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{
+					{IP: "203.0.113.10"},
+					{Hostname: "lb.example.com", Ports: []v1.PortStatus{{Port: 8443, Protocol: v1.ProtocolTCP}}},
+				},
+			},
+		},
+	}
+
+	expected := &Exposure{
+		LoadBalancer: []string{"203.0.113.10", "lb.example.com"},
+		Entrypoints: []Entrypoint{
+			{Source: EntrypointSourceLoadBalancer, Address: "203.0.113.10", Port: 80, Protocol: "TCP"},
+			{Source: EntrypointSourceLoadBalancer, Address: "203.0.113.10", Port: 443, Protocol: "TCP"},
+			{Source: EntrypointSourceLoadBalancer, Address: "lb.example.com", Port: 8443, Protocol: "TCP"},
+		},
+	}
+
+	if exposure := ingressExposure(ingress); !reflect.DeepEqual(exposure, expected) {
+		t.Errorf("Returned exposure was incorrect, got: %+v, want: %+v", exposure, expected)
+	}
+
+	if exposure := ingressExposure(&networkingv1.Ingress{}); exposure != nil {
+		t.Errorf("Returned exposure was incorrect, got: %+v, want: nil", exposure)
+	}
+}
+
+func TestResolverListNodesCached(t *testing.T) {
+
+	newService := func(name string, namespace string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: v1.ServiceSpec{
+				Type:  v1.ServiceTypeNodePort,
+				Ports: []v1.ServicePort{{Port: 80, NodePort: 30080, Protocol: v1.ProtocolTCP}},
+			},
+		}
+	}
+
+	client := NewFakeClient(nil,
+		newService("foo", "default"),
+		newService("bar", "default"),
+		newService("baz", "other"),
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	)
+
+	resolver := NewResolver(client, "default")
+
+	for _, name := range []string{"foo", "bar"} {
+		if _, err := resolver.ResolveService(name); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if _, err := resolver.forNamespace("other").ResolveService("baz"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lists := 0
+	for _, action := range client.Clientset.(*fake.Clientset).Actions() {
+		if action.Matches("list", "nodes") {
+			lists++
+		}
+	}
+
+	if lists != 1 {
+		t.Errorf("Returned node list count was incorrect, got: %d, want: %d", lists, 1)
+	}
+}
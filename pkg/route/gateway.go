@@ -0,0 +1,449 @@
+package route
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Gateway API group and object kinds
+const (
+	GatewayAPIGroup = "gateway.networking.k8s.io"
+	KindGateway     = "Gateway"
+	KindHTTPRoute   = "HTTPRoute"
+)
+
+// Gateway API objects are read through the dynamic client, so only the fields
+// used by the resolver are declared here instead of importing the typed API
+
+type gatewayObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		GatewayClassName string            `json:"gatewayClassName"`
+		Listeners        []gatewayListener `json:"listeners"`
+	} `json:"spec"`
+}
+
+type gatewayListener struct {
+	Name          string  `json:"name"`
+	Hostname      *string `json:"hostname,omitempty"`
+	Port          int32   `json:"port"`
+	Protocol      string  `json:"protocol"`
+	AllowedRoutes *struct {
+		Namespaces *struct {
+			From     *string               `json:"from,omitempty"`
+			Selector *metav1.LabelSelector `json:"selector,omitempty"`
+		} `json:"namespaces,omitempty"`
+		Kinds []struct {
+			Group *string `json:"group,omitempty"`
+			Kind  string  `json:"kind"`
+		} `json:"kinds,omitempty"`
+	} `json:"allowedRoutes,omitempty"`
+}
+
+type httpRouteObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		ParentRefs []struct {
+			Group       *string `json:"group,omitempty"`
+			Kind        *string `json:"kind,omitempty"`
+			Namespace   *string `json:"namespace,omitempty"`
+			Name        string  `json:"name"`
+			SectionName *string `json:"sectionName,omitempty"`
+			Port        *int32  `json:"port,omitempty"`
+		} `json:"parentRefs,omitempty"`
+		Hostnames []string        `json:"hostnames,omitempty"`
+		Rules     []httpRouteRule `json:"rules,omitempty"`
+	} `json:"spec"`
+}
+
+type httpRouteRule struct {
+	Matches     []httpRouteMatch `json:"matches,omitempty"`
+	BackendRefs []struct {
+		Group     *string `json:"group,omitempty"`
+		Kind      *string `json:"kind,omitempty"`
+		Name      string  `json:"name"`
+		Namespace *string `json:"namespace,omitempty"`
+		Port      *int32  `json:"port,omitempty"`
+		Weight    *int32  `json:"weight,omitempty"`
+	} `json:"backendRefs,omitempty"`
+}
+
+type httpRouteMatch struct {
+	Path *struct {
+		Type  *string `json:"type,omitempty"`
+		Value *string `json:"value,omitempty"`
+	} `json:"path,omitempty"`
+	Headers     []httpValueMatch `json:"headers,omitempty"`
+	QueryParams []httpValueMatch `json:"queryParams,omitempty"`
+	Method      *string          `json:"method,omitempty"`
+}
+
+type httpValueMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Name  string  `json:"name"`
+	Value string  `json:"value"`
+}
+
+// ResolveHTTPRoute returns the route graph of the HTTPRoute that matches a given name
+func (r *Resolver) ResolveHTTPRoute(name string) (*Route, error) {
+
+	object, err := r.Client.GetCustomResourceByName("httproutes", GatewayAPIGroupVersions, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if object == nil {
+		return nil, fmt.Errorf("httproute %q not found", name)
+	}
+
+	httpRoute := &httpRouteObject{}
+	if err := fromUnstructured(object, httpRoute); err != nil {
+		return nil, err
+	}
+
+	return r.newHTTPRoute(httpRoute)
+}
+
+// ResolveGateway returns the listeners of the gateway that matches a given name, along with
+// the route graphs of the HTTPRoutes of any namespace attached to each of them
+func (r *Resolver) ResolveGateway(name string) (*Gateway, error) {
+
+	object, err := r.Client.GetCustomResourceByName("gateways", GatewayAPIGroupVersions, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if object == nil {
+		return nil, fmt.Errorf("gateway %q not found", name)
+	}
+
+	gateway := &gatewayObject{}
+	if err := fromUnstructured(object, gateway); err != nil {
+		return nil, err
+	}
+
+	objects, err := r.Client.Namespaced("").ListCustomResources("httproutes", GatewayAPIGroupVersions, "")
+	if err != nil {
+		return nil, err
+	}
+
+	httpRoutes := []*httpRouteObject{}
+
+	for index := range objects.Items {
+		httpRoute := &httpRouteObject{}
+		if err := fromUnstructured(&objects.Items[index], httpRoute); err != nil {
+			return nil, err
+		}
+
+		httpRoutes = append(httpRoutes, httpRoute)
+	}
+
+	sort.SliceStable(httpRoutes, func(i, j int) bool {
+		return objectKey(httpRoutes[i].ObjectMeta) < objectKey(httpRoutes[j].ObjectMeta)
+	})
+
+	route := &Gateway{
+		Name:      gateway.Name,
+		Namespace: r.Namespace,
+		ClassName: gateway.Spec.GatewayClassName,
+		Listeners: []Listener{},
+	}
+
+	// HTTPRoutes attached to several listeners and namespace labels are only read once
+	resolved := map[string]*Route{}
+	namespaceLabels := map[string]apilabels.Set{}
+
+	for _, gatewayListener := range gateway.Spec.Listeners {
+
+		listener := Listener{
+			Name:     gatewayListener.Name,
+			Port:     gatewayListener.Port,
+			Protocol: gatewayListener.Protocol,
+			Routes:   []*Route{},
+		}
+
+		if gatewayListener.Hostname != nil {
+			listener.Hostname = *gatewayListener.Hostname
+		}
+
+		for _, httpRoute := range httpRoutes {
+
+			attached, err := r.isAttached(httpRoute, gateway, gatewayListener, namespaceLabels)
+			if err != nil {
+				return nil, err
+			}
+
+			if !attached {
+				continue
+			}
+
+			key := objectKey(httpRoute.ObjectMeta)
+
+			if resolved[key] == nil {
+				resolved[key], err = r.forNamespace(httpRoute.Namespace).newHTTPRoute(httpRoute)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			listener.Routes = append(listener.Routes, resolved[key])
+		}
+
+		route.Listeners = append(route.Listeners, listener)
+	}
+
+	return route, nil
+}
+
+func (r *Resolver) newHTTPRoute(httpRoute *httpRouteObject) (*Route, error) {
+
+	route := &Route{
+		Kind:      KindHTTPRoute,
+		Name:      httpRoute.Name,
+		Namespace: r.Namespace,
+		Hosts:     []Host{},
+	}
+
+	// An HTTPRoute without hostnames matches every hostname of the listeners it is attached to
+	hostnames := httpRoute.Spec.Hostnames
+	if len(hostnames) == 0 {
+		hostnames = []string{""}
+	}
+
+	// Every host shares the same paths, so the backends are only resolved once
+	paths := []Path{}
+
+	for _, rule := range httpRoute.Spec.Rules {
+
+		// A rule without matches matches every request
+		matches := rule.Matches
+		if len(matches) == 0 {
+			matches = []httpRouteMatch{{}}
+		}
+
+		backends := []Path{}
+
+		for _, backendRef := range rule.BackendRefs {
+
+			namespace := r.Namespace
+			if backendRef.Namespace != nil {
+				namespace = *backendRef.Namespace
+			}
+
+			backend := Path{
+				Weight: backendRef.Weight,
+			}
+
+			// The Gateway API weight defaults to 1
+			if backend.Weight == nil {
+				weight := int32(1)
+				backend.Weight = &weight
+			}
+
+			if backendRef.Port != nil {
+				backend.BackendPort = strconv.FormatInt(int64(*backendRef.Port), 10)
+			}
+
+			// Only core services are followed, other backend kinds are reported as not found
+			kind := "Service"
+			if backendRef.Kind != nil {
+				kind = *backendRef.Kind
+			}
+
+			if kind == "Service" && (backendRef.Group == nil || *backendRef.Group == "") {
+				service, err := r.forNamespace(namespace).resolveBackendService(backendRef.Name)
+				if err != nil {
+					return nil, err
+				}
+
+				backend.Service = service
+			} else {
+				backend.Service = &Service{Name: backendRef.Name, Namespace: namespace, Type: kind}
+			}
+
+			backends = append(backends, backend)
+		}
+
+		for _, match := range matches {
+			for _, backend := range backends {
+				path := backend
+				setHTTPRouteMatch(&path, match)
+
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	for _, hostname := range hostnames {
+		route.Hosts = append(route.Hosts, Host{
+			Host:  hostname,
+			Paths: paths,
+		})
+	}
+
+	return route, nil
+}
+
+// setHTTPRouteMatch sets the request conditions of an HTTPRoute match on a path.
+// Headers and query parameters are written as name=value for exact matches
+// and as name~=value for regular expression matches
+func setHTTPRouteMatch(path *Path, match httpRouteMatch) {
+	// A match without path matches every path
+	path.Path = "/"
+	path.PathType = "Prefix"
+
+	if match.Path != nil {
+		if match.Path.Value != nil {
+			path.Path = *match.Path.Value
+		}
+
+		if match.Path.Type != nil {
+			path.PathType = httpRoutePathType(*match.Path.Type)
+		}
+	}
+
+	for _, header := range match.Headers {
+		path.Headers = append(path.Headers, valueMatchToString(header))
+	}
+
+	for _, queryParam := range match.QueryParams {
+		path.QueryParams = append(path.QueryParams, valueMatchToString(queryParam))
+	}
+
+	if match.Method != nil {
+		path.Method = *match.Method
+	}
+}
+
+// httpRoutePathType returns the ingress path type that matches an HTTPRoute path type
+func httpRoutePathType(pathType string) string {
+	switch pathType {
+	case "PathPrefix":
+		return "Prefix"
+	case "Exact":
+		return "Exact"
+	}
+
+	return pathType
+}
+
+func valueMatchToString(match httpValueMatch) string {
+	if match.Type != nil && *match.Type == "RegularExpression" {
+		return match.Name + "~=" + match.Value
+	}
+
+	return match.Name + "=" + match.Value
+}
+
+// isAttached returns whether an HTTPRoute is attached to a gateway listener. The HTTPRoute must
+// reference the gateway, and the listener, when a section name or port is set, and the listener
+// must allow HTTPRoutes from the HTTPRoute namespace and share at least one hostname with it
+func (r *Resolver) isAttached(httpRoute *httpRouteObject, gateway *gatewayObject, listener gatewayListener, namespaceLabels map[string]apilabels.Set) (bool, error) {
+
+	referenced := false
+
+	for _, parentRef := range httpRoute.Spec.ParentRefs {
+		if parentRef.Group != nil && *parentRef.Group != GatewayAPIGroup {
+			continue
+		}
+
+		if parentRef.Kind != nil && *parentRef.Kind != KindGateway {
+			continue
+		}
+
+		namespace := httpRoute.Namespace
+		if parentRef.Namespace != nil {
+			namespace = *parentRef.Namespace
+		}
+
+		if parentRef.Name != gateway.Name || namespace != gateway.Namespace {
+			continue
+		}
+
+		if parentRef.SectionName != nil && *parentRef.SectionName != listener.Name {
+			continue
+		}
+
+		if parentRef.Port != nil && *parentRef.Port != listener.Port {
+			continue
+		}
+
+		referenced = true
+		break
+	}
+
+	if !referenced || !listenerAllowsHTTPRoutes(listener) || !hostnamesIntersect(listener.Hostname, httpRoute.Spec.Hostnames) {
+		return false, nil
+	}
+
+	from := "Same"
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil && listener.AllowedRoutes.Namespaces.From != nil {
+		from = *listener.AllowedRoutes.Namespaces.From
+	}
+
+	switch from {
+	case "All":
+		return true, nil
+
+	case "Selector":
+		selector, err := metav1.LabelSelectorAsSelector(listener.AllowedRoutes.Namespaces.Selector)
+		if err != nil {
+			return false, err
+		}
+
+		if _, ok := namespaceLabels[httpRoute.Namespace]; !ok {
+			namespace, err := r.Client.GetNamespaceByName(httpRoute.Namespace)
+			if err != nil {
+				return false, err
+			}
+
+			namespaceLabels[httpRoute.Namespace] = apilabels.Set(namespace.Labels)
+		}
+
+		return selector.Matches(namespaceLabels[httpRoute.Namespace]), nil
+	}
+
+	return httpRoute.Namespace == gateway.Namespace, nil
+}
+
+// listenerAllowsHTTPRoutes returns whether a listener accepts HTTPRoutes, either because
+// they are listed in its allowed kinds or, when none is listed, because of its protocol
+func listenerAllowsHTTPRoutes(listener gatewayListener) bool {
+	if listener.AllowedRoutes == nil || len(listener.AllowedRoutes.Kinds) == 0 {
+		return listener.Protocol == "HTTP" || listener.Protocol == "HTTPS"
+	}
+
+	for _, kind := range listener.AllowedRoutes.Kinds {
+		if kind.Kind == KindHTTPRoute && (kind.Group == nil || *kind.Group == GatewayAPIGroup) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hostnamesIntersect returns whether a listener hostname matches any of the hostnames
+// of an HTTPRoute. Both of them may be wildcards, and an empty hostname matches any
+func hostnamesIntersect(listenerHostname *string, hostnames []string) bool {
+	if listenerHostname == nil || *listenerHostname == "" || len(hostnames) == 0 {
+		return true
+	}
+
+	for _, hostname := range hostnames {
+		if MatchHost(*listenerHostname, hostname) != HostMatchNone || MatchHost(hostname, *listenerHostname) != HostMatchNone {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fromUnstructured(object *unstructured.Unstructured, into interface{}) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(object.UnstructuredContent(), into)
+}
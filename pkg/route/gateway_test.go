@@ -0,0 +1,281 @@
+package route
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/yaml"
+)
+
+// NewFakeCustomResourceClient returns a Client backed by a fake clientset with the given
+// typed objects and a fake dynamic client that serves the given custom resources
+func NewFakeCustomResourceClient(t *testing.T, resources []*metav1.APIResourceList, objects []runtime.Object, manifests ...string) *Client {
+	gvrToListKind := map[schema.GroupVersionResource]string{}
+	kindToResource := map[schema.GroupVersionKind]schema.GroupVersionResource{}
+
+	for _, resourceList := range resources {
+		groupVersion, _ := schema.ParseGroupVersion(resourceList.GroupVersion)
+
+		for _, resource := range resourceList.APIResources {
+			gvrToListKind[groupVersion.WithResource(resource.Name)] = resource.Kind + "List"
+			kindToResource[groupVersion.WithKind(resource.Kind)] = groupVersion.WithResource(resource.Name)
+		}
+	}
+
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), gvrToListKind)
+
+	// Objects are added with the resource names served by discovery, since the
+	// fake tracker guesses wrong plurals for some kinds, e.g. gatewaies
+	for _, manifest := range manifests {
+		object := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifest), &object.Object); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		gvr := kindToResource[object.GroupVersionKind()]
+		if _, err := dynamicClient.Resource(gvr).Namespace(object.GetNamespace()).Create(context.TODO(), object, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	client := NewFakeClient(resources, objects...)
+	client.Dynamic = dynamicClient
+
+	return client
+}
+
+// NewFakeGatewayResolver returns a Resolver for a gateway with an HTTP listener that allows
+// routes from its namespace, an HTTPS listener that allows routes from labeled namespaces
+// and a TCP listener, along with HTTPRoutes that are attached to them or not
+func NewFakeGatewayResolver(t *testing.T) *Resolver {
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: GatewayAPIV1,
+			APIResources: []metav1.APIResource{
+				{Name: "gateways", Kind: "Gateway", Namespaced: true},
+				{Name: "httproutes", Kind: "HTTPRoute", Namespaced: true},
+			},
+		},
+	}
+
+	objects := []runtime.Object{
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team", Labels: map[string]string{"gateway-access": "true"}}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-foo", Namespace: "default"},
+			Spec: v1.ServiceSpec{
+				Type:  v1.ServiceTypeClusterIP,
+				Ports: []v1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(8080), Protocol: v1.ProtocolTCP}},
+			},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-bar", Namespace: "team"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeExternalName, ExternalName: "bar.example.com"},
+		},
+	}
+
+	client := NewFakeCustomResourceClient(t, resources, objects, `
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: gateway
+  namespace: default
+spec:
+  gatewayClassName: example
+  listeners:
+  - name: http
+    port: 80
+    protocol: HTTP
+  - name: https
+    hostname: "*.foo.com"
+    port: 443
+    protocol: HTTPS
+    allowedRoutes:
+      namespaces:
+        from: Selector
+        selector:
+          matchLabels:
+            gateway-access: "true"
+  - name: tcp
+    port: 9000
+    protocol: TCP
+`, `
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: route-foo
+  namespace: default
+spec:
+  parentRefs:
+  - name: gateway
+  hostnames:
+  - foo.com
+  rules:
+  - matches:
+    - path:
+        type: PathPrefix
+        value: /api
+      headers:
+      - name: x-canary
+        value: "true"
+      method: GET
+    - path:
+        type: Exact
+        value: /health
+      queryParams:
+      - type: RegularExpression
+        name: verbose
+        value: "[01]"
+    backendRefs:
+    - name: service-foo
+      port: 80
+      weight: 90
+    - name: service-missing
+      port: 80
+      weight: 10
+`, `
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: route-bar
+  namespace: team
+spec:
+  parentRefs:
+  - name: gateway
+    namespace: default
+    sectionName: https
+  hostnames:
+  - bar.foo.com
+  rules:
+  - backendRefs:
+    - name: service-bar
+      port: 80
+`, `
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: route-other
+  namespace: other
+spec:
+  parentRefs:
+  - name: gateway
+    namespace: default
+  rules:
+  - backendRefs:
+    - name: service-other
+      port: 80
+`, `
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: route-elsewhere
+  namespace: default
+spec:
+  parentRefs:
+  - name: other-gateway
+  rules:
+  - backendRefs:
+    - name: service-foo
+      port: 80
+`)
+
+	return NewResolver(client, "default")
+}
+
+func TestResolverResolveHTTPRoute(t *testing.T) {
+
+	weight90 := int32(90)
+	weight10 := int32(10)
+
+	serviceFoo := &Service{
+		Name:      "service-foo",
+		Namespace: "default",
+		Found:     true,
+		Type:      "ClusterIP",
+		Ports:     []Port{{Port: 80, TargetPort: "8080", Protocol: "TCP"}},
+	}
+
+	serviceMissing := &Service{
+		Name:      "service-missing",
+		Namespace: "default",
+	}
+
+	expected := &Route{
+		Kind:      "HTTPRoute",
+		Name:      "route-foo",
+		Namespace: "default",
+		Hosts: []Host{
+			{
+				Host: "foo.com",
+				Paths: []Path{
+					{Path: "/api", PathType: "Prefix", Headers: []string{"x-canary=true"}, Method: "GET", BackendPort: "80", Weight: &weight90, Service: serviceFoo},
+					{Path: "/api", PathType: "Prefix", Headers: []string{"x-canary=true"}, Method: "GET", BackendPort: "80", Weight: &weight10, Service: serviceMissing},
+					{Path: "/health", PathType: "Exact", QueryParams: []string{"verbose~=[01]"}, BackendPort: "80", Weight: &weight90, Service: serviceFoo},
+					{Path: "/health", PathType: "Exact", QueryParams: []string{"verbose~=[01]"}, BackendPort: "80", Weight: &weight10, Service: serviceMissing},
+				},
+			},
+		},
+	}
+
+	route, err := NewFakeGatewayResolver(t).ResolveHTTPRoute("route-foo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(route, expected) {
+		t.Errorf("Returned route was incorrect,\ngot:\n%+v\nwant:\n%+v", route, expected)
+	}
+}
+
+func TestResolverResolveGateway(t *testing.T) {
+
+	expected := map[string][]string{
+		"http":  {"default/route-foo"},
+		"https": {"team/route-bar"},
+		"tcp":   {},
+	}
+
+	gateway, err := NewFakeGatewayResolver(t).ResolveGateway("gateway")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if gateway.ClassName != "example" || len(gateway.Listeners) != len(expected) {
+		t.Fatalf("Returned gateway was incorrect, got: %+v", gateway)
+	}
+
+	for _, listener := range gateway.Listeners {
+		routes := []string{}
+		for _, route := range listener.Routes {
+			routes = append(routes, route.Namespace+"/"+route.Name)
+		}
+
+		if !reflect.DeepEqual(routes, expected[listener.Name]) {
+			t.Errorf("Returned routes of listener %s were incorrect, got: %v, want: %v", listener.Name, routes, expected[listener.Name])
+		}
+	}
+
+	// Backends of HTTPRoutes in other namespaces are resolved in their namespace
+	service := gateway.Listeners[1].Routes[0].Hosts[0].Paths[0].Service
+	if !service.Found || service.Namespace != "team" || service.Hostname != "bar.example.com" {
+		t.Errorf("Returned backend service was incorrect, got: %+v", service)
+	}
+}
+
+func TestResolverResolveGatewayNotServed(t *testing.T) {
+
+	client := NewFakeClient(nil)
+	client.Dynamic = fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+
+	if _, err := NewResolver(client, "default").ResolveGateway("gateway"); err == nil {
+		t.Errorf("Expected an error when the Gateway API is not served")
+	}
+}
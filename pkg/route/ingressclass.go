@@ -0,0 +1,248 @@
+package route
+
+import (
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Ingress class finding types
+const (
+	FindingIngressClassNotFound       FindingType = "IngressClassNotFound"
+	FindingIngressClassForbidden      FindingType = "IngressClassForbidden"
+	FindingIngressControllerNotFound  FindingType = "IngressControllerNotFound"
+	FindingIngressControllerForbidden FindingType = "IngressControllerForbidden"
+)
+
+// AnnotationIngressClass is the legacy annotation that sets the class of an ingress
+const AnnotationIngressClass = "kubernetes.io/ingress.class"
+
+// AnnotationDefaultIngressClass is the annotation that marks the default ingress class of a cluster
+const AnnotationDefaultIngressClass = "ingressclass.kubernetes.io/is-default-class"
+
+// ingressControllerPodLabels lists the labels of the pods of well known ingress controllers,
+// per controller name. Controllers without pods in the cluster, such as the ones of cloud
+// load balancers, are not listed
+var ingressControllerPodLabels = map[string]map[string]string{
+	"k8s.io/ingress-nginx":                   {"app.kubernetes.io/name": "ingress-nginx"},
+	"nginx.org/ingress-controller":           {"app.kubernetes.io/name": "nginx-ingress"},
+	"traefik.io/ingress-controller":          {"app.kubernetes.io/name": "traefik"},
+	"haproxy.org/ingress-controller/haproxy": {"app.kubernetes.io/name": "kubernetes-ingress"},
+	"projectcontour.io/ingress-controller":   {"app.kubernetes.io/name": "contour"},
+	"ingress-controllers.konghq.com/kong":    {"app.kubernetes.io/name": "kong"},
+	"ingress.k8s.aws/alb":                    {"app.kubernetes.io/name": "aws-load-balancer-controller"},
+	"istio.io/ingress-controller":            {"app": "istiod"},
+}
+
+// resolveIngressClass returns the ingress class of an ingress: the class set in its spec, the one
+// of the legacy annotation or the default class of the cluster, along with the controller serving
+// it and the findings of the class. No class is returned when the ingress sets no class and the
+// cluster has no ingress classes, as ingress controllers then serve ingresses without class.
+// Users allowed to read the objects of a namespace are usually not allowed to list ingress
+// classes or the pods of other namespaces, so the class or its controller is left unresolved then
+func (r *Resolver) resolveIngressClass(ingress *networkingv1.Ingress) (*IngressClass, []Finding, error) {
+
+	ingressClass := &IngressClass{}
+
+	switch {
+	case ingress.Spec.IngressClassName != nil:
+		ingressClass.Name = *ingress.Spec.IngressClassName
+		ingressClass.Source = IngressClassSourceSpec
+
+	case IngressClassName(ingress) != "":
+		ingressClass.Name = IngressClassName(ingress)
+		ingressClass.Source = IngressClassSourceAnnotation
+	}
+
+	ingressClasses, err := r.listIngressClasses()
+	if apierrors.IsForbidden(err) {
+		ingressClass.Forbidden = true
+
+		message := "IngressClasses can not be listed, so the controller of the class is not resolved"
+		if ingressClass.Name == "" {
+			message = "IngressClasses can not be listed, so the default class of the ingress is not resolved"
+		}
+
+		return ingressClass, []Finding{{
+			Severity: SeverityWarning,
+			Type:     FindingIngressClassForbidden,
+			Message:  message,
+		}}, nil
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := []networkingv1.IngressClass{}
+	if ingressClasses != nil {
+		items = ingressClasses.Items
+	}
+
+	if ingressClass.Name == "" {
+		if ingressClass.Name = findDefaultIngressClassName(items); ingressClass.Name != "" {
+			ingressClass.Source = IngressClassSourceDefault
+		}
+
+		if ingressClass.Name == "" && len(items) == 0 {
+			return nil, nil, nil
+		}
+
+		if ingressClass.Name == "" {
+			return ingressClass, []Finding{{
+				Severity: SeverityWarning,
+				Type:     FindingIngressClassNotFound,
+				Message:  "ingress sets no class and the cluster has no default IngressClass, so it may not be served by any controller",
+			}}, nil
+		}
+	}
+
+	var class *networkingv1.IngressClass
+	for index := range items {
+		if items[index].Name == ingressClass.Name {
+			class = &items[index]
+		}
+	}
+
+	if class == nil {
+		return ingressClass, []Finding{{
+			Severity: SeverityWarning,
+			Type:     FindingIngressClassNotFound,
+			Message:  "IngressClass " + ingressClass.Name + " not found",
+		}}, nil
+	}
+
+	ingressClass.Found = true
+	ingressClass.Default = class.Annotations[AnnotationDefaultIngressClass] == "true"
+	ingressClass.Controller = class.Spec.Controller
+	ingressClass.Parameters = IngressClassParametersToString(class.Spec.Parameters)
+
+	labels, ok := ingressControllerPodLabels[class.Spec.Controller]
+	if !ok {
+		return ingressClass, nil, nil
+	}
+
+	// Controllers usually run in a namespace of their own
+	pods, err := r.listControllerPods(class.Spec.Controller, labels)
+	if apierrors.IsForbidden(err) {
+		return ingressClass, []Finding{{
+			Severity: SeverityWarning,
+			Type:     FindingIngressControllerForbidden,
+			Message:  "pods can not be listed across namespaces, so whether the controller " + class.Spec.Controller + " is running is unknown",
+		}}, nil
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if pods != nil {
+		for _, pod := range pods.Items {
+			if IsPodReady(&pod) {
+				return ingressClass, nil, nil
+			}
+		}
+	}
+
+	return ingressClass, []Finding{{
+		Severity: SeverityWarning,
+		Type:     FindingIngressControllerNotFound,
+		Message:  "no running pods found for the controller " + class.Spec.Controller + " of IngressClass " + class.Name,
+	}}, nil
+}
+
+// IngressClassParametersToString returns the parameters of an ingress class in the
+// "apiGroup/kind/name" format, along with their namespace for namespace scoped ones
+func IngressClassParametersToString(parameters *networkingv1.IngressClassParametersReference) string {
+	if parameters == nil {
+		return ""
+	}
+
+	reference := parameters.Kind + "/" + parameters.Name
+	if parameters.APIGroup != nil && *parameters.APIGroup != "" {
+		reference = *parameters.APIGroup + "/" + reference
+	}
+
+	if parameters.Namespace != nil && *parameters.Namespace != "" {
+		reference += " (namespace " + *parameters.Namespace + ")"
+	}
+
+	return reference
+}
+
+// listIngressClasses returns the ingress classes of the cluster, listed once per resolver
+func (r *Resolver) listIngressClasses() (*networkingv1.IngressClassList, error) {
+
+	if r.cache.ingressClassesListed {
+		return r.cache.ingressClasses, r.cache.ingressClassesErr
+	}
+
+	ingressClasses, err := r.Client.ListIngressClasses()
+	if err != nil && !apierrors.IsForbidden(err) {
+		return nil, err
+	}
+
+	r.cache.ingressClassesListed = true
+	r.cache.ingressClasses = ingressClasses
+	r.cache.ingressClassesErr = err
+
+	return ingressClasses, err
+}
+
+// listControllerPods returns the pods of an ingress controller across namespaces, listed once per resolver
+func (r *Resolver) listControllerPods(controller string, labels map[string]string) (*v1.PodList, error) {
+
+	if cached, ok := r.cache.controllerPods[controller]; ok {
+		return cached.pods, cached.err
+	}
+
+	pods, err := r.Client.Namespaced("").GetPodsByLabels(labels)
+	if err != nil && !apierrors.IsForbidden(err) {
+		return nil, err
+	}
+
+	if r.cache.controllerPods == nil {
+		r.cache.controllerPods = map[string]cachedPodList{}
+	}
+
+	r.cache.controllerPods[controller] = cachedPodList{pods: pods, err: err}
+
+	return pods, err
+}
+
+// defaultIngressClassName returns the name of the default ingress class of the cluster, if any.
+// No class is returned when the user is not allowed to list ingress classes
+func (r *Resolver) defaultIngressClassName() (string, error) {
+
+	ingressClasses, err := r.listIngressClasses()
+	if apierrors.IsForbidden(err) {
+		return "", nil
+	}
+
+	if err != nil || ingressClasses == nil {
+		return "", err
+	}
+
+	return findDefaultIngressClassName(ingressClasses.Items), nil
+}
+
+// findDefaultIngressClassName returns the name of the ingress class marked as the default one, if any
+func findDefaultIngressClassName(ingressClasses []networkingv1.IngressClass) string {
+	for _, ingressClass := range ingressClasses {
+		if ingressClass.Annotations[AnnotationDefaultIngressClass] == "true" {
+			return ingressClass.Name
+		}
+	}
+
+	return ""
+}
+
+// effectiveIngressClassName returns the class of an ingress set in its spec or legacy
+// annotation or, when neither is set, the given default class of the cluster
+func effectiveIngressClassName(ingress *networkingv1.Ingress, defaultClass string) string {
+	if class := IngressClassName(ingress); class != "" {
+		return class
+	}
+
+	return defaultClass
+}
@@ -0,0 +1,287 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newIngressClass returns an ingress class served by the given controller
+func newIngressClass(name string, controller string, isDefault bool) *networkingv1.IngressClass {
+	ingressClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       networkingv1.IngressClassSpec{Controller: controller},
+	}
+
+	if isDefault {
+		ingressClass.Annotations = map[string]string{AnnotationDefaultIngressClass: "true"}
+	}
+
+	return ingressClass
+}
+
+func TestResolverResolveIngressClass(t *testing.T) {
+
+	apiGroup := "k8s.example.com"
+	scope := networkingv1.IngressClassParametersReferenceScopeNamespace
+	namespace := "ingress-nginx"
+
+	nginx := newIngressClass("nginx", "k8s.io/ingress-nginx", true)
+	nginx.Spec.Parameters = &networkingv1.IngressClassParametersReference{
+		APIGroup:  &apiGroup,
+		Kind:      "IngressParameters",
+		Name:      "external-lb",
+		Scope:     &scope,
+		Namespace: &namespace,
+	}
+
+	controllerPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-nginx-controller-1",
+			Namespace: "ingress-nginx",
+			Labels:    map[string]string{"app.kubernetes.io/name": "ingress-nginx"},
+		},
+		Status: v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}},
+	}
+
+	classes := []runtime.Object{
+		nginx,
+		newIngressClass("traefik", "traefik.io/ingress-controller", false),
+		newIngressClass("custom", "example.com/custom", false),
+		controllerPod,
+	}
+
+	className := func(name string) *string { return &name }
+
+	tests := []struct {
+		name     string
+		objects  []runtime.Object
+		ingress  networkingv1.Ingress
+		expected *IngressClass
+	}{
+		{
+			"class name",
+			classes,
+			networkingv1.Ingress{Spec: networkingv1.IngressSpec{IngressClassName: className("custom")}},
+			&IngressClass{Name: "custom", Source: IngressClassSourceSpec, Found: true, Controller: "example.com/custom"},
+		},
+		{
+			"legacy annotation",
+			classes,
+			networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationIngressClass: "nginx"}}},
+			&IngressClass{Name: "nginx", Source: IngressClassSourceAnnotation, Found: true, Default: true, Controller: "k8s.io/ingress-nginx", Parameters: "k8s.example.com/IngressParameters/external-lb (namespace ingress-nginx)"},
+		},
+		{
+			"default class",
+			classes,
+			networkingv1.Ingress{},
+			&IngressClass{Name: "nginx", Source: IngressClassSourceDefault, Found: true, Default: true, Controller: "k8s.io/ingress-nginx", Parameters: "k8s.example.com/IngressParameters/external-lb (namespace ingress-nginx)"},
+		},
+		{
+			"class not found",
+			classes,
+			networkingv1.Ingress{Spec: networkingv1.IngressSpec{IngressClassName: className("missing")}},
+			&IngressClass{Name: "missing", Source: IngressClassSourceSpec, Warnings: []string{"IngressClass missing not found"}},
+		},
+		{
+			"controller not running",
+			classes,
+			networkingv1.Ingress{Spec: networkingv1.IngressSpec{IngressClassName: className("traefik")}},
+			&IngressClass{Name: "traefik", Source: IngressClassSourceSpec, Found: true, Controller: "traefik.io/ingress-controller", Warnings: []string{"no running pods found for the controller traefik.io/ingress-controller of IngressClass traefik"}},
+		},
+		{
+			"no default class",
+			[]runtime.Object{newIngressClass("custom", "example.com/custom", false)},
+			networkingv1.Ingress{},
+			&IngressClass{Warnings: []string{"ingress sets no class and the cluster has no default IngressClass, so it may not be served by any controller"}},
+		},
+		{
+			"no ingress classes",
+			nil,
+			networkingv1.Ingress{},
+			nil,
+		},
+	}
+
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: IngressNetworkingV1,
+			APIResources: []metav1.APIResource{
+				{Name: "ingresses", Kind: "Ingress", Namespaced: true},
+				{Name: "ingressclasses", Kind: "IngressClass"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+
+		test.ingress.Name = "ingress"
+		test.ingress.Namespace = "default"
+
+		resolver := NewResolver(NewFakeClient(resources, append(test.objects, &test.ingress)...), "default")
+
+		route, err := resolver.ResolveIngress("ingress")
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(route.IngressClass, test.expected) {
+			t.Errorf("Returned ingress class for %s was incorrect, got: %+v, want: %+v", test.name, route.IngressClass, test.expected)
+		}
+	}
+}
+
+func TestResolverResolveIngressClassForbidden(t *testing.T) {
+
+	ingressClass := `
+apiVersion: networking.k8s.io/v1
+kind: IngressClass
+metadata:
+  name: nginx
+spec:
+  controller: k8s.io/ingress-nginx
+`
+
+	withClass := `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ingress
+spec:
+  ingressClassName: nginx
+`
+
+	withoutClass := `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ingress
+`
+
+	tests := []struct {
+		name      string
+		manifest  string
+		forbidden string
+		expected  *IngressClass
+	}{
+		{
+			"ingress classes",
+			withClass,
+			"ingressclasses",
+			&IngressClass{Name: "nginx", Source: IngressClassSourceSpec, Forbidden: true, Warnings: []string{"IngressClasses can not be listed, so the controller of the class is not resolved"}},
+		},
+		{
+			"default ingress class",
+			withoutClass,
+			"ingressclasses",
+			&IngressClass{Forbidden: true, Warnings: []string{"IngressClasses can not be listed, so the default class of the ingress is not resolved"}},
+		},
+		{
+			"controller pods",
+			withClass,
+			"pods",
+			&IngressClass{Name: "nginx", Source: IngressClassSourceSpec, Found: true, Controller: "k8s.io/ingress-nginx", Warnings: []string{"pods can not be listed across namespaces, so whether the controller k8s.io/ingress-nginx is running is unknown"}},
+		},
+	}
+
+	for _, test := range tests {
+
+		client := &forbiddenClient{
+			ClientInterface: NewFakeMemoryClient(t, ingressClass, test.manifest),
+			resources:       map[string]bool{test.forbidden: true},
+		}
+
+		route, err := NewResolver(client, "default").ResolveIngress("ingress")
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(route.IngressClass, test.expected) {
+			t.Errorf("Returned ingress class for %s was incorrect, got: %+v, want: %+v", test.name, route.IngressClass, test.expected)
+		}
+
+		findings, err := NewResolver(client, "default").CheckIngress("ingress")
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+
+		if CountErrors(findings) != 0 {
+			t.Errorf("Returned findings for %s were incorrect, got: %+v", test.name, findings)
+		}
+	}
+}
+
+func TestResolverResolveIngressClassCached(t *testing.T) {
+
+	class := "nginx"
+
+	newIngress := func(name string, namespace string) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       networkingv1.IngressSpec{IngressClassName: &class},
+		}
+	}
+
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: IngressNetworkingV1,
+			APIResources: []metav1.APIResource{
+				{Name: "ingresses", Kind: "Ingress", Namespaced: true},
+				{Name: "ingressclasses", Kind: "IngressClass"},
+			},
+		},
+	}
+
+	client := NewFakeClient(resources,
+		newIngressClass("nginx", "k8s.io/ingress-nginx", true),
+		newIngress("foo", "default"),
+		newIngress("bar", "default"),
+		newIngress("baz", "other"),
+	)
+
+	resolver := NewResolver(client, "default")
+
+	if _, err := resolver.ResolveIngresses([]string{"foo", "bar"}, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := resolver.forNamespace("other").ResolveIngress("baz"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ingressClassLists, controllerPodLists := 0, 0
+	for _, action := range client.Clientset.(*fake.Clientset).Actions() {
+		switch {
+		case action.Matches("list", "ingressclasses"):
+			ingressClassLists++
+		case action.Matches("list", "pods") && action.GetNamespace() == "":
+			controllerPodLists++
+		}
+	}
+
+	if ingressClassLists != 1 || controllerPodLists != 1 {
+		t.Errorf("Returned list counts were incorrect, got: %d ingress classes and %d controller pods, want: 1 and 1", ingressClassLists, controllerPodLists)
+	}
+
+	resolver.ResetCache()
+
+	if _, err := resolver.ResolveIngress("foo"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ingressClassLists = 0
+	for _, action := range client.Clientset.(*fake.Clientset).Actions() {
+		if action.Matches("list", "ingressclasses") {
+			ingressClassLists++
+		}
+	}
+
+	if ingressClassLists != 2 {
+		t.Errorf("Returned ingress class list count after reset was incorrect, got: %d, want: %d", ingressClassLists, 2)
+	}
+}
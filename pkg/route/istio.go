@@ -0,0 +1,592 @@
+package route
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KindVirtualService is the kind of Istio virtual services
+const KindVirtualService = "VirtualService"
+
+// Istio objects are read through the dynamic client, so only the fields
+// used by the resolver are declared here instead of importing the typed API
+
+type virtualServiceObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Hosts []string       `json:"hosts,omitempty"`
+		HTTP  []istioHTTP    `json:"http,omitempty"`
+		TCP   []istioL4Route `json:"tcp,omitempty"`
+		TLS   []istioL4Route `json:"tls,omitempty"`
+	} `json:"spec"`
+}
+
+type istioHTTP struct {
+	Match   []istioHTTPMatch `json:"match,omitempty"`
+	Route   []istioRoute     `json:"route,omitempty"`
+	Rewrite *struct {
+		URI       string `json:"uri,omitempty"`
+		Authority string `json:"authority,omitempty"`
+	} `json:"rewrite,omitempty"`
+	Retries *struct {
+		Attempts      int32  `json:"attempts,omitempty"`
+		PerTryTimeout string `json:"perTryTimeout,omitempty"`
+		RetryOn       string `json:"retryOn,omitempty"`
+	} `json:"retries,omitempty"`
+}
+
+type istioHTTPMatch struct {
+	URI         *istioStringMatch           `json:"uri,omitempty"`
+	Headers     map[string]istioStringMatch `json:"headers,omitempty"`
+	QueryParams map[string]istioStringMatch `json:"queryParams,omitempty"`
+	Method      *istioStringMatch           `json:"method,omitempty"`
+	Authority   *istioStringMatch           `json:"authority,omitempty"`
+	Port        int32                       `json:"port,omitempty"`
+}
+
+type istioStringMatch struct {
+	Exact  string `json:"exact,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Regex  string `json:"regex,omitempty"`
+}
+
+type istioL4Route struct {
+	Match []struct {
+		Port     int32    `json:"port,omitempty"`
+		SNIHosts []string `json:"sniHosts,omitempty"`
+	} `json:"match,omitempty"`
+	Route []istioRoute `json:"route,omitempty"`
+}
+
+type istioRoute struct {
+	Destination struct {
+		Host   string `json:"host"`
+		Subset string `json:"subset,omitempty"`
+		Port   *struct {
+			Number int32 `json:"number"`
+		} `json:"port,omitempty"`
+	} `json:"destination"`
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+type destinationRuleObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Host    string `json:"host"`
+		Subsets []struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels,omitempty"`
+		} `json:"subsets,omitempty"`
+	} `json:"spec"`
+}
+
+type serviceEntryObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Hosts []string `json:"hosts"`
+		Ports []struct {
+			Number     int32  `json:"number"`
+			Protocol   string `json:"protocol,omitempty"`
+			TargetPort int32  `json:"targetPort,omitempty"`
+		} `json:"ports,omitempty"`
+		Endpoints []struct {
+			Address string `json:"address"`
+		} `json:"endpoints,omitempty"`
+	} `json:"spec"`
+}
+
+// istioDestinations resolves the destinations of a virtual service. Service entries and
+// destination rules of every namespace are only read once, the first time they are needed
+type istioDestinations struct {
+	resolver         *Resolver
+	serviceEntries   []*serviceEntryObject
+	destinationRules []*destinationRuleObject
+	loaded           bool
+}
+
+// ResolveVirtualService returns the route graph of the Istio virtual service that matches a given
+// name. Each HTTP, TCP and TLS route is expanded into a path per match and weighted destination
+func (r *Resolver) ResolveVirtualService(name string) (*Route, error) {
+
+	object, err := r.Client.GetCustomResourceByName("virtualservices", IstioNetworkingGroupVersions, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if object == nil {
+		return nil, fmt.Errorf("virtualservice %q not found", name)
+	}
+
+	virtualService := &virtualServiceObject{}
+	if err := fromUnstructured(object, virtualService); err != nil {
+		return nil, err
+	}
+
+	destinations := &istioDestinations{resolver: r}
+	paths := []Path{}
+
+	for _, http := range virtualService.Spec.HTTP {
+
+		backends, err := destinations.resolve(http.Route)
+		if err != nil {
+			return nil, err
+		}
+
+		// A route without matches matches every request
+		matches := http.Match
+		if len(matches) == 0 {
+			matches = []istioHTTPMatch{{}}
+		}
+
+		for _, match := range matches {
+			for _, backend := range backends {
+				path := backend
+				setIstioHTTPMatch(&path, match)
+				setIstioHTTPPolicies(&path, http)
+
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	for protocol, l4Routes := range map[string][]istioL4Route{"TCP": virtualService.Spec.TCP, "TLS": virtualService.Spec.TLS} {
+		for _, l4Route := range l4Routes {
+
+			backends, err := destinations.resolve(l4Route.Route)
+			if err != nil {
+				return nil, err
+			}
+
+			conditions := [][]string{nil}
+			if len(l4Route.Match) > 0 {
+				conditions = [][]string{}
+			}
+
+			for _, match := range l4Route.Match {
+				matchConditions := []string{}
+
+				if match.Port != 0 {
+					matchConditions = append(matchConditions, "port="+strconv.FormatInt(int64(match.Port), 10))
+				}
+
+				for _, sniHost := range match.SNIHosts {
+					matchConditions = append(matchConditions, "sni="+sniHost)
+				}
+
+				conditions = append(conditions, matchConditions)
+			}
+
+			for _, matchConditions := range conditions {
+				for _, backend := range backends {
+					path := backend
+					path.Protocol = protocol
+					path.Conditions = matchConditions
+
+					paths = append(paths, path)
+				}
+			}
+		}
+	}
+
+	// TCP routes are listed before TLS routes regardless of the map iteration order
+	sort.SliceStable(paths, func(i, j int) bool {
+		return protocolPriority(paths[i].Protocol) < protocolPriority(paths[j].Protocol)
+	})
+
+	route := &Route{
+		Kind:      KindVirtualService,
+		Name:      virtualService.Name,
+		Namespace: r.Namespace,
+		Hosts:     []Host{},
+	}
+
+	for _, host := range virtualService.Spec.Hosts {
+		route.Hosts = append(route.Hosts, Host{
+			Host:  host,
+			Paths: paths,
+		})
+	}
+
+	return route, nil
+}
+
+func protocolPriority(protocol string) int {
+	switch protocol {
+	case "":
+		return 0
+	case "TCP":
+		return 1
+	}
+
+	return 2
+}
+
+// resolve returns a path per destination of a route, with the backend
+// service narrowed to the pods of the destination subset, if any
+func (d *istioDestinations) resolve(routes []istioRoute) ([]Path, error) {
+
+	backends := []Path{}
+
+	for _, route := range routes {
+
+		path := Path{
+			Weight: route.Weight,
+		}
+
+		// A single destination receives all the traffic when no weight is set
+		if path.Weight == nil && len(routes) == 1 {
+			weight := int32(100)
+			path.Weight = &weight
+		}
+
+		if route.Destination.Port != nil {
+			path.BackendPort = strconv.FormatInt(int64(route.Destination.Port.Number), 10)
+		}
+
+		service, err := d.resolveHost(route.Destination.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		path.Service = service
+
+		if route.Destination.Subset != "" {
+			path.Subset, err = d.resolveSubset(service, route.Destination.Host, route.Destination.Subset)
+			if err != nil {
+				return nil, err
+			}
+
+			if path.Subset.Found && service.Type != "ServiceEntry" {
+				path.Service, err = d.narrowService(service, path.Subset.Labels)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		backends = append(backends, path)
+	}
+
+	return backends, nil
+}
+
+// resolveHost returns the service of a destination host. Short names are services of the
+// virtual service namespace, and name.namespace.svc FQDNs are services of that namespace.
+// Any other host is looked up in the hosts of the service entries of every namespace
+func (d *istioDestinations) resolveHost(host string) (*Service, error) {
+
+	if name, namespace, ok := istioServiceName(host, d.resolver.Namespace); ok {
+		return d.resolver.forNamespace(namespace).resolveBackendService(name)
+	}
+
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+
+	for _, serviceEntry := range d.serviceEntries {
+		for _, serviceEntryHost := range serviceEntry.Spec.Hosts {
+			if MatchHost(serviceEntryHost, host) == HostMatchNone {
+				continue
+			}
+
+			service := &Service{
+				Name:      host,
+				Namespace: serviceEntry.Namespace,
+				Found:     true,
+				Type:      "ServiceEntry",
+			}
+
+			for _, port := range serviceEntry.Spec.Ports {
+				targetPort := port.TargetPort
+				if targetPort == 0 {
+					targetPort = port.Number
+				}
+
+				service.Ports = append(service.Ports, Port{
+					Port:       port.Number,
+					TargetPort: strconv.FormatInt(int64(targetPort), 10),
+					Protocol:   port.Protocol,
+				})
+			}
+
+			for _, endpoint := range serviceEntry.Spec.Endpoints {
+				service.Endpoints = append(service.Endpoints, Endpoint{
+					Addresses: []string{endpoint.Address},
+					Ready:     true,
+				})
+			}
+
+			// Service entries without endpoints are resolved through DNS
+			if len(service.Endpoints) == 0 {
+				service.Hostname = host
+			}
+
+			return service, nil
+		}
+	}
+
+	return &Service{Name: host, Namespace: d.resolver.Namespace}, nil
+}
+
+// resolveSubset returns the labels of a destination subset, which are read from the
+// destination rule of the destination host. Destination rules of the virtual service
+// namespace are preferred to the ones of the service namespace, and these to any other
+func (d *istioDestinations) resolveSubset(service *Service, host string, name string) (*Subset, error) {
+
+	subset := &Subset{Name: name}
+
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+
+	var destinationRule *destinationRuleObject
+	priority := 0
+
+	for _, candidate := range d.destinationRules {
+		if !d.sameHost(candidate, service, host) {
+			continue
+		}
+
+		candidatePriority := 1
+		switch candidate.Namespace {
+		case d.resolver.Namespace:
+			candidatePriority = 3
+		case service.Namespace:
+			candidatePriority = 2
+		}
+
+		if candidatePriority > priority {
+			destinationRule = candidate
+			priority = candidatePriority
+		}
+	}
+
+	if destinationRule == nil {
+		return subset, nil
+	}
+
+	for _, destinationRuleSubset := range destinationRule.Spec.Subsets {
+		if destinationRuleSubset.Name == name {
+			subset.Found = true
+			subset.Labels = destinationRuleSubset.Labels
+		}
+	}
+
+	return subset, nil
+}
+
+// sameHost returns whether a destination rule applies to the service of a destination host
+func (d *istioDestinations) sameHost(destinationRule *destinationRuleObject, service *Service, host string) bool {
+	if destinationRule.Spec.Host == host {
+		return true
+	}
+
+	name, namespace, ok := istioServiceName(destinationRule.Spec.Host, destinationRule.Namespace)
+
+	return ok && service.Type != "ServiceEntry" && name == service.Name && namespace == service.Namespace
+}
+
+// narrowService returns a copy of a service with only the pods and endpoints of the pods that match the
+// labels of a subset. The pods are read from the service namespace
+func (d *istioDestinations) narrowService(service *Service, labels map[string]string) (*Service, error) {
+
+	if !service.Found {
+		return service, nil
+	}
+
+	pods, err := d.resolver.forNamespace(service.Namespace).Client.GetPodsByLabels(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := map[string]bool{}
+	for _, pod := range pods.Items {
+		selected[pod.Name] = true
+	}
+
+	narrowed := *service
+	narrowed.Pods = nil
+	narrowed.Endpoints = nil
+
+	for _, pod := range service.Pods {
+		if selected[pod.Name] {
+			narrowed.Pods = append(narrowed.Pods, pod)
+		}
+	}
+
+	for _, endpoint := range service.Endpoints {
+		if selected[endpoint.Pod] {
+			narrowed.Endpoints = append(narrowed.Endpoints, endpoint)
+		}
+	}
+
+	return &narrowed, nil
+}
+
+// load reads the service entries and destination rules of every namespace
+func (d *istioDestinations) load() error {
+	if d.loaded {
+		return nil
+	}
+
+	client := d.resolver.Client.Namespaced("")
+
+	serviceEntries, err := client.ListCustomResources("serviceentries", IstioNetworkingGroupVersions, "")
+	if err != nil {
+		return err
+	}
+
+	for index := range serviceEntries.Items {
+		serviceEntry := &serviceEntryObject{}
+		if err := fromUnstructured(&serviceEntries.Items[index], serviceEntry); err != nil {
+			return err
+		}
+
+		d.serviceEntries = append(d.serviceEntries, serviceEntry)
+	}
+
+	destinationRules, err := client.ListCustomResources("destinationrules", IstioNetworkingGroupVersions, "")
+	if err != nil {
+		return err
+	}
+
+	for index := range destinationRules.Items {
+		destinationRule := &destinationRuleObject{}
+		if err := fromUnstructured(&destinationRules.Items[index], destinationRule); err != nil {
+			return err
+		}
+
+		d.destinationRules = append(d.destinationRules, destinationRule)
+	}
+
+	sort.SliceStable(d.serviceEntries, func(i, j int) bool {
+		return objectKey(d.serviceEntries[i].ObjectMeta) < objectKey(d.serviceEntries[j].ObjectMeta)
+	})
+
+	sort.SliceStable(d.destinationRules, func(i, j int) bool {
+		return objectKey(d.destinationRules[i].ObjectMeta) < objectKey(d.destinationRules[j].ObjectMeta)
+	})
+
+	d.loaded = true
+
+	return nil
+}
+
+// istioServiceName returns the name and namespace of the service of an Istio host. Hosts without
+// dots are short names of services of the given namespace, and hosts whose third label is svc are
+// service FQDNs, e.g. reviews.prod.svc.cluster.local. Any other host is not a service
+func istioServiceName(host string, namespace string) (string, string, bool) {
+	labels := strings.Split(host, ".")
+
+	switch {
+	case len(labels) == 1:
+		return host, namespace, true
+
+	case len(labels) >= 3 && labels[2] == "svc":
+		return labels[0], labels[1], true
+	}
+
+	return "", "", false
+}
+
+// setIstioHTTPMatch sets the request conditions of an Istio HTTP match on a path.
+// Values are written as name=value for exact matches, name^=value for prefix
+// matches and name~=value for regular expression matches
+func setIstioHTTPMatch(path *Path, match istioHTTPMatch) {
+	path.Path = "/"
+	path.PathType = "Prefix"
+
+	if match.URI != nil {
+		switch {
+		case match.URI.Exact != "":
+			path.Path = match.URI.Exact
+			path.PathType = "Exact"
+		case match.URI.Prefix != "":
+			path.Path = match.URI.Prefix
+		case match.URI.Regex != "":
+			path.Path = match.URI.Regex
+			path.PathType = "RegularExpression"
+		}
+	}
+
+	for _, name := range sortedKeys(match.Headers) {
+		path.Headers = append(path.Headers, istioStringMatchToString(name, match.Headers[name]))
+	}
+
+	for _, name := range sortedKeys(match.QueryParams) {
+		path.QueryParams = append(path.QueryParams, istioStringMatchToString(name, match.QueryParams[name]))
+	}
+
+	if match.Method != nil {
+		if match.Method.Exact != "" {
+			path.Method = match.Method.Exact
+		} else {
+			path.Conditions = append(path.Conditions, istioStringMatchToString("method", *match.Method))
+		}
+	}
+
+	if match.Authority != nil {
+		path.Conditions = append(path.Conditions, istioStringMatchToString("authority", *match.Authority))
+	}
+
+	if match.Port != 0 {
+		path.Conditions = append(path.Conditions, "port="+strconv.FormatInt(int64(match.Port), 10))
+	}
+}
+
+// setIstioHTTPPolicies sets the rewrite and retry policies of an Istio HTTP route on a path
+func setIstioHTTPPolicies(path *Path, http istioHTTP) {
+	if http.Rewrite != nil {
+		rewrites := []string{}
+
+		if http.Rewrite.URI != "" {
+			rewrites = append(rewrites, "uri="+http.Rewrite.URI)
+		}
+
+		if http.Rewrite.Authority != "" {
+			rewrites = append(rewrites, "authority="+http.Rewrite.Authority)
+		}
+
+		path.Rewrite = strings.Join(rewrites, ",")
+	}
+
+	if http.Retries != nil {
+		retries := []string{"attempts=" + strconv.FormatInt(int64(http.Retries.Attempts), 10)}
+
+		if http.Retries.PerTryTimeout != "" {
+			retries = append(retries, "perTryTimeout="+http.Retries.PerTryTimeout)
+		}
+
+		if http.Retries.RetryOn != "" {
+			retries = append(retries, "retryOn="+http.Retries.RetryOn)
+		}
+
+		path.Retries = strings.Join(retries, ",")
+	}
+}
+
+func istioStringMatchToString(name string, match istioStringMatch) string {
+	switch {
+	case match.Prefix != "":
+		return name + "^=" + match.Prefix
+	case match.Regex != "":
+		return name + "~=" + match.Regex
+	}
+
+	return name + "=" + match.Exact
+}
+
+func sortedKeys(matches map[string]istioStringMatch) []string {
+	keys := []string{}
+	for key := range matches {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,244 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// NewFakeIstioResolver returns a Resolver for a virtual service with weighted HTTP destinations
+// narrowed by DestinationRule subsets, a TCP route to a service of another namespace and a TLS
+// route to a ServiceEntry host
+func NewFakeIstioResolver(t *testing.T) *Resolver {
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: IstioNetworkingV1beta1,
+			APIResources: []metav1.APIResource{
+				{Name: "virtualservices", Kind: "VirtualService", Namespaced: true},
+				{Name: "destinationrules", Kind: "DestinationRule", Namespaced: true},
+				{Name: "serviceentries", Kind: "ServiceEntry", Namespaced: true},
+			},
+		},
+	}
+
+	objects := []runtime.Object{
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "reviews-v1", Namespace: "default", Labels: map[string]string{"app": "reviews", "version": "v1"}}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "reviews-v2", Namespace: "default", Labels: map[string]string{"app": "reviews", "version": "v2"}}},
+		&v1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "default"},
+			Subsets: []v1.EndpointSubset{
+				{
+					Addresses: []v1.EndpointAddress{
+						{IP: "10.0.0.1", TargetRef: &v1.ObjectReference{Kind: "Pod", Name: "reviews-v1"}},
+						{IP: "10.0.0.2", TargetRef: &v1.ObjectReference{Kind: "Pod", Name: "reviews-v2"}},
+					},
+					Ports: []v1.EndpointPort{{Port: 9080, Protocol: v1.ProtocolTCP}},
+				},
+			},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "default"},
+			Spec: v1.ServiceSpec{
+				Type:     v1.ServiceTypeClusterIP,
+				Ports:    []v1.ServicePort{{Port: 9080, TargetPort: intstr.FromInt(9080), Protocol: v1.ProtocolTCP}},
+				Selector: map[string]string{"app": "reviews"},
+			},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "mongo", Namespace: "backend"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeExternalName, ExternalName: "mongo.example.com"},
+		},
+	}
+
+	client := NewFakeCustomResourceClient(t, resources, objects, `
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: default
+spec:
+  hosts:
+  - reviews.foo.com
+  http:
+  - match:
+    - uri:
+        prefix: /api
+      headers:
+        end-user:
+          exact: jason
+      method:
+        exact: GET
+    rewrite:
+      uri: /
+    retries:
+      attempts: 3
+      perTryTimeout: 2s
+    route:
+    - destination:
+        host: reviews
+        subset: v1
+        port:
+          number: 9080
+      weight: 80
+    - destination:
+        host: reviews.default.svc.cluster.local
+        subset: v3
+      weight: 20
+  tcp:
+  - match:
+    - port: 27017
+    route:
+    - destination:
+        host: mongo.backend.svc.cluster.local
+  tls:
+  - match:
+    - sniHosts:
+      - api.example.com
+    route:
+    - destination:
+        host: api.example.com
+        port:
+          number: 443
+`, `
+apiVersion: networking.istio.io/v1beta1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: default
+spec:
+  host: reviews
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+  - name: v2
+    labels:
+      version: v2
+`, `
+apiVersion: networking.istio.io/v1beta1
+kind: ServiceEntry
+metadata:
+  name: external-api
+  namespace: external
+spec:
+  hosts:
+  - "*.example.com"
+  ports:
+  - number: 443
+    protocol: TLS
+`)
+
+	return NewResolver(client, "default")
+}
+
+func TestResolverResolveVirtualService(t *testing.T) {
+
+	weight80 := int32(80)
+	weight20 := int32(20)
+	weight100 := int32(100)
+
+	reviews := &Service{
+		Name:      "reviews",
+		Namespace: "default",
+		Found:     true,
+		Type:      "ClusterIP",
+		Ports:     []Port{{Port: 9080, TargetPort: "9080", Protocol: "TCP"}},
+		Pods:      []Pod{{Name: "reviews-v1", Ready: true}, {Name: "reviews-v2", Ready: true}},
+		Endpoints: []Endpoint{
+			{Addresses: []string{"10.0.0.1"}, Ready: true, Pod: "reviews-v1", Ports: []EndpointPort{{Port: 9080, Protocol: "TCP"}}},
+			{Addresses: []string{"10.0.0.2"}, Ready: true, Pod: "reviews-v2", Ports: []EndpointPort{{Port: 9080, Protocol: "TCP"}}},
+		},
+	}
+
+	reviewsV1 := *reviews
+	reviewsV1.Pods = reviews.Pods[:1]
+	reviewsV1.Endpoints = reviews.Endpoints[:1]
+
+	paths := []Path{
+		{
+			Path:        "/api",
+			PathType:    "Prefix",
+			Headers:     []string{"end-user=jason"},
+			Method:      "GET",
+			Rewrite:     "uri=/",
+			Retries:     "attempts=3,perTryTimeout=2s",
+			BackendPort: "9080",
+			Weight:      &weight80,
+			Subset:      &Subset{Name: "v1", Found: true, Labels: map[string]string{"version": "v1"}},
+			Service:     &reviewsV1,
+		},
+		{
+			Path:     "/api",
+			PathType: "Prefix",
+			Headers:  []string{"end-user=jason"},
+			Method:   "GET",
+			Rewrite:  "uri=/",
+			Retries:  "attempts=3,perTryTimeout=2s",
+			Weight:   &weight20,
+			Subset:   &Subset{Name: "v3"},
+			Service:  reviews,
+		},
+		{
+			Protocol:   "TCP",
+			Conditions: []string{"port=27017"},
+			Weight:     &weight100,
+			Service:    &Service{Name: "mongo", Namespace: "backend", Found: true, Type: "ExternalName", Hostname: "mongo.example.com"},
+		},
+		{
+			Protocol:    "TLS",
+			Conditions:  []string{"sni=api.example.com"},
+			BackendPort: "443",
+			Weight:      &weight100,
+			Service: &Service{
+				Name:      "api.example.com",
+				Namespace: "external",
+				Found:     true,
+				Type:      "ServiceEntry",
+				Ports:     []Port{{Port: 443, TargetPort: "443", Protocol: "TLS"}},
+				Hostname:  "api.example.com",
+			},
+		},
+	}
+
+	expected := &Route{
+		Kind:      "VirtualService",
+		Name:      "reviews",
+		Namespace: "default",
+		Hosts:     []Host{{Host: "reviews.foo.com", Paths: paths}},
+	}
+
+	route, err := NewFakeIstioResolver(t).ResolveVirtualService("reviews")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(route, expected) {
+		t.Errorf("Returned route was incorrect,\ngot:\n%+v\nwant:\n%+v", route, expected)
+	}
+}
+
+func TestIstioServiceName(t *testing.T) {
+
+	tests := []struct {
+		host      string
+		name      string
+		namespace string
+		ok        bool
+	}{
+		{"reviews", "reviews", "default", true},
+		{"reviews.prod.svc.cluster.local", "reviews", "prod", true},
+		{"reviews.prod.svc", "reviews", "prod", true},
+		{"api.example.com", "", "", false},
+	}
+
+	for _, test := range tests {
+		name, namespace, ok := istioServiceName(test.host, "default")
+		if name != test.name || namespace != test.namespace || ok != test.ok {
+			t.Errorf("Returned service of host %s was incorrect, got: %s/%s %v", test.host, namespace, name, ok)
+		}
+	}
+}
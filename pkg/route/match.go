@@ -0,0 +1,92 @@
+package route
+
+import (
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// Host match priorities, a higher priority wins over a lower one
+const (
+	HostMatchNone = iota
+	HostMatchAny
+	HostMatchWildcard
+	HostMatchExact
+)
+
+// MatchHost returns the priority of the match between an ingress rule host
+// and a request host. A rule without host matches every request host, and a
+// wildcard host matches a single DNS label, so *.foo.com matches bar.foo.com
+// but neither foo.com nor baz.bar.foo.com
+func MatchHost(ruleHost string, host string) int {
+	ruleHost = strings.ToLower(ruleHost)
+	host = strings.ToLower(host)
+
+	switch {
+	case ruleHost == "":
+		return HostMatchAny
+
+	case ruleHost == host:
+		return HostMatchExact
+
+	case strings.HasPrefix(ruleHost, "*."):
+		index := strings.Index(host, ".")
+		if index > 0 && host[index:] == ruleHost[1:] {
+			return HostMatchWildcard
+		}
+	}
+
+	return HostMatchNone
+}
+
+// MatchPath returns whether an ingress path of the given type matches a request path.
+// Prefix paths are matched element by element, ignoring trailing slashes, so /foo
+// matches /foo and /foo/bar but not /foobar. ImplementationSpecific paths are
+// matched as Prefix paths, which is what most ingress controllers do
+func MatchPath(pathType string, path string, requestPath string) bool {
+	if requestPath == "" {
+		requestPath = "/"
+	}
+
+	if pathType == string(networkingv1.PathTypeExact) {
+		return path == requestPath
+	}
+
+	pathElements := splitPath(path)
+	requestElements := splitPath(requestPath)
+
+	if len(pathElements) > len(requestElements) {
+		return false
+	}
+
+	for index, element := range pathElements {
+		if requestElements[index] != element {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PathTypePriority returns the priority of a path type used to break ties between
+// paths of the same length, a higher priority wins over a lower one
+func PathTypePriority(pathType string) int {
+	switch pathType {
+	case string(networkingv1.PathTypeExact):
+		return 2
+	case string(networkingv1.PathTypePrefix):
+		return 1
+	}
+
+	return 0
+}
+
+func splitPath(path string) (elements []string) {
+	for _, element := range strings.Split(path, "/") {
+		if element != "" {
+			elements = append(elements, element)
+		}
+	}
+
+	return
+}
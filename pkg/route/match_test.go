@@ -0,0 +1,68 @@
+package route
+
+import (
+	"testing"
+)
+
+func TestMatchHost(t *testing.T) {
+
+	tests := []struct {
+		ruleHost         string
+		host             string
+		expectedPriority int
+	}{
+		{"foo.com", "foo.com", HostMatchExact},
+		{"foo.com", "FOO.com", HostMatchExact},
+		{"foo.com", "bar.com", HostMatchNone},
+		{"*.foo.com", "bar.foo.com", HostMatchWildcard},
+		{"*.foo.com", "foo.com", HostMatchNone},
+		{"*.foo.com", "baz.bar.foo.com", HostMatchNone},
+		{"", "foo.com", HostMatchAny},
+	}
+
+	for _, test := range tests {
+		priority := MatchHost(test.ruleHost, test.host)
+
+		if priority != test.expectedPriority {
+			t.Errorf("Returned host priority was incorrect for %q and %q, got: %d, want: %d", test.ruleHost, test.host, priority, test.expectedPriority)
+		}
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+
+	// Examples from the Kubernetes ingress documentation
+	tests := []struct {
+		pathType      string
+		path          string
+		requestPath   string
+		expectedMatch bool
+	}{
+		{"Prefix", "/", "/", true},
+		{"Prefix", "/", "/aaa/bbb", true},
+		{"Exact", "/foo", "/foo", true},
+		{"Exact", "/foo", "/bar", false},
+		{"Exact", "/foo", "/foo/", false},
+		{"Exact", "/foo/", "/foo", false},
+		{"Prefix", "/foo", "/foo", true},
+		{"Prefix", "/foo", "/foo/", true},
+		{"Prefix", "/foo/", "/foo", true},
+		{"Prefix", "/foo/", "/foo/", true},
+		{"Prefix", "/aaa/bb", "/aaa/bbb", false},
+		{"Prefix", "/aaa/bbb", "/aaa/bbb", true},
+		{"Prefix", "/aaa/bbb/", "/aaa/bbb", true},
+		{"Prefix", "/aaa/bbb", "/aaa/bbb/", true},
+		{"Prefix", "/aaa/bbb", "/aaa/bbb/ccc", true},
+		{"Prefix", "/aaa/bbb", "/aaa/bbbxyz", false},
+		{"ImplementationSpecific", "/aaa", "/aaa/ccc", true},
+		{"ImplementationSpecific", "", "/aaa", true},
+	}
+
+	for _, test := range tests {
+		match := MatchPath(test.pathType, test.path, test.requestPath)
+
+		if match != test.expectedMatch {
+			t.Errorf("Returned match was incorrect for %s %q and %q, got: %t, want: %t", test.pathType, test.path, test.requestPath, match, test.expectedMatch)
+		}
+	}
+}
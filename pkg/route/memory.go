@@ -0,0 +1,606 @@
+package route
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// workloadTemplatePaths lists the fields of the pod templates of the workload kinds
+var workloadTemplatePaths = map[schema.GroupKind][]string{
+	{Group: "apps", Kind: "Deployment"}:        {"spec", "template"},
+	{Group: "apps", Kind: "StatefulSet"}:       {"spec", "template"},
+	{Group: "apps", Kind: "ReplicaSet"}:        {"spec", "template"},
+	{Group: "apps", Kind: "DaemonSet"}:         {"spec", "template"},
+	{Group: "", Kind: "ReplicationController"}: {"spec", "template"},
+	{Group: "batch", Kind: "Job"}:              {"spec", "template"},
+	{Group: "batch", Kind: "CronJob"}:          {"spec", "jobTemplate", "spec", "template"},
+}
+
+// clusterScopedKinds lists the kinds of the cluster scoped objects read by the resolver
+var clusterScopedKinds = map[string]bool{
+	"Namespace":    true,
+	"Node":         true,
+	"IngressClass": true,
+	"GatewayClass": true,
+}
+
+// MemoryClient is a ClientInterface that serves objects loaded from manifests instead of
+// a cluster. Pods are synthesized from the pod templates of workloads, and endpoint slices
+// from the pods selected by services, so that route graphs can be resolved before deploying
+type MemoryClient struct {
+	Namespace string
+
+	objects *memoryObjects
+}
+
+// memoryObjects holds the objects of a memory client, shared by its namespaced copies
+type memoryObjects struct {
+	pods            []v1.Pod
+	services        []v1.Service
+	ingresses       []networkingv1.Ingress
+	ingressClasses  []networkingv1.IngressClass
+	endpointSlices  []discoveryv1.EndpointSlice
+	namespaces      []v1.Namespace
+	nodes           []v1.Node
+	secrets         []v1.Secret
+	customResources []unstructured.Unstructured
+}
+
+// NewMemoryClient returns a MemoryClient that serves the given objects.
+// Namespaced objects without namespace are set in the client namespace
+func NewMemoryClient(namespace string, objects ...*unstructured.Unstructured) (*MemoryClient, error) {
+	client := &MemoryClient{
+		Namespace: namespace,
+		objects:   &memoryObjects{},
+	}
+
+	for _, object := range objects {
+		if err := client.add(object); err != nil {
+			return nil, fmt.Errorf("%s %q: %v", object.GetKind(), object.GetName(), err)
+		}
+	}
+
+	return client, nil
+}
+
+// add converts an object to its typed representation and stores it
+func (c *MemoryClient) add(object *unstructured.Unstructured) error {
+	object = object.DeepCopy()
+
+	if object.GetNamespace() == "" && !clusterScopedKinds[object.GetKind()] {
+		object.SetNamespace(c.Namespace)
+	}
+
+	gvk := object.GroupVersionKind()
+
+	if path, ok := workloadTemplatePaths[gvk.GroupKind()]; ok {
+		return c.addWorkloadPods(object, path)
+	}
+
+	objects := c.objects
+
+	switch gvk {
+	case v1.SchemeGroupVersion.WithKind("Pod"):
+		pod := v1.Pod{}
+		if err := fromUnstructured(object, &pod); err != nil {
+			return err
+		}
+
+		objects.pods = append(objects.pods, expectedPod(pod))
+
+	case v1.SchemeGroupVersion.WithKind("Service"):
+		service := v1.Service{}
+		if err := fromUnstructured(object, &service); err != nil {
+			return err
+		}
+
+		objects.services = append(objects.services, defaultService(service))
+
+	case v1.SchemeGroupVersion.WithKind("Endpoints"):
+		endpoints := &v1.Endpoints{}
+		if err := fromUnstructured(object, endpoints); err != nil {
+			return err
+		}
+
+		for _, endpointSlice := range EndpointSlicesFromEndpoints(endpoints).Items {
+			endpointSlice.Namespace = endpoints.Namespace
+			endpointSlice.Labels = map[string]string{discoveryv1.LabelServiceName: endpoints.Name}
+			objects.endpointSlices = append(objects.endpointSlices, endpointSlice)
+		}
+
+	case v1.SchemeGroupVersion.WithKind("Namespace"):
+		namespace := v1.Namespace{}
+		if err := fromUnstructured(object, &namespace); err != nil {
+			return err
+		}
+
+		objects.namespaces = append(objects.namespaces, namespace)
+
+	case v1.SchemeGroupVersion.WithKind("Node"):
+		node := v1.Node{}
+		if err := fromUnstructured(object, &node); err != nil {
+			return err
+		}
+
+		objects.nodes = append(objects.nodes, node)
+
+	case v1.SchemeGroupVersion.WithKind("Secret"):
+		secret := v1.Secret{}
+		if err := fromUnstructured(object, &secret); err != nil {
+			return err
+		}
+
+		objects.secrets = append(objects.secrets, secret)
+
+	case networkingv1.SchemeGroupVersion.WithKind("Ingress"):
+		ingress := networkingv1.Ingress{}
+		if err := fromUnstructured(object, &ingress); err != nil {
+			return err
+		}
+
+		objects.ingresses = append(objects.ingresses, ingress)
+
+	case networkingv1beta1.SchemeGroupVersion.WithKind("Ingress"):
+		ingress := &networkingv1beta1.Ingress{}
+		if err := fromUnstructured(object, ingress); err != nil {
+			return err
+		}
+
+		objects.ingresses = append(objects.ingresses, *IngressFromNetworkingV1beta1(ingress))
+
+	case extensionsv1beta1.SchemeGroupVersion.WithKind("Ingress"):
+		ingress := &extensionsv1beta1.Ingress{}
+		if err := fromUnstructured(object, ingress); err != nil {
+			return err
+		}
+
+		converted, err := IngressFromExtensionsV1beta1(ingress)
+		if err != nil {
+			return err
+		}
+
+		objects.ingresses = append(objects.ingresses, *converted)
+
+	case networkingv1.SchemeGroupVersion.WithKind("IngressClass"):
+		ingressClass := networkingv1.IngressClass{}
+		if err := fromUnstructured(object, &ingressClass); err != nil {
+			return err
+		}
+
+		objects.ingressClasses = append(objects.ingressClasses, ingressClass)
+
+	case networkingv1beta1.SchemeGroupVersion.WithKind("IngressClass"):
+		ingressClass := &networkingv1beta1.IngressClass{}
+		if err := fromUnstructured(object, ingressClass); err != nil {
+			return err
+		}
+
+		converted, err := IngressClassFromNetworkingV1beta1(ingressClass)
+		if err != nil {
+			return err
+		}
+
+		objects.ingressClasses = append(objects.ingressClasses, *converted)
+
+	case discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"):
+		endpointSlice := discoveryv1.EndpointSlice{}
+		if err := fromUnstructured(object, &endpointSlice); err != nil {
+			return err
+		}
+
+		objects.endpointSlices = append(objects.endpointSlices, endpointSlice)
+
+	case discoveryv1beta1.SchemeGroupVersion.WithKind("EndpointSlice"):
+		endpointSlice := &discoveryv1beta1.EndpointSlice{}
+		if err := fromUnstructured(object, endpointSlice); err != nil {
+			return err
+		}
+
+		objects.endpointSlices = append(objects.endpointSlices, *EndpointSliceFromDiscoveryV1beta1(endpointSlice))
+
+	default:
+		objects.customResources = append(objects.customResources, *object)
+	}
+
+	return nil
+}
+
+// addWorkloadPods adds the pods a workload is expected to run, one per replica, named
+// after the workload and the replica index. The pods do not run any workload, they
+// are only used to match service selectors and to resolve named target ports
+func (c *MemoryClient) addWorkloadPods(object *unstructured.Unstructured, templatePath []string) error {
+
+	template, found, err := unstructured.NestedMap(object.Object, templatePath...)
+	if err != nil || !found {
+		return err
+	}
+
+	podTemplate := v1.PodTemplateSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, &podTemplate); err != nil {
+		return err
+	}
+
+	workload := struct {
+		Spec struct {
+			Replicas *int32 `json:"replicas,omitempty"`
+		} `json:"spec"`
+	}{}
+
+	if err := fromUnstructured(object, &workload); err != nil {
+		return err
+	}
+
+	// Workloads without replicas run a single pod, e.g. daemon sets are expected to run on one node at least
+	replicas := int32(1)
+	if workload.Spec.Replicas != nil {
+		replicas = *workload.Spec.Replicas
+	}
+
+	for index := int32(0); index < replicas; index++ {
+		pod := v1.Pod{
+			ObjectMeta: *podTemplate.ObjectMeta.DeepCopy(),
+			Spec:       *podTemplate.Spec.DeepCopy(),
+		}
+
+		pod.Name = object.GetName() + "-" + strconv.Itoa(int(index))
+		pod.Namespace = object.GetNamespace()
+
+		c.objects.pods = append(c.objects.pods, expectedPod(pod))
+	}
+
+	return nil
+}
+
+// expectedPod returns a pod that is running with all its containers ready when its manifest
+// does not have a status, as manifests describe the pods expected to run
+func expectedPod(pod v1.Pod) v1.Pod {
+	if len(pod.Status.Conditions) == 0 {
+		pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	}
+
+	if pod.Status.Phase == "" {
+		pod.Status.Phase = v1.PodRunning
+	}
+
+	if len(pod.Status.ContainerStatuses) == 0 {
+		for _, container := range pod.Spec.Containers {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{Name: container.Name, Ready: true})
+		}
+	}
+
+	return pod
+}
+
+// defaultService returns a service with the defaults set by the API server on creation
+func defaultService(service v1.Service) v1.Service {
+	if service.Spec.Type == "" {
+		service.Spec.Type = v1.ServiceTypeClusterIP
+	}
+
+	for index := range service.Spec.Ports {
+		port := &service.Spec.Ports[index]
+
+		if port.Protocol == "" {
+			port.Protocol = v1.ProtocolTCP
+		}
+
+		if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal == 0 {
+			port.TargetPort = intstr.FromInt(int(port.Port))
+		}
+	}
+
+	return service
+}
+
+// Namespaced returns a copy of the client scoped to the given namespace.
+// The copy shares the objects of the client
+func (c *MemoryClient) Namespaced(namespace string) ClientInterface {
+	return &MemoryClient{
+		Namespace: namespace,
+		objects:   c.objects,
+	}
+}
+
+// inNamespace returns whether an object is in the client namespace, which is
+// always the case when the client namespace is empty
+func (c *MemoryClient) inNamespace(meta metav1.ObjectMeta) bool {
+	return c.Namespace == "" || meta.Namespace == c.Namespace
+}
+
+// notFound returns the error of the API server for a missing object
+func notFound(group string, resource string, name string) error {
+	return apierrors.NewNotFound(schema.GroupResource{Group: group, Resource: resource}, name)
+}
+
+// GetPodsByLabels returns a list of pods that match the given labels
+func (c *MemoryClient) GetPodsByLabels(labels map[string]string) (*v1.PodList, error) {
+	selector := apilabels.SelectorFromSet(labels)
+	pods := &v1.PodList{}
+
+	for _, pod := range c.objects.pods {
+		if c.inNamespace(pod.ObjectMeta) && selector.Matches(apilabels.Set(pod.Labels)) {
+			pods.Items = append(pods.Items, pod)
+		}
+	}
+
+	return pods, nil
+}
+
+// GetServiceByName returns a service that matches a given name
+func (c *MemoryClient) GetServiceByName(name string) (*v1.Service, error) {
+	for index, service := range c.objects.services {
+		if service.Name == name && service.Namespace == c.Namespace {
+			return &c.objects.services[index], nil
+		}
+	}
+
+	return nil, notFound("", "services", name)
+}
+
+// GetIngressByName returns an ingress that matches a given name
+func (c *MemoryClient) GetIngressByName(name string) (*networkingv1.Ingress, error) {
+	for index, ingress := range c.objects.ingresses {
+		if ingress.Name == name && ingress.Namespace == c.Namespace {
+			return &c.objects.ingresses[index], nil
+		}
+	}
+
+	return nil, notFound("networking.k8s.io", "ingresses", name)
+}
+
+// GetPodByName returns a pod that matches a given name
+func (c *MemoryClient) GetPodByName(name string) (*v1.Pod, error) {
+	for index, pod := range c.objects.pods {
+		if pod.Name == name && pod.Namespace == c.Namespace {
+			return &c.objects.pods[index], nil
+		}
+	}
+
+	return nil, notFound("", "pods", name)
+}
+
+// ListServices returns the services in the namespace that match a label selector.
+// All namespaces are listed when the client namespace is empty
+func (c *MemoryClient) ListServices(selector string) (*v1.ServiceList, error) {
+	labelSelector, err := apilabels.Parse(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	services := &v1.ServiceList{}
+
+	for _, service := range c.objects.services {
+		if c.inNamespace(service.ObjectMeta) && labelSelector.Matches(apilabels.Set(service.Labels)) {
+			services.Items = append(services.Items, service)
+		}
+	}
+
+	return services, nil
+}
+
+// ListIngresses returns the ingresses in the namespace that match a label selector.
+// All namespaces are listed when the client namespace is empty
+func (c *MemoryClient) ListIngresses(selector string) (*networkingv1.IngressList, error) {
+	labelSelector, err := apilabels.Parse(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	ingresses := &networkingv1.IngressList{}
+
+	for _, ingress := range c.objects.ingresses {
+		if c.inNamespace(ingress.ObjectMeta) && labelSelector.Matches(apilabels.Set(ingress.Labels)) {
+			ingresses.Items = append(ingresses.Items, ingress)
+		}
+	}
+
+	return ingresses, nil
+}
+
+// GetEndpointSlicesByService returns the endpoint slices of the service that matches a given name.
+// When the manifests do not declare any, an endpoint slice is synthesized per pod selected by the
+// service, with the ports the service targets on the pod
+func (c *MemoryClient) GetEndpointSlicesByService(name string) (*discoveryv1.EndpointSliceList, error) {
+	endpointSlices := &discoveryv1.EndpointSliceList{}
+
+	for _, endpointSlice := range c.objects.endpointSlices {
+		if c.inNamespace(endpointSlice.ObjectMeta) && endpointSlice.Labels[discoveryv1.LabelServiceName] == name {
+			endpointSlices.Items = append(endpointSlices.Items, endpointSlice)
+		}
+	}
+
+	if len(endpointSlices.Items) > 0 {
+		return endpointSlices, nil
+	}
+
+	service, err := c.GetServiceByName(name)
+	if apierrors.IsNotFound(err) || len(service.Spec.Selector) == 0 {
+		return endpointSlices, nil
+	}
+
+	pods, err := c.GetPodsByLabels(service.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	for _, pod := range pods.Items {
+		ready := IsPodReady(&pod)
+
+		endpointSlice := discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name + "-" + pod.Name,
+				Namespace: service.Namespace,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: name},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{
+					Addresses:  []string{},
+					Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+					TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: pod.Name, Namespace: pod.Namespace},
+				},
+			},
+		}
+
+		if pod.Status.PodIP != "" {
+			endpointSlice.Endpoints[0].Addresses = []string{pod.Status.PodIP}
+		}
+
+		for _, servicePort := range service.Spec.Ports {
+			port := servicePort.TargetPort.IntVal
+
+			if servicePort.TargetPort.Type == intstr.String {
+				var ok bool
+
+				// Ports whose name is not declared by the pod are not served by it
+				if _, port, ok = findContainerPort(&pod, servicePort.TargetPort.StrVal); !ok {
+					continue
+				}
+			}
+
+			portName := servicePort.Name
+			protocol := servicePort.Protocol
+
+			endpointSlice.Ports = append(endpointSlice.Ports, discoveryv1.EndpointPort{
+				Name:     &portName,
+				Port:     &port,
+				Protocol: &protocol,
+			})
+		}
+
+		endpointSlices.Items = append(endpointSlices.Items, endpointSlice)
+	}
+
+	return endpointSlices, nil
+}
+
+// GetNamespaceByName returns a namespace that matches a given name. Namespaces
+// that are not declared in the manifests are returned with the name label set
+// by the API server only, since they are usually created by the deployment tools
+func (c *MemoryClient) GetNamespaceByName(name string) (*v1.Namespace, error) {
+	for index, namespace := range c.objects.namespaces {
+		if namespace.Name == name {
+			return &c.objects.namespaces[index], nil
+		}
+	}
+
+	return &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"kubernetes.io/metadata.name": name},
+		},
+	}, nil
+}
+
+// GetSecretByName returns a secret that matches a given name
+func (c *MemoryClient) GetSecretByName(name string) (*v1.Secret, error) {
+	for index, secret := range c.objects.secrets {
+		if secret.Name == name && secret.Namespace == c.Namespace {
+			return &c.objects.secrets[index], nil
+		}
+	}
+
+	return nil, notFound("", "secrets", name)
+}
+
+// ListIngressClasses returns the ingress classes of the manifests
+func (c *MemoryClient) ListIngressClasses() (*networkingv1.IngressClassList, error) {
+	return &networkingv1.IngressClassList{Items: c.objects.ingressClasses}, nil
+}
+
+// ListNodes returns the nodes of the manifests
+func (c *MemoryClient) ListNodes() (*v1.NodeList, error) {
+	return &v1.NodeList{Items: c.objects.nodes}, nil
+}
+
+// GetCustomResourceByName returns the custom resource that matches a given name in any of the given API versions
+func (c *MemoryClient) GetCustomResourceByName(resource string, groupVersions []string, name string) (*unstructured.Unstructured, error) {
+	for index, object := range c.objects.customResources {
+		// Cluster scoped resources do not have a namespace
+		namespaced := object.GetNamespace() == c.Namespace || object.GetNamespace() == ""
+
+		if object.GetName() == name && namespaced && isCustomResource(&object, resource, groupVersions) {
+			return &c.objects.customResources[index], nil
+		}
+	}
+
+	group := ""
+	if len(groupVersions) > 0 {
+		group = schema.FromAPIVersionAndKind(groupVersions[0], "").Group
+	}
+
+	return nil, notFound(group, resource, name)
+}
+
+// ListCustomResources returns the custom resources in the namespace that match a label selector in
+// any of the given API versions. All namespaces are listed when the client namespace is empty
+func (c *MemoryClient) ListCustomResources(resource string, groupVersions []string, selector string) (*unstructured.UnstructuredList, error) {
+	labelSelector, err := apilabels.Parse(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &unstructured.UnstructuredList{}
+
+	for _, object := range c.objects.customResources {
+		if c.Namespace != "" && object.GetNamespace() != c.Namespace {
+			continue
+		}
+
+		if isCustomResource(&object, resource, groupVersions) && labelSelector.Matches(apilabels.Set(object.GetLabels())) {
+			list.Items = append(list.Items, object)
+		}
+	}
+
+	return list, nil
+}
+
+// isCustomResource returns whether an object is of the given resource in one of the given API versions
+func isCustomResource(object *unstructured.Unstructured, resource string, groupVersions []string) bool {
+	if kindToResource(object.GetKind()) != resource {
+		return false
+	}
+
+	for _, groupVersion := range groupVersions {
+		if object.GetAPIVersion() == groupVersion {
+			return true
+		}
+	}
+
+	return false
+}
+
+// kindToResource returns the plural resource name of a kind, e.g. httpproxies for HTTPProxy
+// and gateways for Gateway, following the naming of the custom resource definitions
+func kindToResource(kind string) string {
+	resource := strings.ToLower(kind)
+
+	switch {
+	case strings.HasSuffix(resource, "s"):
+		return resource + "es"
+	case strings.HasSuffix(resource, "y") && len(resource) > 1 && !strings.ContainsAny(resource[len(resource)-2:len(resource)-1], "aeiou"):
+		return strings.TrimSuffix(resource, "y") + "ies"
+	}
+
+	return resource + "s"
+}
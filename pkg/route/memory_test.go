@@ -0,0 +1,253 @@
+package route
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// NewFakeMemoryClient returns a MemoryClient that serves the objects of the given manifests
+func NewFakeMemoryClient(t *testing.T, manifests ...string) *MemoryClient {
+	objects := []*unstructured.Unstructured{}
+
+	for _, manifest := range manifests {
+		object := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifest), &object.Object); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		objects = append(objects, object)
+	}
+
+	client, err := NewMemoryClient("default", objects...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return client
+}
+
+// forbiddenClient wraps a client and returns Forbidden errors when reading the given resources,
+// as the API server does for users allowed to read the objects of a namespace only
+type forbiddenClient struct {
+	ClientInterface
+	resources map[string]bool
+}
+
+func (c *forbiddenClient) forbidden(resource string) error {
+	return apierrors.NewForbidden(schema.GroupResource{Resource: resource}, "", errors.New("access denied"))
+}
+
+func (c *forbiddenClient) Namespaced(namespace string) ClientInterface {
+	return &forbiddenClient{ClientInterface: c.ClientInterface.Namespaced(namespace), resources: c.resources}
+}
+
+func (c *forbiddenClient) GetPodsByLabels(labels map[string]string) (*v1.PodList, error) {
+	if c.resources["pods"] {
+		return nil, c.forbidden("pods")
+	}
+
+	return c.ClientInterface.GetPodsByLabels(labels)
+}
+
+func (c *forbiddenClient) GetSecretByName(name string) (*v1.Secret, error) {
+	if c.resources["secrets"] {
+		return nil, c.forbidden("secrets")
+	}
+
+	return c.ClientInterface.GetSecretByName(name)
+}
+
+func (c *forbiddenClient) ListIngressClasses() (*networkingv1.IngressClassList, error) {
+	if c.resources["ingressclasses"] {
+		return nil, c.forbidden("ingressclasses")
+	}
+
+	return c.ClientInterface.ListIngressClasses()
+}
+
+func (c *forbiddenClient) ListNodes() (*v1.NodeList, error) {
+	if c.resources["nodes"] {
+		return nil, c.forbidden("nodes")
+	}
+
+	return c.ClientInterface.ListNodes()
+}
+
+func TestMemoryClientResolveIngress(t *testing.T) {
+
+	client := NewFakeMemoryClient(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: app
+        ports:
+        - name: http
+          containerPort: 8080
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: service-web
+spec:
+  selector:
+    app: web
+  ports:
+  - name: http
+    port: 80
+    targetPort: http
+`, `
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: ingress
+spec:
+  rules:
+  - host: foo.com
+    http:
+      paths:
+      - path: /
+        backend:
+          serviceName: service-web
+          servicePort: 80
+      - path: /missing
+        backend:
+          serviceName: service-missing
+          servicePort: 80
+`)
+
+	port8080 := int32(8080)
+	protocol := "TCP"
+
+	expected := &Route{
+		Name:      "ingress",
+		Namespace: "default",
+		Hosts: []Host{
+			{
+				Host: "foo.com",
+				Paths: []Path{
+					{
+						Path:        "/",
+						BackendPort: "80",
+						Service: &Service{
+							Name:      "service-web",
+							Namespace: "default",
+							Found:     true,
+							Type:      "ClusterIP",
+							Ports: []Port{
+								{
+									Name:       "http",
+									Port:       80,
+									TargetPort: "http",
+									Protocol:   protocol,
+									TargetPorts: []TargetPort{
+										{Pod: "web-0", Found: true, Container: "app", Port: 8080},
+										{Pod: "web-1", Found: true, Container: "app", Port: 8080},
+									},
+								},
+							},
+							Pods: []Pod{{Name: "web-0", Ready: true}, {Name: "web-1", Ready: true}},
+							Endpoints: []Endpoint{
+								{Addresses: []string{}, Ready: true, Pod: "web-0", Ports: []EndpointPort{{Name: "http", Port: port8080, Protocol: protocol}}},
+								{Addresses: []string{}, Ready: true, Pod: "web-1", Ports: []EndpointPort{{Name: "http", Port: port8080, Protocol: protocol}}},
+							},
+						},
+					},
+					{
+						Path:        "/missing",
+						BackendPort: "80",
+						Service:     &Service{Name: "service-missing", Namespace: "default"},
+					},
+				},
+			},
+		},
+	}
+
+	route, err := NewResolver(client, "default").ResolveIngress("ingress")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(route, expected) {
+		t.Errorf("Returned route was incorrect,\ngot:\n%+v\nwant:\n%+v", route, expected)
+	}
+
+	findings, err := NewResolver(client, "default").CheckIngress("ingress")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(findings) != 1 || findings[0].Type != FindingServiceNotFound {
+		t.Errorf("Returned findings were incorrect, got: %+v", findings)
+	}
+}
+
+func TestMemoryClientCustomResources(t *testing.T) {
+
+	client := NewFakeMemoryClient(t, `
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: proxy
+  namespace: team
+spec:
+  virtualhost:
+    fqdn: foo.com
+`)
+
+	if _, err := client.GetCustomResourceByName("httpproxies", ContourGroupVersions, "proxy"); err == nil {
+		t.Errorf("Expected an error for a proxy of another namespace")
+	}
+
+	object, err := client.Namespaced("team").GetCustomResourceByName("httpproxies", ContourGroupVersions, "proxy")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if object.GetName() != "proxy" {
+		t.Errorf("Returned custom resource was incorrect, got: %s", object.GetName())
+	}
+
+	list, err := client.Namespaced("").ListCustomResources("httpproxies", ContourGroupVersions, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(list.Items) != 1 {
+		t.Errorf("Returned custom resources were incorrect, got: %d items", len(list.Items))
+	}
+}
+
+func TestKindToResource(t *testing.T) {
+
+	tests := map[string]string{
+		"HTTPProxy":       "httpproxies",
+		"Gateway":         "gateways",
+		"ServiceEntry":    "serviceentries",
+		"IngressRoute":    "ingressroutes",
+		"DestinationRule": "destinationrules",
+		"IngressClass":    "ingressclasses",
+	}
+
+	for kind, expected := range tests {
+		if resource := kindToResource(kind); resource != expected {
+			t.Errorf("Returned resource of kind %s was incorrect, got: %s, want: %s", kind, resource, expected)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package route
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// nginxAnnotationPrefix is the prefix of the annotations of the NGINX ingress controller
+const nginxAnnotationPrefix = "nginx.ingress.kubernetes.io/"
+
+// Defaults of the NGINX ingress controller CORS annotations
+const (
+	nginxDefaultCORSAllowOrigin  = "*"
+	nginxDefaultCORSAllowMethods = "GET, PUT, POST, DELETE, PATCH, OPTIONS"
+	nginxDefaultCORSAllowHeaders = "DNT,Keep-Alive,User-Agent,X-Requested-With,If-Modified-Since,Cache-Control,Content-Type,Range,Authorization"
+	nginxDefaultCORSMaxAge       = "1728000"
+	nginxDefaultCanaryTotal      = 100
+)
+
+// NGINXBehavior returns the effective behavior of an ingress path decoded from the annotations of
+// the NGINX ingress controller set on its ingress: rewrites, SSL redirects, backend protocol, canary
+// rules, authentication, rate limits, timeouts, CORS and allowed source ranges. Defaults are applied
+// to the settings of the enabled features. No behavior is returned when no annotation is set
+func NGINXBehavior(ingress *networkingv1.Ingress, path string) *Behavior {
+
+	annotation := func(name string) string {
+		return strings.TrimSpace(ingress.Annotations[nginxAnnotationPrefix+name])
+	}
+
+	behavior := &Behavior{}
+
+	if target := annotation("rewrite-target"); target != "" {
+		behavior.Rewrite = nginxRewrite(path, target)
+	}
+
+	if redirect := annotation("ssl-redirect"); redirect != "" {
+		enabled := redirect == "true"
+		behavior.SSLRedirect = &enabled
+	}
+
+	behavior.ForceSSLRedirect = annotation("force-ssl-redirect") == "true"
+	behavior.BackendProtocol = strings.ToUpper(annotation("backend-protocol"))
+
+	if annotation("canary") == "true" {
+		canary := &Canary{
+			Header:        annotation("canary-by-header"),
+			HeaderValue:   annotation("canary-by-header-value"),
+			HeaderPattern: annotation("canary-by-header-pattern"),
+			Cookie:        annotation("canary-by-cookie"),
+		}
+
+		if weight, ok := nginxInt(annotation("canary-weight")); ok {
+			canary.Weight = &weight
+
+			canary.WeightTotal = nginxDefaultCanaryTotal
+			if total, ok := nginxInt(annotation("canary-weight-total")); ok {
+				canary.WeightTotal = total
+			}
+		}
+
+		behavior.Canary = canary
+	}
+
+	if url := annotation("auth-url"); url != "" {
+		behavior.Auth = &Auth{Type: "external", URL: url, SignIn: annotation("auth-signin")}
+	} else if secret := annotation("auth-secret"); secret != "" {
+		authType := annotation("auth-type")
+		if authType == "" {
+			authType = "basic"
+		}
+
+		behavior.Auth = &Auth{Type: authType, Secret: secret, Realm: annotation("auth-realm")}
+	}
+
+	rateLimit := &RateLimit{}
+	rateLimit.RPS, _ = nginxInt(annotation("limit-rps"))
+	rateLimit.RPM, _ = nginxInt(annotation("limit-rpm"))
+	rateLimit.Connections, _ = nginxInt(annotation("limit-connections"))
+	rateLimit.BurstMultiplier, _ = nginxInt(annotation("limit-burst-multiplier"))
+
+	if rateLimit.RPS != 0 || rateLimit.RPM != 0 || rateLimit.Connections != 0 {
+		behavior.RateLimit = rateLimit
+	}
+
+	timeouts := &Timeouts{
+		Connect: nginxSeconds(annotation("proxy-connect-timeout")),
+		Read:    nginxSeconds(annotation("proxy-read-timeout")),
+		Send:    nginxSeconds(annotation("proxy-send-timeout")),
+	}
+
+	if *timeouts != (Timeouts{}) {
+		behavior.Timeouts = timeouts
+	}
+
+	if annotation("enable-cors") == "true" {
+		behavior.CORS = &CORS{
+			AllowOrigin:      nginxDefault(annotation("cors-allow-origin"), nginxDefaultCORSAllowOrigin),
+			AllowMethods:     nginxDefault(annotation("cors-allow-methods"), nginxDefaultCORSAllowMethods),
+			AllowHeaders:     nginxDefault(annotation("cors-allow-headers"), nginxDefaultCORSAllowHeaders),
+			AllowCredentials: annotation("cors-allow-credentials") != "false",
+			MaxAge:           nginxSeconds(nginxDefault(annotation("cors-max-age"), nginxDefaultCORSMaxAge)),
+		}
+	}
+
+	// The whitelist annotation was renamed to allowlist in newer controller versions
+	ranges := nginxDefault(annotation("allowlist-source-range"), annotation("whitelist-source-range"))
+	for _, sourceRange := range strings.Split(ranges, ",") {
+		if sourceRange = strings.TrimSpace(sourceRange); sourceRange != "" {
+			behavior.AllowedSourceRanges = append(behavior.AllowedSourceRanges, sourceRange)
+		}
+	}
+
+	if isEmptyBehavior(behavior) {
+		return nil
+	}
+
+	return behavior
+}
+
+// NGINXDefaultBackendBehavior returns the behavior of the default backend of an ingress decoded from the
+// annotations of the NGINX ingress controller. Rewrites apply to the capture groups of rule paths and canary
+// rules to the paths of the ingress they are merged into, so only the settings of the whole server apply
+func NGINXDefaultBackendBehavior(ingress *networkingv1.Ingress) *Behavior {
+
+	behavior := NGINXBehavior(ingress, "")
+	if behavior == nil {
+		return nil
+	}
+
+	behavior.Rewrite = ""
+	behavior.Canary = nil
+
+	if isEmptyBehavior(behavior) {
+		return nil
+	}
+
+	return behavior
+}
+
+// NGINXUseRegex returns whether the NGINX ingress controller matches the paths of an ingress as
+// regular expressions, which the use-regex annotation enables and the rewrite-target one implies
+func NGINXUseRegex(ingress *networkingv1.Ingress) bool {
+	annotation := func(name string) string {
+		return strings.TrimSpace(ingress.Annotations[nginxAnnotationPrefix+name])
+	}
+
+	return annotation("use-regex") == "true" || annotation("rewrite-target") != ""
+}
+
+// MatchNGINXRegexPath returns whether a path of an ingress with regular expression paths matches
+// a request path. The controller anchors the expression at the start of the request path and
+// matches it case insensitively. Invalid expressions do not match any path
+func MatchNGINXRegexPath(path string, requestPath string) bool {
+	if requestPath == "" {
+		requestPath = "/"
+	}
+
+	expression, err := regexp.Compile("(?i)^" + path)
+	if err != nil {
+		return false
+	}
+
+	return expression.MatchString(requestPath)
+}
+
+// nginxRewriteGroupReference matches the references of a rewrite target to the capture groups of its path
+var nginxRewriteGroupReference = regexp.MustCompile(`\$([1-9])`)
+
+// nginxRewrite returns the rewrite of a path to a target, such as "/api(/|$)(.*) -> /$2 ($2 = (.*))",
+// with each capture group of the path the target refers to. Groups missing from the path are
+// rewritten to an empty string by the controller, so they are shown as <none>
+func nginxRewrite(path string, target string) string {
+
+	rewrite := path + " -> " + target
+
+	groups := regexCaptureGroups(path)
+	references := []string{}
+	seen := map[string]bool{}
+
+	for _, match := range nginxRewriteGroupReference.FindAllStringSubmatch(target, -1) {
+		if seen[match[0]] {
+			continue
+		}
+
+		seen[match[0]] = true
+
+		group := "<none>"
+		if index, _ := strconv.Atoi(match[1]); index <= len(groups) {
+			group = groups[index-1]
+		}
+
+		references = append(references, match[0]+" = "+group)
+	}
+
+	if len(references) == 0 {
+		return rewrite
+	}
+
+	return rewrite + " (" + strings.Join(references, ", ") + ")"
+}
+
+// regexCaptureGroups returns the source of the capture groups of a regular expression, in the
+// order they are numbered. No groups are returned for invalid expressions
+func regexCaptureGroups(expression string) []string {
+
+	if _, err := regexp.Compile(expression); err != nil {
+		return nil
+	}
+
+	groups := []string{}
+
+	// starts holds the position and number of the open groups, -1 for non-capturing ones
+	type start struct{ position, number int }
+	starts := []start{}
+
+	for i := 0; i < len(expression); i++ {
+		switch expression[i] {
+		case '\\':
+			i++
+
+		case '[':
+			// A closing bracket right after the opening one or its negation is a literal
+			i++
+			if i < len(expression) && expression[i] == '^' {
+				i++
+			}
+			if i < len(expression) && expression[i] == ']' {
+				i++
+			}
+			for ; i < len(expression) && expression[i] != ']'; i++ {
+				if expression[i] == '\\' {
+					i++
+				}
+			}
+
+		case '(':
+			number := -1
+
+			rest := expression[i+1:]
+			if !strings.HasPrefix(rest, "?") || strings.HasPrefix(rest, "?P<") || strings.HasPrefix(rest, "?<") {
+				number = len(groups)
+				groups = append(groups, "")
+			}
+
+			starts = append(starts, start{position: i, number: number})
+
+		case ')':
+			open := starts[len(starts)-1]
+			starts = starts[:len(starts)-1]
+
+			if open.number >= 0 {
+				groups[open.number] = expression[open.position : i+1]
+			}
+		}
+	}
+
+	return groups
+}
+
+// isEmptyBehavior returns whether no setting of a behavior is set
+func isEmptyBehavior(behavior *Behavior) bool {
+	return behavior.Rewrite == "" && behavior.SSLRedirect == nil && !behavior.ForceSSLRedirect &&
+		behavior.BackendProtocol == "" && behavior.Canary == nil && behavior.Auth == nil &&
+		behavior.RateLimit == nil && behavior.Timeouts == nil && behavior.CORS == nil &&
+		len(behavior.AllowedSourceRanges) == 0
+}
+
+// nginxInt returns the integer value of an annotation and whether it is a valid integer
+func nginxInt(value string) (int32, bool) {
+	number, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return int32(number), true
+}
+
+// nginxSeconds returns the duration of an annotation set in seconds, such as "60s" for "60"
+func nginxSeconds(value string) string {
+	if _, ok := nginxInt(value); ok {
+		return value + "s"
+	}
+
+	return value
+}
+
+// nginxDefault returns the value of an annotation, or the given default when it is not set
+func nginxDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
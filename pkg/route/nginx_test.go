@@ -0,0 +1,260 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNGINXBehavior(t *testing.T) {
+
+	enabled := true
+	disabled := false
+	weight := int32(20)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		path        string
+		expected    *Behavior
+	}{
+		{
+			"no annotations",
+			map[string]string{"kubernetes.io/ingress.class": "nginx"},
+			"/",
+			nil,
+		},
+		{
+			"rewrite with capture groups",
+			map[string]string{
+				"nginx.ingress.kubernetes.io/rewrite-target":   "/$2",
+				"nginx.ingress.kubernetes.io/ssl-redirect":     "false",
+				"nginx.ingress.kubernetes.io/backend-protocol": "https",
+			},
+			"/api(/|$)(.*)",
+			&Behavior{Rewrite: "/api(/|$)(.*) -> /$2 ($2 = (.*))", SSLRedirect: &disabled, BackendProtocol: "HTTPS"},
+		},
+		{
+			"rewrite with several capture groups",
+			map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/$3"},
+			"/(foo)(/|$)(.*)",
+			&Behavior{Rewrite: "/(foo)(/|$)(.*) -> /$3 ($3 = (.*))"},
+		},
+		{
+			"rewrite with nested, non-capturing and missing groups",
+			map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/$2/$1/$2/$4"},
+			"/(?:v1|v2)/(users/([0-9]+))[(]x[)]",
+			&Behavior{Rewrite: "/(?:v1|v2)/(users/([0-9]+))[(]x[)] -> /$2/$1/$2/$4 ($2 = ([0-9]+), $1 = (users/([0-9]+)), $4 = <none>)"},
+		},
+		{
+			"rewrite without capture groups",
+			map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/"},
+			"/api",
+			&Behavior{Rewrite: "/api -> /"},
+		},
+		{
+			"canary by weight and header",
+			map[string]string{
+				"nginx.ingress.kubernetes.io/canary":                 "true",
+				"nginx.ingress.kubernetes.io/canary-weight":          "20",
+				"nginx.ingress.kubernetes.io/canary-by-header":       "X-Canary",
+				"nginx.ingress.kubernetes.io/canary-by-header-value": "on",
+				"nginx.ingress.kubernetes.io/canary-by-cookie":       "canary",
+			},
+			"/",
+			&Behavior{Canary: &Canary{Weight: &weight, WeightTotal: 100, Header: "X-Canary", HeaderValue: "on", Cookie: "canary"}},
+		},
+		{
+			"canary disabled",
+			map[string]string{
+				"nginx.ingress.kubernetes.io/canary":        "false",
+				"nginx.ingress.kubernetes.io/canary-weight": "20",
+			},
+			"/",
+			nil,
+		},
+		{
+			"external auth, limits and timeouts",
+			map[string]string{
+				"nginx.ingress.kubernetes.io/auth-url":               "https://auth.example.com/oauth2/auth",
+				"nginx.ingress.kubernetes.io/auth-signin":            "https://auth.example.com/oauth2/start",
+				"nginx.ingress.kubernetes.io/limit-rps":              "10",
+				"nginx.ingress.kubernetes.io/limit-burst-multiplier": "3",
+				"nginx.ingress.kubernetes.io/proxy-read-timeout":     "120",
+				"nginx.ingress.kubernetes.io/proxy-connect-timeout":  "5",
+				"nginx.ingress.kubernetes.io/force-ssl-redirect":     "true",
+			},
+			"/",
+			&Behavior{
+				ForceSSLRedirect: true,
+				Auth:             &Auth{Type: "external", URL: "https://auth.example.com/oauth2/auth", SignIn: "https://auth.example.com/oauth2/start"},
+				RateLimit:        &RateLimit{RPS: 10, BurstMultiplier: 3},
+				Timeouts:         &Timeouts{Connect: "5s", Read: "120s"},
+			},
+		},
+		{
+			"basic auth, CORS defaults and source ranges",
+			map[string]string{
+				"nginx.ingress.kubernetes.io/auth-secret":            "basic-auth",
+				"nginx.ingress.kubernetes.io/auth-realm":             "Authentication Required",
+				"nginx.ingress.kubernetes.io/enable-cors":            "true",
+				"nginx.ingress.kubernetes.io/cors-allow-origin":      "https://app.example.com",
+				"nginx.ingress.kubernetes.io/whitelist-source-range": "10.0.0.0/8, 192.168.0.0/16",
+				"nginx.ingress.kubernetes.io/ssl-redirect":           "true",
+			},
+			"/",
+			&Behavior{
+				SSLRedirect: &enabled,
+				Auth:        &Auth{Type: "basic", Secret: "basic-auth", Realm: "Authentication Required"},
+				CORS: &CORS{
+					AllowOrigin:      "https://app.example.com",
+					AllowMethods:     nginxDefaultCORSAllowMethods,
+					AllowHeaders:     nginxDefaultCORSAllowHeaders,
+					AllowCredentials: true,
+					MaxAge:           "1728000s",
+				},
+				AllowedSourceRanges: []string{"10.0.0.0/8", "192.168.0.0/16"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+
+		ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+
+		behavior := NGINXBehavior(ingress, test.path)
+
+		if !reflect.DeepEqual(behavior, test.expected) {
+			t.Errorf("Returned behavior for %s was incorrect, got: %+v, want: %+v", test.name, behavior, test.expected)
+		}
+	}
+}
+
+func TestNGINXDefaultBackendBehavior(t *testing.T) {
+
+	enabled := true
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    *Behavior
+	}{
+		{
+			"path annotations only",
+			map[string]string{
+				"nginx.ingress.kubernetes.io/rewrite-target": "/$2",
+				"nginx.ingress.kubernetes.io/canary":         "true",
+			},
+			nil,
+		},
+		{
+			"server annotations",
+			map[string]string{
+				"nginx.ingress.kubernetes.io/rewrite-target": "/$2",
+				"nginx.ingress.kubernetes.io/ssl-redirect":   "true",
+				"nginx.ingress.kubernetes.io/auth-url":       "https://auth.example.com/verify",
+			},
+			&Behavior{SSLRedirect: &enabled, Auth: &Auth{Type: "external", URL: "https://auth.example.com/verify"}},
+		},
+	}
+
+	for _, test := range tests {
+
+		ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+
+		behavior := NGINXDefaultBackendBehavior(ingress)
+
+		if !reflect.DeepEqual(behavior, test.expected) {
+			t.Errorf("Returned behavior for %s was incorrect, got: %+v, want: %+v", test.name, behavior, test.expected)
+		}
+	}
+}
+
+func TestMatchNGINXRegexPath(t *testing.T) {
+
+	tests := []struct {
+		path          string
+		requestPath   string
+		expectedMatch bool
+	}{
+		{"/api(/|$)(.*)", "/api/users", true},
+		{"/api(/|$)(.*)", "/api", true},
+		{"/api(/|$)(.*)", "/apis", false},
+		{"/API/v[0-9]+", "/api/v2/users", true},
+		{"/users/[0-9]+$", "/users/42/posts", false},
+		{"/(invalid", "/(invalid", false},
+		{"/", "", true},
+	}
+
+	for _, test := range tests {
+		if match := MatchNGINXRegexPath(test.path, test.requestPath); match != test.expectedMatch {
+			t.Errorf("Returned match for %s and %s was incorrect, got: %t, want: %t", test.path, test.requestPath, match, test.expectedMatch)
+		}
+	}
+}
+
+func TestResolverResolveURLNGINXRegex(t *testing.T) {
+
+	client := NewFakeMemoryClient(t, `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ingress-regex
+  annotations:
+    nginx.ingress.kubernetes.io/use-regex: "true"
+spec:
+  rules:
+  - host: foo.com
+    http:
+      paths:
+      - path: /users/[0-9]+
+        pathType: ImplementationSpecific
+        backend:
+          service:
+            name: service-users
+            port:
+              number: 80
+`, `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ingress-plain
+spec:
+  rules:
+  - host: bar.com
+    http:
+      paths:
+      - path: /users/[0-9]+
+        pathType: ImplementationSpecific
+        backend:
+          service:
+            name: service-users
+            port:
+              number: 80
+`)
+
+	tests := []struct {
+		url             string
+		expectedIngress string
+	}{
+		{"http://foo.com/users/42", "ingress-regex"},
+		{"http://foo.com/users/me", ""},
+		{"http://bar.com/users/42", ""},
+	}
+
+	for _, test := range tests {
+
+		ingress := ""
+
+		urlRoute, err := NewResolver(client, "default").ResolveURL(test.url, false)
+		if err == nil {
+			ingress = urlRoute.Match.Ingress
+		}
+
+		if ingress != test.expectedIngress {
+			t.Errorf("Returned ingress for %s was incorrect, got: %q, want: %q", test.url, ingress, test.expectedIngress)
+		}
+	}
+}
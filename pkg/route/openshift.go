@@ -0,0 +1,147 @@
+package route
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// KindOpenShiftRoute is the kind of OpenShift routes
+const KindOpenShiftRoute = "Route"
+
+// openShiftRouteObject declares the fields of an OpenShift route used by the resolver
+type openShiftRouteObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Host              string                   `json:"host,omitempty"`
+		Path              string                   `json:"path,omitempty"`
+		To                openShiftRouteBackend    `json:"to"`
+		AlternateBackends []openShiftRouteBackend  `json:"alternateBackends,omitempty"`
+		Port              *openShiftRoutePort      `json:"port,omitempty"`
+		TLS               *openShiftRouteTLSConfig `json:"tls,omitempty"`
+	} `json:"spec"`
+}
+
+type openShiftRouteBackend struct {
+	Kind   string `json:"kind,omitempty"`
+	Name   string `json:"name"`
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+type openShiftRoutePort struct {
+	TargetPort intstr.IntOrString `json:"targetPort"`
+}
+
+type openShiftRouteTLSConfig struct {
+	Termination                   string `json:"termination"`
+	InsecureEdgeTerminationPolicy string `json:"insecureEdgeTerminationPolicy,omitempty"`
+}
+
+// ResolveOpenShiftRoute returns the route graph of the OpenShift route that matches a given name.
+// The primary service and the alternate backends are each resolved into a weighted path
+func (r *Resolver) ResolveOpenShiftRoute(name string) (*Route, error) {
+
+	object, err := r.Client.GetCustomResourceByName("routes", OpenShiftRouteGroupVersions, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if object == nil {
+		return nil, fmt.Errorf("route %q not found", name)
+	}
+
+	openShiftRoute := &openShiftRouteObject{}
+	if err := fromUnstructured(object, openShiftRoute); err != nil {
+		return nil, err
+	}
+
+	host := Host{
+		Host:  openShiftRoute.Spec.Host,
+		Paths: []Path{},
+	}
+
+	if tls := openShiftRoute.Spec.TLS; tls != nil {
+		host.TLS = &TLS{
+			Termination:                   tls.Termination,
+			InsecureEdgeTerminationPolicy: tls.InsecureEdgeTerminationPolicy,
+		}
+	}
+
+	// Routes without a path match every path of the host
+	path := openShiftRoute.Spec.Path
+	if path == "" {
+		path = "/"
+	}
+
+	targetPort := ""
+	if openShiftRoute.Spec.Port != nil {
+		targetPort = openShiftRoute.Spec.Port.TargetPort.String()
+	}
+
+	backends := append([]openShiftRouteBackend{openShiftRoute.Spec.To}, openShiftRoute.Spec.AlternateBackends...)
+
+	for _, backend := range backends {
+
+		// Backends without a weight get the default weight of 100
+		weight := int32(100)
+		if backend.Weight != nil {
+			weight = *backend.Weight
+		}
+
+		service, err := r.resolveOpenShiftRouteBackend(backend, targetPort)
+		if err != nil {
+			return nil, err
+		}
+
+		host.Paths = append(host.Paths, Path{
+			Path:        path,
+			PathType:    "Prefix",
+			BackendPort: targetPort,
+			Weight:      &weight,
+			Service:     service,
+		})
+	}
+
+	return &Route{
+		Kind:      KindOpenShiftRoute,
+		Name:      openShiftRoute.Name,
+		Namespace: r.Namespace,
+		Hosts:     []Host{host},
+	}, nil
+}
+
+// resolveOpenShiftRouteBackend returns the service of a route backend. The target port of a route
+// is either the name of a service port or the target port of a service port, so the service is
+// narrowed to the ports that it maps. Services without such a port keep all their ports
+func (r *Resolver) resolveOpenShiftRouteBackend(backend openShiftRouteBackend, targetPort string) (*Service, error) {
+
+	if backend.Kind != "" && backend.Kind != "Service" {
+		return &Service{Name: backend.Name, Namespace: r.Namespace, Type: backend.Kind}, nil
+	}
+
+	service, err := r.resolveBackendService(backend.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetPort == "" || !service.Found {
+		return service, nil
+	}
+
+	ports := []Port{}
+
+	for _, port := range service.Ports {
+		if port.Name == targetPort || port.TargetPort == targetPort {
+			ports = append(ports, port)
+		}
+	}
+
+	if len(ports) > 0 {
+		narrowed := *service
+		narrowed.Ports = ports
+		service = &narrowed
+	}
+
+	return service, nil
+}
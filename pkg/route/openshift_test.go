@@ -0,0 +1,103 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestResolverResolveOpenShiftRoute(t *testing.T) {
+
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: OpenShiftRouteV1,
+			APIResources: []metav1.APIResource{{Name: "routes", Kind: "Route", Namespaced: true}},
+		},
+	}
+
+	objects := []runtime.Object{
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-foo", Namespace: "default"},
+			Spec: v1.ServiceSpec{
+				Type: v1.ServiceTypeClusterIP,
+				Ports: []v1.ServicePort{
+					{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080), Protocol: v1.ProtocolTCP},
+					{Name: "metrics", Port: 9090, TargetPort: intstr.FromInt(9090), Protocol: v1.ProtocolTCP},
+				},
+			},
+		},
+	}
+
+	client := NewFakeCustomResourceClient(t, resources, objects, `
+apiVersion: route.openshift.io/v1
+kind: Route
+metadata:
+  name: route-foo
+  namespace: default
+spec:
+  host: foo.apps.example.com
+  to:
+    kind: Service
+    name: service-foo
+    weight: 90
+  alternateBackends:
+  - kind: Service
+    name: service-missing
+    weight: 10
+  port:
+    targetPort: http
+  tls:
+    termination: edge
+    insecureEdgeTerminationPolicy: Redirect
+`)
+
+	weight90 := int32(90)
+	weight10 := int32(10)
+
+	expected := &Route{
+		Kind:      "Route",
+		Name:      "route-foo",
+		Namespace: "default",
+		Hosts: []Host{
+			{
+				Host: "foo.apps.example.com",
+				TLS:  &TLS{Termination: "edge", InsecureEdgeTerminationPolicy: "Redirect"},
+				Paths: []Path{
+					{
+						Path:        "/",
+						PathType:    "Prefix",
+						BackendPort: "http",
+						Weight:      &weight90,
+						Service: &Service{
+							Name:      "service-foo",
+							Namespace: "default",
+							Found:     true,
+							Type:      "ClusterIP",
+							Ports:     []Port{{Name: "http", Port: 80, TargetPort: "8080", Protocol: "TCP"}},
+						},
+					},
+					{
+						Path:        "/",
+						PathType:    "Prefix",
+						BackendPort: "http",
+						Weight:      &weight10,
+						Service:     &Service{Name: "service-missing", Namespace: "default"},
+					},
+				},
+			},
+		},
+	}
+
+	route, err := NewResolver(client, "default").ResolveOpenShiftRoute("route-foo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(route, expected) {
+		t.Errorf("Returned route was incorrect,\ngot:\n%+v\nwant:\n%+v", route, expected)
+	}
+}
@@ -0,0 +1,814 @@
+package route
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Resolver builds route graphs from the objects returned by a client.
+// PodDetails makes it read the details of the pods backing services
+type Resolver struct {
+	Client     ClientInterface
+	Namespace  string
+	PodDetails bool
+
+	// cache holds the cluster scoped objects read by the resolver, shared with the resolvers of other namespaces
+	cache *clusterCache
+}
+
+// clusterCache defines the cluster scoped objects listed once per resolver: the nodes of the services
+// exposed on node ports, the ingress classes and the pods of their controllers per controller.
+// Forbidden errors are cached along with the lists, as they do not change between reads either
+type clusterCache struct {
+	nodesListed bool
+	nodes       []v1.Node
+
+	ingressClassesListed bool
+	ingressClasses       *networkingv1.IngressClassList
+	ingressClassesErr    error
+
+	controllerPods map[string]cachedPodList
+}
+
+// cachedPodList defines a pod list read once, along with the error returned when reading it
+type cachedPodList struct {
+	pods *v1.PodList
+	err  error
+}
+
+// NewResolver returns a new Resolver struct
+func NewResolver(client ClientInterface, namespace string) *Resolver {
+	return &Resolver{
+		Client:    client,
+		Namespace: namespace,
+		cache:     &clusterCache{},
+	}
+}
+
+// ResolveIngress returns the route graph of the ingress that matches a given name
+func (r *Resolver) ResolveIngress(name string) (*Route, error) {
+
+	ingress, err := r.Client.GetIngressByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if ingress == nil {
+		return nil, fmt.Errorf("ingress %q not found", name)
+	}
+
+	return r.newRoute(ingress)
+}
+
+// ResolveIngresses returns the route graphs of the ingresses that match the given names
+// or, when no name is given, of all the ingresses that match a label selector.
+// Ingresses are listed across all namespaces when the resolver namespace is empty
+func (r *Resolver) ResolveIngresses(names []string, selector string) ([]*Route, error) {
+
+	routes := []*Route{}
+
+	if len(names) > 0 {
+		for _, name := range names {
+			route, err := r.ResolveIngress(name)
+			if err != nil {
+				return nil, err
+			}
+
+			routes = append(routes, route)
+		}
+
+		return routes, nil
+	}
+
+	ingresses, err := r.Client.ListIngresses(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(ingresses.Items, func(i, j int) bool {
+		return objectKey(ingresses.Items[i].ObjectMeta) < objectKey(ingresses.Items[j].ObjectMeta)
+	})
+
+	for index := range ingresses.Items {
+		ingress := &ingresses.Items[index]
+
+		route, err := r.forNamespace(ingress.Namespace).newRoute(ingress)
+		if err != nil {
+			return nil, err
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+func (r *Resolver) newRoute(ingress *networkingv1.Ingress) (*Route, error) {
+
+	route := &Route{
+		Name:      ingress.Name,
+		Namespace: r.Namespace,
+		Exposure:  ingressExposure(ingress),
+		Hosts:     []Host{},
+	}
+
+	ingressClass, findings, err := r.resolveIngressClass(ingress)
+	if err != nil {
+		return nil, err
+	}
+
+	route.IngressClass = ingressClass
+	for _, finding := range findings {
+		ingressClass.Warnings = append(ingressClass.Warnings, finding.Message)
+	}
+
+	secrets := map[string]*Secret{}
+
+	for _, rule := range ingress.Spec.Rules {
+
+		host := Host{
+			Host:  rule.Host,
+			Paths: []Path{},
+		}
+
+		tls, findings, err := r.resolveIngressTLS(ingress, rule.Host, secrets)
+		if err != nil {
+			return nil, err
+		}
+
+		host.TLS = tls
+		for _, finding := range findings {
+			host.Warnings = append(host.Warnings, finding.Message)
+		}
+
+		// Rules without HTTP paths send the requests of their host to the default backend
+		if rule.HTTP == nil {
+			route.Hosts = append(route.Hosts, host)
+			continue
+		}
+
+		for _, ingressPath := range rule.HTTP.Paths {
+
+			service, err := r.resolveIngressBackend(ingressPath.Backend)
+			if err != nil {
+				return nil, err
+			}
+
+			path := Path{
+				Path:        ingressPath.Path,
+				BackendPort: IngressBackendPortToString(ingressPath.Backend),
+				Behavior:    NGINXBehavior(ingress, ingressPath.Path),
+				Service:     service,
+			}
+
+			if ingressPath.PathType != nil {
+				path.PathType = string(*ingressPath.PathType)
+			}
+
+			host.Paths = append(host.Paths, path)
+		}
+
+		route.Hosts = append(route.Hosts, host)
+	}
+
+	if backend := ingress.Spec.DefaultBackend; backend != nil {
+
+		service, err := r.resolveIngressBackend(*backend)
+		if err != nil {
+			return nil, err
+		}
+
+		route.DefaultBackend = &Path{
+			BackendPort: IngressBackendPortToString(*backend),
+			Behavior:    NGINXDefaultBackendBehavior(ingress),
+			Service:     service,
+		}
+	}
+
+	return route, nil
+}
+
+// ResolveService returns the route graph of the service that matches a given name
+func (r *Resolver) ResolveService(name string) (*Service, error) {
+
+	service, err := r.Client.GetServiceByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if service == nil {
+		return nil, fmt.Errorf("service %q not found", name)
+	}
+
+	return r.newService(service)
+}
+
+// ResolveServices returns the route graphs of the services that match the given names
+// or, when no name is given, of all the services that match a label selector.
+// Services are listed across all namespaces when the resolver namespace is empty
+func (r *Resolver) ResolveServices(names []string, selector string) ([]*Service, error) {
+
+	routes := []*Service{}
+
+	if len(names) > 0 {
+		for _, name := range names {
+			route, err := r.ResolveService(name)
+			if err != nil {
+				return nil, err
+			}
+
+			routes = append(routes, route)
+		}
+
+		return routes, nil
+	}
+
+	services, err := r.Client.ListServices(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(services.Items, func(i, j int) bool {
+		return objectKey(services.Items[i].ObjectMeta) < objectKey(services.Items[j].ObjectMeta)
+	})
+
+	for index := range services.Items {
+		service := &services.Items[index]
+
+		route, err := r.forNamespace(service.Namespace).newService(service)
+		if err != nil {
+			return nil, err
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+// ResetCache drops the cluster scoped objects read by the resolver and the resolvers of other
+// namespaces, so that they are read again, such as when a watcher resolves the routes again
+func (r *Resolver) ResetCache() {
+	*r.cache = clusterCache{}
+}
+
+// forNamespace returns the resolver to use for objects of the given namespace
+func (r *Resolver) forNamespace(namespace string) *Resolver {
+	if namespace == r.Namespace || namespace == "" {
+		return r
+	}
+
+	resolver := NewResolver(r.Client.Namespaced(namespace), namespace)
+	resolver.PodDetails = r.PodDetails
+	resolver.cache = r.cache
+
+	return resolver
+}
+
+func objectKey(meta metav1.ObjectMeta) string {
+	return meta.Namespace + "/" + meta.Name
+}
+
+// ResolvePod returns the services that select the pod that matches a given name,
+// along with the ingress paths and default backends that route traffic to those services
+func (r *Resolver) ResolvePod(name string) (*PodRoute, error) {
+
+	pod, err := r.Client.GetPodByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if pod == nil {
+		return nil, fmt.Errorf("pod %q not found", name)
+	}
+
+	services, err := r.Client.ListServices("")
+	if err != nil {
+		return nil, err
+	}
+
+	ingresses, err := r.Client.ListIngresses("")
+	if err != nil {
+		return nil, err
+	}
+
+	route := &PodRoute{
+		Name:      pod.Name,
+		Namespace: r.Namespace,
+		Services:  []PodService{},
+	}
+
+	for _, service := range services.Items {
+
+		// Services without selector do not select pods by labels
+		if len(service.Spec.Selector) == 0 {
+			continue
+		}
+
+		if !apilabels.SelectorFromSet(service.Spec.Selector).Matches(apilabels.Set(pod.Labels)) {
+			continue
+		}
+
+		podService := PodService{
+			Name:  service.Name,
+			Type:  ServiceTypeToString(service.Spec.Type),
+			Ports: newPorts(service.Spec.Ports),
+		}
+
+		for _, ingress := range ingresses.Items {
+			for _, rule := range ingress.Spec.Rules {
+				if rule.HTTP == nil {
+					continue
+				}
+
+				for _, ingressPath := range rule.HTTP.Paths {
+					if IngressBackendServiceName(ingressPath.Backend) != service.Name {
+						continue
+					}
+
+					path := IngressPath{
+						Ingress:     ingress.Name,
+						Host:        rule.Host,
+						Path:        ingressPath.Path,
+						BackendPort: IngressBackendPortToString(ingressPath.Backend),
+					}
+
+					if ingressPath.PathType != nil {
+						path.PathType = string(*ingressPath.PathType)
+					}
+
+					podService.Backends = append(podService.Backends, path)
+				}
+			}
+
+			if backend := ingress.Spec.DefaultBackend; backend != nil && IngressBackendServiceName(*backend) == service.Name {
+				podService.Backends = append(podService.Backends, IngressPath{
+					Ingress:        ingress.Name,
+					DefaultBackend: true,
+					BackendPort:    IngressBackendPortToString(*backend),
+				})
+			}
+		}
+
+		route.Services = append(route.Services, podService)
+	}
+
+	return route, nil
+}
+
+// ResolveURL returns the ingress path that serves a URL following the Kubernetes
+// matching rules: exact hosts win over wildcard hosts, which win over rules
+// without host, then the longest path wins and Exact paths win over Prefix paths.
+// Default backends of the ingresses whose rules match the host, or without rules, serve
+// the URLs that match no path of the host, so they have the lowest precedence of the host.
+// Paths of ingresses with NGINX regular expression paths are matched as regular expressions.
+// When candidates is true, the other matching ingress paths are returned as well
+func (r *Resolver) ResolveURL(rawURL string, candidates bool) (*URLRoute, error) {
+
+	requestURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if requestURL.Host == "" {
+		return nil, fmt.Errorf("URL %q does not have a host", rawURL)
+	}
+
+	route := &URLRoute{
+		URL:  rawURL,
+		Host: requestURL.Hostname(),
+		Path: requestURL.Path,
+	}
+
+	if route.Path == "" {
+		route.Path = "/"
+	}
+
+	ingresses, err := r.Client.ListIngresses("")
+	if err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		candidate      URLCandidate
+		backend        networkingv1.IngressBackend
+		defaultBackend bool
+		hostPriority   int
+		pathLength     int
+		typePriority   int
+	}
+
+	matches := []match{}
+
+	for index := range ingresses.Items {
+		ingress := &ingresses.Items[index]
+		useRegex := NGINXUseRegex(ingress)
+
+		for _, rule := range ingress.Spec.Rules {
+
+			hostPriority := MatchHost(rule.Host, route.Host)
+			if hostPriority == HostMatchNone || rule.HTTP == nil {
+				continue
+			}
+
+			for _, ingressPath := range rule.HTTP.Paths {
+
+				pathType := string(networkingv1.PathTypeImplementationSpecific)
+				if ingressPath.PathType != nil {
+					pathType = string(*ingressPath.PathType)
+				}
+
+				matched := MatchPath(pathType, ingressPath.Path, route.Path)
+				if useRegex && pathType != string(networkingv1.PathTypeExact) {
+					matched = MatchNGINXRegexPath(ingressPath.Path, route.Path)
+				}
+
+				if !matched {
+					continue
+				}
+
+				matches = append(matches, match{
+					candidate: URLCandidate{
+						Ingress:     ingress.Name,
+						Host:        rule.Host,
+						Path:        ingressPath.Path,
+						PathType:    pathType,
+						BackendPort: IngressBackendPortToString(ingressPath.Backend),
+					},
+					backend:      ingressPath.Backend,
+					hostPriority: hostPriority,
+					pathLength:   len(strings.Join(splitPath(ingressPath.Path), "/")),
+					typePriority: PathTypePriority(pathType),
+				})
+			}
+		}
+
+		if backend := ingress.Spec.DefaultBackend; backend != nil {
+
+			hostPriority := HostMatchNone
+			if len(ingress.Spec.Rules) == 0 {
+				hostPriority = HostMatchAny
+			}
+
+			for _, rule := range ingress.Spec.Rules {
+				if priority := MatchHost(rule.Host, route.Host); priority > hostPriority {
+					hostPriority = priority
+				}
+			}
+
+			if hostPriority != HostMatchNone {
+				matches = append(matches, match{
+					candidate: URLCandidate{
+						Ingress:        ingress.Name,
+						DefaultBackend: true,
+						BackendPort:    IngressBackendPortToString(*backend),
+					},
+					backend:        *backend,
+					defaultBackend: true,
+					hostPriority:   hostPriority,
+				})
+			}
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no ingress path matches URL %q", rawURL)
+	}
+
+	// Controllers select the server of a host first, then the paths of the server
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].hostPriority != matches[j].hostPriority {
+			return matches[i].hostPriority > matches[j].hostPriority
+		}
+		if matches[i].defaultBackend != matches[j].defaultBackend {
+			return !matches[i].defaultBackend
+		}
+		if matches[i].pathLength != matches[j].pathLength {
+			return matches[i].pathLength > matches[j].pathLength
+		}
+		return matches[i].typePriority > matches[j].typePriority
+	})
+
+	for index, match := range matches {
+		if index > 0 && !candidates {
+			break
+		}
+
+		service, err := r.resolveIngressBackend(match.backend)
+		if err != nil {
+			return nil, err
+		}
+
+		candidate := match.candidate
+		candidate.Service = service
+
+		if index == 0 {
+			route.Match = &candidate
+		} else {
+			route.Candidates = append(route.Candidates, candidate)
+		}
+	}
+
+	return route, nil
+}
+
+// resolveIngressBackend returns the route graph of the target of an ingress backend.
+// Resource backends are not resolved, and are returned with the Resource type
+func (r *Resolver) resolveIngressBackend(backend networkingv1.IngressBackend) (*Service, error) {
+	if backend.Resource != nil {
+		return &Service{
+			Name:      IngressBackendResourceToString(backend),
+			Namespace: r.Namespace,
+			Type:      BackendTypeResource,
+		}, nil
+	}
+
+	return r.resolveBackendService(IngressBackendServiceName(backend))
+}
+
+// resolveBackendService returns the route graph of a service referenced by
+// another object. A service that does not exist is returned with Found set to false
+func (r *Resolver) resolveBackendService(name string) (*Service, error) {
+
+	service, err := r.Client.GetServiceByName(name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if err != nil || service == nil {
+		return &Service{Name: name, Namespace: r.Namespace}, nil
+	}
+
+	return r.newService(service)
+}
+
+func (r *Resolver) newService(service *v1.Service) (*Service, error) {
+
+	route := &Service{
+		Name:      service.Name,
+		Namespace: r.Namespace,
+		Found:     true,
+		Type:      ServiceTypeToString(service.Spec.Type),
+		Ports:     newPorts(service.Spec.Ports),
+	}
+
+	if service.Spec.Type == v1.ServiceTypeExternalName {
+		route.Hostname = service.Spec.ExternalName
+		return route, nil
+	}
+
+	exposure, err := r.resolveServiceExposure(service)
+	if err != nil {
+		return nil, err
+	}
+
+	route.Exposure = exposure
+
+	endpointSlices, err := r.Client.GetEndpointSlicesByService(service.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := map[string]bool{}
+
+	for _, endpointSlice := range endpointSlices.Items {
+		for _, endpoint := range newEndpoints(endpointSlice) {
+			route.Endpoints = append(route.Endpoints, endpoint)
+
+			if endpoint.Pod == "" || pods[endpoint.Pod] {
+				continue
+			}
+			pods[endpoint.Pod] = true
+
+			route.Pods = append(route.Pods, Pod{
+				Name:        endpoint.Pod,
+				Ready:       endpoint.Ready,
+				Terminating: endpoint.Terminating,
+			})
+		}
+	}
+
+	sort.SliceStable(route.Pods, func(i, j int) bool {
+		return route.Pods[i].Name < route.Pods[j].Name
+	})
+
+	if err := r.resolvePods(service, route); err != nil {
+		return nil, err
+	}
+
+	return route, nil
+}
+
+// resolvePods resolves the named target ports of a service against the container ports of
+// the pods backing it and, when the resolver reads pod details, sets the details of the pods.
+// Pods are read through the service selector, and one by one when they are not selected by it,
+// e.g. for services without selector
+func (r *Resolver) resolvePods(service *v1.Service, route *Service) error {
+
+	named := false
+	for _, port := range service.Spec.Ports {
+		if port.TargetPort.Type == intstr.String {
+			named = true
+		}
+	}
+
+	if (!named && !r.PodDetails) || len(route.Pods) == 0 {
+		return nil
+	}
+
+	selected := map[string]*v1.Pod{}
+
+	if len(service.Spec.Selector) > 0 {
+		pods, err := r.Client.GetPodsByLabels(service.Spec.Selector)
+		if err != nil {
+			return err
+		}
+
+		for index := range pods.Items {
+			selected[pods.Items[index].Name] = &pods.Items[index]
+		}
+	}
+
+	pods := []*v1.Pod{}
+
+	for index, routePod := range route.Pods {
+		pod, ok := selected[routePod.Name]
+
+		if !ok {
+			var err error
+
+			pod, err = r.Client.GetPodByName(routePod.Name)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+
+		// Pods that can not be read are kept so that their target ports are reported as not found
+		if pod == nil || pod.Name == "" {
+			pod = &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: routePod.Name}}
+		} else if r.PodDetails {
+			route.Pods[index].Details = NewPodDetails(pod)
+		}
+
+		pods = append(pods, pod)
+	}
+
+	if !named {
+		return nil
+	}
+
+	for index, port := range service.Spec.Ports {
+		if port.TargetPort.Type == intstr.String {
+			route.Ports[index].TargetPorts = ResolveTargetPorts(pods, port.TargetPort.StrVal)
+		}
+	}
+
+	return nil
+}
+
+// ResolveTargetPorts returns the container port that a named target port resolves to on each pod.
+// The port number used by most pods is the expected one, and the pods resolving the name to
+// another number are flagged, as it happens during rollouts that renumber container ports
+func ResolveTargetPorts(pods []*v1.Pod, name string) []TargetPort {
+
+	targetPorts := []TargetPort{}
+	counts := map[int32]int{}
+	expected := int32(0)
+
+	for _, pod := range pods {
+		targetPort := TargetPort{Pod: pod.Name}
+
+		container, port, ok := findContainerPort(pod, name)
+		if ok {
+			targetPort.Found = true
+			targetPort.Container = container
+			targetPort.Port = port
+
+			counts[port]++
+
+			// Ties are broken in favor of the first pod
+			if counts[port] > counts[expected] {
+				expected = port
+			}
+		}
+
+		targetPorts = append(targetPorts, targetPort)
+	}
+
+	for index := range targetPorts {
+		if targetPorts[index].Found && targetPorts[index].Port != expected {
+			targetPorts[index].Mismatch = true
+		}
+	}
+
+	return targetPorts
+}
+
+// NewPodDetails returns the placement and the state of a pod. The creation time is
+// not set for pods without one, such as the pods synthesized from manifests
+func NewPodDetails(pod *v1.Pod) *PodDetails {
+	details := &PodDetails{
+		IP:         pod.Status.PodIP,
+		Node:       pod.Spec.NodeName,
+		Phase:      string(pod.Status.Phase),
+		Containers: len(pod.Spec.Containers),
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			details.ReadyContainers++
+		}
+
+		details.Restarts += status.RestartCount
+	}
+
+	if !pod.CreationTimestamp.IsZero() {
+		created := pod.CreationTimestamp.Time
+		details.Created = &created
+	}
+
+	return details
+}
+
+// findContainerPort returns the container and the number of the container port of a pod that matches a given name
+func findContainerPort(pod *v1.Pod, name string) (string, int32, bool) {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == name {
+				return container.Name, port.ContainerPort, true
+			}
+		}
+	}
+
+	return "", 0, false
+}
+
+func newEndpoints(endpointSlice discoveryv1.EndpointSlice) (endpoints []Endpoint) {
+	ports := []EndpointPort{}
+
+	for _, port := range endpointSlice.Ports {
+		endpointPort := EndpointPort{}
+
+		if port.Name != nil {
+			endpointPort.Name = *port.Name
+		}
+		if port.Port != nil {
+			endpointPort.Port = *port.Port
+		}
+		if port.Protocol != nil {
+			endpointPort.Protocol = string(*port.Protocol)
+		}
+
+		ports = append(ports, endpointPort)
+	}
+
+	for _, endpointSliceEndpoint := range endpointSlice.Endpoints {
+
+		// A nil ready condition must be interpreted as ready
+		endpoint := Endpoint{
+			Addresses: endpointSliceEndpoint.Addresses,
+			Ready:     endpointSliceEndpoint.Conditions.Ready == nil || *endpointSliceEndpoint.Conditions.Ready,
+			Ports:     ports,
+		}
+
+		if endpointSliceEndpoint.Conditions.Terminating != nil {
+			endpoint.Terminating = *endpointSliceEndpoint.Conditions.Terminating
+		}
+
+		if endpointSliceEndpoint.TargetRef != nil && endpointSliceEndpoint.TargetRef.Kind == "Pod" {
+			endpoint.Pod = endpointSliceEndpoint.TargetRef.Name
+		}
+
+		if endpointSliceEndpoint.NodeName != nil {
+			endpoint.Node = *endpointSliceEndpoint.NodeName
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return
+}
+
+func newPorts(servicePorts []v1.ServicePort) (ports []Port) {
+	for _, port := range servicePorts {
+		ports = append(ports, Port{
+			Name:       port.Name,
+			Port:       port.Port,
+			TargetPort: PortToString(port.TargetPort.Type, port.TargetPort.StrVal, port.TargetPort.IntVal),
+			NodePort:   port.NodePort,
+			Protocol:   string(port.Protocol),
+		})
+	}
+
+	return
+}
@@ -0,0 +1,382 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func NewFakeResolver() *Resolver {
+	client := NewFakeIngressClient(
+		[]string{IngressNetworkingV1},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod-foo-1",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "foo"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "web", Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}}},
+				},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod-bar-1",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "bar"},
+			},
+		},
+		&v1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-foo", Namespace: "default"},
+			Subsets: []v1.EndpointSubset{
+				{
+					Addresses: []v1.EndpointAddress{
+						{IP: "10.0.0.1", TargetRef: &v1.ObjectReference{Kind: "Pod", Name: "pod-foo-1"}},
+					},
+					Ports: []v1.EndpointPort{{Name: "http", Port: 8080, Protocol: v1.ProtocolTCP}},
+				},
+			},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-foo", Namespace: "default"},
+			Spec: v1.ServiceSpec{
+				Type: v1.ServiceTypeClusterIP,
+				Ports: []v1.ServicePort{
+					{Port: 80, TargetPort: intstr.FromString("http"), Protocol: v1.ProtocolTCP},
+				},
+				Selector: map[string]string{"app": "foo"},
+			},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					{
+						Host: "foo.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path: "/foo",
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "service-foo",
+												Port: networkingv1.ServiceBackendPort{Number: 80},
+											},
+										},
+									},
+									{
+										Path: "/missing",
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "service-missing",
+												Port: networkingv1.ServiceBackendPort{Name: "http"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	return NewResolver(client, "default")
+}
+
+func TestResolverResolveIngress(t *testing.T) {
+
+	expected := &Route{
+		Name:      "ingress",
+		Namespace: "default",
+		Hosts: []Host{
+			{
+				Host: "foo.com",
+				Paths: []Path{
+					{
+						Path:        "/foo",
+						BackendPort: "80",
+						Service: &Service{
+							Name:      "service-foo",
+							Namespace: "default",
+							Found:     true,
+							Type:      "ClusterIP",
+							Ports: []Port{
+								{
+									Port:        80,
+									TargetPort:  "http",
+									Protocol:    "TCP",
+									TargetPorts: []TargetPort{{Pod: "pod-foo-1", Found: true, Container: "web", Port: 8080}},
+								},
+							},
+							Pods: []Pod{{Name: "pod-foo-1", Ready: true}},
+							Endpoints: []Endpoint{
+								{
+									Addresses: []string{"10.0.0.1"},
+									Ready:     true,
+									Pod:       "pod-foo-1",
+									Ports:     []EndpointPort{{Name: "http", Port: 8080, Protocol: "TCP"}},
+								},
+							},
+						},
+					},
+					{
+						Path:        "/missing",
+						BackendPort: "http",
+						Service: &Service{
+							Name:      "service-missing",
+							Namespace: "default",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	route, err := NewFakeResolver().ResolveIngress("ingress")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(route, expected) {
+		t.Errorf("Returned route was incorrect,\ngot:\n%+v\nwant:\n%+v", route, expected)
+	}
+}
+
+func TestResolverResolveIngressDefaultBackend(t *testing.T) {
+
+	apiGroup := "k8s.example.com"
+
+	client := NewFakeIngressClient(
+		[]string{IngressNetworkingV1},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-default", Namespace: "default"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeExternalName, ExternalName: "default.example.com"},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"},
+			Spec: networkingv1.IngressSpec{
+				DefaultBackend: &networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: "service-default",
+						Port: networkingv1.ServiceBackendPort{Number: 80},
+					},
+				},
+				Rules: []networkingv1.IngressRule{
+					{Host: "foo.com"},
+					{
+						Host: "static.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path: "/assets",
+										Backend: networkingv1.IngressBackend{
+											Resource: &v1.TypedLocalObjectReference{
+												APIGroup: &apiGroup,
+												Kind:     "StorageBucket",
+												Name:     "static-assets",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	expected := &Route{
+		Name:      "ingress",
+		Namespace: "default",
+		Hosts: []Host{
+			{Host: "foo.com", Paths: []Path{}},
+			{
+				Host: "static.com",
+				Paths: []Path{
+					{
+						Path: "/assets",
+						Service: &Service{
+							Name:      "k8s.example.com/StorageBucket/static-assets",
+							Namespace: "default",
+							Type:      "Resource",
+						},
+					},
+				},
+			},
+		},
+		DefaultBackend: &Path{
+			BackendPort: "80",
+			Service: &Service{
+				Name:      "service-default",
+				Namespace: "default",
+				Found:     true,
+				Type:      "ExternalName",
+				Hostname:  "default.example.com",
+			},
+		},
+	}
+
+	route, err := NewResolver(client, "default").ResolveIngress("ingress")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(route, expected) {
+		t.Errorf("Returned route was incorrect,\ngot:\n%+v\nwant:\n%+v", route, expected)
+	}
+}
+
+func TestResolveTargetPorts(t *testing.T) {
+
+	newPod := func(name string, ports ...v1.ContainerPort) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web", Ports: ports}}},
+		}
+	}
+
+	pods := []*v1.Pod{
+		newPod("pod-1", v1.ContainerPort{Name: "http", ContainerPort: 8080}),
+		newPod("pod-2", v1.ContainerPort{Name: "http", ContainerPort: 9090}),
+		newPod("pod-3", v1.ContainerPort{Name: "http", ContainerPort: 9090}),
+		newPod("pod-4", v1.ContainerPort{Name: "metrics", ContainerPort: 9090}),
+	}
+
+	expected := []TargetPort{
+		{Pod: "pod-1", Found: true, Container: "web", Port: 8080, Mismatch: true},
+		{Pod: "pod-2", Found: true, Container: "web", Port: 9090},
+		{Pod: "pod-3", Found: true, Container: "web", Port: 9090},
+		{Pod: "pod-4"},
+	}
+
+	targetPorts := ResolveTargetPorts(pods, "http")
+
+	if !reflect.DeepEqual(targetPorts, expected) {
+		t.Errorf("Returned target ports were incorrect,\ngot:\n%+v\nwant:\n%+v", targetPorts, expected)
+	}
+}
+
+func TestNewPodDetails(t *testing.T) {
+
+	created := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-1", CreationTimestamp: metav1.NewTime(created)},
+		Spec:       v1.PodSpec{NodeName: "node-1", Containers: []v1.Container{{Name: "web"}, {Name: "proxy"}}},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			PodIP: "10.1.0.1",
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "web", Ready: true, RestartCount: 3},
+				{Name: "proxy", RestartCount: 1},
+			},
+		},
+	}
+
+	expected := &PodDetails{IP: "10.1.0.1", Node: "node-1", Phase: "Running", ReadyContainers: 1, Containers: 2, Restarts: 4, Created: &created}
+
+	if details := NewPodDetails(pod); !reflect.DeepEqual(details, expected) {
+		t.Errorf("Returned pod details were incorrect, got: %+v, want: %+v", details, expected)
+	}
+}
+
+func TestResolverNotFound(t *testing.T) {
+
+	resolver := NewFakeResolver()
+
+	if _, err := resolver.ResolveIngress("ingress-missing"); err == nil {
+		t.Errorf("Expected an error for a missing ingress")
+	}
+
+	if _, err := resolver.ResolveService("service-missing"); err == nil {
+		t.Errorf("Expected an error for a missing service")
+	}
+}
+
+func TestResolverResolveIngressesAllNamespaces(t *testing.T) {
+
+	newIngress := func(name string, namespace string, team string) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{"team": team},
+			},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					{
+						Host: name + ".com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path: "/",
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "service-foo",
+												Port: networkingv1.ServiceBackendPort{Number: 80},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	client := NewFakeIngressClient(
+		[]string{IngressNetworkingV1},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-foo", Namespace: "other"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeExternalName, ExternalName: "foo.example.com"},
+		},
+		newIngress("ingress-web", "other", "web"),
+		newIngress("ingress-web", "default", "web"),
+		newIngress("ingress-api", "default", "api"),
+	)
+	client.Namespace = ""
+
+	routes, err := NewResolver(client, "").ResolveIngresses(nil, "team=web")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		namespace    string
+		serviceFound bool
+	}{
+		{"default", false},
+		{"other", true},
+	}
+
+	if len(routes) != len(expected) {
+		t.Fatalf("Returned route count was incorrect, got: %d, want: %d", len(routes), len(expected))
+	}
+
+	for index, route := range routes {
+		service := route.Hosts[0].Paths[0].Service
+
+		if route.Namespace != expected[index].namespace || service.Namespace != expected[index].namespace {
+			t.Errorf("Returned route namespace was incorrect, got: %s/%s, want: %s", route.Namespace, service.Namespace, expected[index].namespace)
+		}
+
+		if service.Found != expected[index].serviceFound {
+			t.Errorf("Returned service found was incorrect for namespace %s, got: %t, want: %t", route.Namespace, service.Found, expected[index].serviceFound)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+// Package route resolves ingresses, Gateway API, Istio, OpenShift, Traefik and Contour
+// objects and services into a typed route graph that goes from the entry point object
+// down to the pods serving the traffic, and pods into the reverse graph of services and
+// ingresses routing to them
+package route
+
+import "time"
+
+// Route document schema identifiers. Fields may be added to the schema
+// within a version, but never renamed or removed
+const (
+	DocumentAPIVersion = "route-info/v1"
+	DocumentKind       = "RouteInfo"
+)
+
+// Document is the versioned, machine-readable representation of the route
+// information of an ingress, an HTTPRoute, a Gateway, a VirtualService, an OpenShift
+// route, a Traefik IngressRoute, a Contour HTTPProxy, a service, a pod or a URL.
+// Listings of several ingresses or services are set in the plural fields,
+// snapshots of the route graph of a namespace or cluster also set their timestamp,
+// and the conflicts between the ingresses of a namespace or cluster are set in conflicts
+type Document struct {
+	APIVersion     string     `json:"apiVersion"`
+	Kind           string     `json:"kind"`
+	Timestamp      *time.Time `json:"timestamp,omitempty"`
+	Ingress        *Route     `json:"ingress,omitempty"`
+	Ingresses      []*Route   `json:"ingresses,omitempty"`
+	HTTPRoute      *Route     `json:"httpRoute,omitempty"`
+	Gateway        *Gateway   `json:"gateway,omitempty"`
+	VirtualService *Route     `json:"virtualService,omitempty"`
+	OpenShiftRoute *Route     `json:"openShiftRoute,omitempty"`
+	IngressRoute   *Route     `json:"ingressRoute,omitempty"`
+	HTTPProxy      *Route     `json:"httpProxy,omitempty"`
+	Service        *Service   `json:"service,omitempty"`
+	Services       []*Service `json:"services,omitempty"`
+	Pod            *PodRoute  `json:"pod,omitempty"`
+	URL            *URLRoute  `json:"url,omitempty"`
+	Findings       []Finding  `json:"findings,omitempty"`
+	Conflicts      []Conflict `json:"conflicts,omitempty"`
+}
+
+// Route defines the hosts configured on an entry point object.
+// Kind is only set for objects other than ingresses, and the ingress class, the load
+// balancer exposure and the default backend serving the requests that match no rule
+// are only set for ingresses
+type Route struct {
+	Kind           string        `json:"kind,omitempty"`
+	Name           string        `json:"name"`
+	Namespace      string        `json:"namespace"`
+	IngressClass   *IngressClass `json:"ingressClass,omitempty"`
+	Exposure       *Exposure     `json:"exposure,omitempty"`
+	Hosts          []Host        `json:"hosts"`
+	DefaultBackend *Path         `json:"defaultBackend,omitempty"`
+}
+
+// Ingress class sources, which tell where the class of an ingress is taken from
+const (
+	IngressClassSourceSpec       = "ingressClassName"
+	IngressClassSourceAnnotation = "annotation"
+	IngressClassSourceDefault    = "default"
+)
+
+// IngressClass defines the ingress class of an ingress and the controller that serves it.
+// Source tells whether the class is set in the ingress spec, in the legacy annotation or
+// is the default class of the cluster. Warnings are the problems found with the class,
+// such as classes without IngressClass object or controllers without running pods.
+// Forbidden classes are the ones not resolved because IngressClasses can not be listed
+type IngressClass struct {
+	Name       string   `json:"name,omitempty"`
+	Source     string   `json:"source,omitempty"`
+	Found      bool     `json:"found"`
+	Forbidden  bool     `json:"forbidden,omitempty"`
+	Default    bool     `json:"default,omitempty"`
+	Controller string   `json:"controller,omitempty"`
+	Parameters string   `json:"parameters,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// Host defines the paths configured for a host, along with its TLS configuration when it is set.
+// Warnings are the problems found with the TLS coverage of the host
+type Host struct {
+	Host     string   `json:"host"`
+	TLS      *TLS     `json:"tls,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	Paths    []Path   `json:"paths"`
+}
+
+// TLS defines how TLS connections to a host are terminated, such as the edge, passthrough
+// or reencrypt termination of OpenShift routes, what is done with insecure requests and
+// the secret holding the certificate served for the host
+type TLS struct {
+	Termination                   string  `json:"termination,omitempty"`
+	InsecureEdgeTerminationPolicy string  `json:"insecureEdgeTerminationPolicy,omitempty"`
+	Secret                        *Secret `json:"secret,omitempty"`
+}
+
+// Secret defines a TLS secret and the certificate parsed from it.
+// Forbidden secrets are the ones the user is not allowed to read
+type Secret struct {
+	Name        string       `json:"name"`
+	Found       bool         `json:"found"`
+	Forbidden   bool         `json:"forbidden,omitempty"`
+	Type        string       `json:"type,omitempty"`
+	Certificate *Certificate `json:"certificate,omitempty"`
+}
+
+// Certificate defines the identity, issuer and validity period of an X.509 certificate
+type Certificate struct {
+	Subject   string    `json:"subject"`
+	DNSNames  []string  `json:"dnsNames,omitempty"`
+	Issuer    string    `json:"issuer"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+}
+
+// Path defines the backend configured for a path. Protocol is only set for routes
+// other than HTTP ones. Headers, query parameters, method and conditions are the extra
+// request conditions of Gateway API, Istio, Traefik and Contour matches, filters are the
+// middlewares and header policies applied to the requests, include is the delegated
+// Contour proxy the path is defined in, weight is the share of the matching traffic
+// sent to the backend, and behavior is the one set by ingress controller annotations
+type Path struct {
+	Protocol    string    `json:"protocol,omitempty"`
+	Path        string    `json:"path"`
+	PathType    string    `json:"pathType,omitempty"`
+	Headers     []string  `json:"headers,omitempty"`
+	QueryParams []string  `json:"queryParams,omitempty"`
+	Method      string    `json:"method,omitempty"`
+	Conditions  []string  `json:"conditions,omitempty"`
+	Rewrite     string    `json:"rewrite,omitempty"`
+	Retries     string    `json:"retries,omitempty"`
+	Filters     []string  `json:"filters,omitempty"`
+	Include     string    `json:"include,omitempty"`
+	BackendPort string    `json:"backendPort"`
+	Weight      *int32    `json:"weight,omitempty"`
+	Subset      *Subset   `json:"subset,omitempty"`
+	Behavior    *Behavior `json:"behavior,omitempty"`
+	Service     *Service  `json:"service"`
+}
+
+// Behavior defines the effective behavior of an ingress path decoded from the annotations
+// of its ingress controller. Rewrite is the path rewritten to the target, along with the
+// capture groups of the path the target refers to
+type Behavior struct {
+	Rewrite             string     `json:"rewrite,omitempty"`
+	SSLRedirect         *bool      `json:"sslRedirect,omitempty"`
+	ForceSSLRedirect    bool       `json:"forceSSLRedirect,omitempty"`
+	BackendProtocol     string     `json:"backendProtocol,omitempty"`
+	Canary              *Canary    `json:"canary,omitempty"`
+	Auth                *Auth      `json:"auth,omitempty"`
+	RateLimit           *RateLimit `json:"rateLimit,omitempty"`
+	Timeouts            *Timeouts  `json:"timeouts,omitempty"`
+	CORS                *CORS      `json:"cors,omitempty"`
+	AllowedSourceRanges []string   `json:"allowedSourceRanges,omitempty"`
+}
+
+// Canary defines the requests sent to a canary ingress instead of the main one of its host and
+// path: a share of the traffic, or the requests with a header or a cookie set to "always"
+type Canary struct {
+	Weight        *int32 `json:"weight,omitempty"`
+	WeightTotal   int32  `json:"weightTotal,omitempty"`
+	Header        string `json:"header,omitempty"`
+	HeaderValue   string `json:"headerValue,omitempty"`
+	HeaderPattern string `json:"headerPattern,omitempty"`
+	Cookie        string `json:"cookie,omitempty"`
+}
+
+// Auth defines how requests are authenticated, either by an external service
+// or with the basic or digest credentials of a secret
+type Auth struct {
+	Type   string `json:"type"`
+	URL    string `json:"url,omitempty"`
+	SignIn string `json:"signIn,omitempty"`
+	Secret string `json:"secret,omitempty"`
+	Realm  string `json:"realm,omitempty"`
+}
+
+// RateLimit defines the requests per second, requests per minute and
+// concurrent connections allowed per client address
+type RateLimit struct {
+	RPS             int32 `json:"rps,omitempty"`
+	RPM             int32 `json:"rpm,omitempty"`
+	Connections     int32 `json:"connections,omitempty"`
+	BurstMultiplier int32 `json:"burstMultiplier,omitempty"`
+}
+
+// Timeouts defines the timeouts of the connections to the backend
+type Timeouts struct {
+	Connect string `json:"connect,omitempty"`
+	Read    string `json:"read,omitempty"`
+	Send    string `json:"send,omitempty"`
+}
+
+// CORS defines the cross-origin resource sharing headers returned to the clients
+type CORS struct {
+	AllowOrigin      string `json:"allowOrigin"`
+	AllowMethods     string `json:"allowMethods"`
+	AllowHeaders     string `json:"allowHeaders"`
+	AllowCredentials bool   `json:"allowCredentials"`
+	MaxAge           string `json:"maxAge"`
+}
+
+// Subset defines a named subset of the pods of a service, such as the
+// ones of an Istio DestinationRule. Labels narrow the pods of the service
+type Subset struct {
+	Name   string            `json:"name"`
+	Found  bool              `json:"found"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// BackendTypeResource is the type of the ingress backends that reference a resource instead of a service
+const BackendTypeResource = "Resource"
+
+// Service defines the ports and the pods, endpoints or hostname behind a service.
+// Pods are the ones referenced by the service endpoints. Backends other than
+// services are returned with the kind of their target as type. Exposure is only
+// set for services reachable from outside the cluster
+type Service struct {
+	Name      string     `json:"name"`
+	Namespace string     `json:"namespace"`
+	Found     bool       `json:"found"`
+	Type      string     `json:"type,omitempty"`
+	Ports     []Port     `json:"ports,omitempty"`
+	Pods      []Pod      `json:"pods,omitempty"`
+	Endpoints []Endpoint `json:"endpoints,omitempty"`
+	Hostname  string     `json:"hostname,omitempty"`
+	Exposure  *Exposure  `json:"exposure,omitempty"`
+}
+
+// Entrypoint sources, which tell how traffic from outside the cluster reaches an entrypoint
+const (
+	EntrypointSourceLoadBalancer = "LoadBalancer"
+	EntrypointSourceExternalIP   = "ExternalIP"
+	EntrypointSourceNodePort     = "NodePort"
+)
+
+// Exposure defines how traffic from outside the cluster enters a service or an ingress: the
+// addresses of its load balancer, the external IPs of a service and the source ranges its load
+// balancer accepts. Pending is set for load balancers that are not provisioned yet. Entrypoints
+// are the addresses and ports that can be given out to reach it, including node ports on each node
+type Exposure struct {
+	LoadBalancer []string     `json:"loadBalancer,omitempty"`
+	Pending      bool         `json:"pending,omitempty"`
+	ExternalIPs  []string     `json:"externalIPs,omitempty"`
+	SourceRanges []string     `json:"sourceRanges,omitempty"`
+	Entrypoints  []Entrypoint `json:"entrypoints,omitempty"`
+}
+
+// Entrypoint defines an address and port reachable from outside the cluster.
+// The node and the type of its address, InternalIP or ExternalIP, are only set for node ports
+type Entrypoint struct {
+	Source      string `json:"source"`
+	Address     string `json:"address"`
+	Port        int32  `json:"port"`
+	Protocol    string `json:"protocol,omitempty"`
+	Node        string `json:"node,omitempty"`
+	AddressType string `json:"addressType,omitempty"`
+}
+
+// Port defines a service port. Named target ports are resolved
+// against the container ports of each pod backing the service
+type Port struct {
+	Name        string       `json:"name,omitempty"`
+	Port        int32        `json:"port"`
+	TargetPort  string       `json:"targetPort"`
+	NodePort    int32        `json:"nodePort,omitempty"`
+	Protocol    string       `json:"protocol,omitempty"`
+	TargetPorts []TargetPort `json:"targetPorts,omitempty"`
+}
+
+// TargetPort defines the container port a named target port resolves to on a pod.
+// Mismatch is set when the port number differs from the one of most pods
+type TargetPort struct {
+	Pod       string `json:"pod"`
+	Found     bool   `json:"found"`
+	Container string `json:"container,omitempty"`
+	Port      int32  `json:"port,omitempty"`
+	Mismatch  bool   `json:"mismatch,omitempty"`
+}
+
+// Pod defines a pod that backs a service endpoint.
+// Details are only set when the resolver is asked to read them
+type Pod struct {
+	Name        string      `json:"name"`
+	Ready       bool        `json:"ready"`
+	Terminating bool        `json:"terminating,omitempty"`
+	Details     *PodDetails `json:"details,omitempty"`
+}
+
+// PodDetails defines the placement and the state of a pod, along with the number
+// of its containers that are ready and the restarts of all of its containers
+type PodDetails struct {
+	IP              string     `json:"ip,omitempty"`
+	Node            string     `json:"node,omitempty"`
+	Phase           string     `json:"phase,omitempty"`
+	ReadyContainers int        `json:"readyContainers"`
+	Containers      int        `json:"containers"`
+	Restarts        int32      `json:"restarts"`
+	Created         *time.Time `json:"created,omitempty"`
+}
+
+// Endpoint defines a network endpoint of a service, which may not be backed by a pod
+type Endpoint struct {
+	Addresses   []string       `json:"addresses"`
+	Ready       bool           `json:"ready"`
+	Terminating bool           `json:"terminating,omitempty"`
+	Pod         string         `json:"pod,omitempty"`
+	Node        string         `json:"node,omitempty"`
+	Ports       []EndpointPort `json:"ports,omitempty"`
+}
+
+// EndpointPort defines a port of a service endpoint
+type EndpointPort struct {
+	Name     string `json:"name,omitempty"`
+	Port     int32  `json:"port"`
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// Gateway defines the listeners of a Gateway API gateway
+type Gateway struct {
+	Name      string     `json:"name"`
+	Namespace string     `json:"namespace"`
+	ClassName string     `json:"className,omitempty"`
+	Listeners []Listener `json:"listeners"`
+}
+
+// Listener defines a gateway listener and the HTTPRoutes attached to it
+type Listener struct {
+	Name     string   `json:"name"`
+	Hostname string   `json:"hostname,omitempty"`
+	Port     int32    `json:"port"`
+	Protocol string   `json:"protocol"`
+	Routes   []*Route `json:"routes"`
+}
+
+// PodRoute defines the services and ingress paths that route traffic to a pod
+type PodRoute struct {
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace"`
+	Services  []PodService `json:"services"`
+}
+
+// PodService defines a service that selects a pod and the ingress paths that use it
+type PodService struct {
+	Name     string        `json:"name"`
+	Type     string        `json:"type"`
+	Ports    []Port        `json:"ports,omitempty"`
+	Backends []IngressPath `json:"backends,omitempty"`
+}
+
+// IngressPath defines an ingress path that routes traffic to a service. Default
+// backends match any host and any path, so they are set without host nor path
+type IngressPath struct {
+	Ingress        string `json:"ingress"`
+	Host           string `json:"host"`
+	Path           string `json:"path"`
+	PathType       string `json:"pathType,omitempty"`
+	DefaultBackend bool   `json:"defaultBackend,omitempty"`
+	BackendPort    string `json:"backendPort"`
+}
+
+// URLRoute defines the ingress path selected to serve a URL
+// and, optionally, the other ingress paths that also match it
+type URLRoute struct {
+	URL        string         `json:"url"`
+	Host       string         `json:"host"`
+	Path       string         `json:"path"`
+	Match      *URLCandidate  `json:"match"`
+	Candidates []URLCandidate `json:"candidates,omitempty"`
+}
+
+// URLCandidate defines an ingress path that matches a URL. Default
+// backends match any path, so they are set without host nor path
+type URLCandidate struct {
+	Ingress        string   `json:"ingress"`
+	Host           string   `json:"host"`
+	Path           string   `json:"path"`
+	PathType       string   `json:"pathType,omitempty"`
+	DefaultBackend bool     `json:"defaultBackend,omitempty"`
+	BackendPort    string   `json:"backendPort"`
+	Service        *Service `json:"service"`
+}
+
+// NewDocument returns a new Document struct with the schema identifiers set
+func NewDocument() *Document {
+	return &Document{
+		APIVersion: DocumentAPIVersion,
+		Kind:       DocumentKind,
+	}
+}
@@ -0,0 +1,197 @@
+package route
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// TLS finding types
+const (
+	FindingTLSHostNotCovered      FindingType = "TLSHostNotCovered"
+	FindingTLSSecretNotFound      FindingType = "TLSSecretNotFound"
+	FindingTLSSecretForbidden     FindingType = "TLSSecretForbidden"
+	FindingTLSSecretInvalid       FindingType = "TLSSecretInvalid"
+	FindingTLSCertificateExpired  FindingType = "TLSCertificateExpired"
+	FindingTLSCertificateNotValid FindingType = "TLSCertificateNotYetValid"
+	FindingTLSHostMismatch        FindingType = "TLSHostMismatch"
+)
+
+// resolveIngressTLS returns the TLS configuration of an ingress host, along with the findings of its
+// TLS coverage. Hosts are covered by the first TLS entry that lists them, and TLS entries without
+// hosts cover every host. Secrets are only read once per ingress through the secrets cache
+func (r *Resolver) resolveIngressTLS(ingress *networkingv1.Ingress, host string, secrets map[string]*Secret) (*TLS, []Finding, error) {
+
+	// Rules without host match every host, so there is no certificate to check
+	if host == "" || len(ingress.Spec.TLS) == 0 {
+		return nil, nil, nil
+	}
+
+	for _, ingressTLS := range ingress.Spec.TLS {
+
+		covered := len(ingressTLS.Hosts) == 0
+		for _, tlsHost := range ingressTLS.Hosts {
+			if MatchHost(tlsHost, host) != HostMatchNone {
+				covered = true
+			}
+		}
+
+		if !covered {
+			continue
+		}
+
+		tls := &TLS{Termination: "edge"}
+
+		// TLS entries without secret use the default certificate of the controller
+		if ingressTLS.SecretName == "" {
+			return tls, nil, nil
+		}
+
+		secret, ok := secrets[ingressTLS.SecretName]
+		if !ok {
+			var err error
+			secret, err = r.resolveTLSSecret(ingressTLS.SecretName)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			secrets[ingressTLS.SecretName] = secret
+		}
+
+		tls.Secret = secret
+
+		return tls, checkTLSSecret(host, secret), nil
+	}
+
+	return nil, []Finding{{
+		Severity: SeverityWarning,
+		Type:     FindingTLSHostNotCovered,
+		Message:  "host " + host + " is not covered by any TLS entry",
+	}}, nil
+}
+
+// resolveTLSSecret returns the TLS secret that matches a given name and the certificate parsed from it.
+// Users are often not allowed to read secrets, so secrets are flagged as forbidden then
+func (r *Resolver) resolveTLSSecret(name string) (*Secret, error) {
+
+	secret, err := r.Client.GetSecretByName(name)
+	if apierrors.IsForbidden(err) {
+		return &Secret{Name: name, Forbidden: true}, nil
+	}
+
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if err != nil || secret == nil {
+		return &Secret{Name: name}, nil
+	}
+
+	tlsSecret := &Secret{
+		Name:  name,
+		Found: true,
+		Type:  string(secret.Type),
+	}
+
+	block, _ := pem.Decode(secret.Data[v1.TLSCertKey])
+	if block == nil {
+		return tlsSecret, nil
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return tlsSecret, nil
+	}
+
+	tlsSecret.Certificate = &Certificate{
+		Subject:   certificate.Subject.String(),
+		DNSNames:  certificate.DNSNames,
+		Issuer:    certificate.Issuer.String(),
+		NotBefore: certificate.NotBefore.UTC(),
+		NotAfter:  certificate.NotAfter.UTC(),
+	}
+
+	return tlsSecret, nil
+}
+
+// checkTLSSecret returns the findings of the TLS secret of a host: unreadable or missing secrets,
+// secrets of other types or without certificate, certificates out of their validity
+// period and certificates whose names do not match the host
+func checkTLSSecret(host string, secret *Secret) []Finding {
+
+	if secret.Forbidden {
+		return []Finding{{
+			Severity: SeverityWarning,
+			Type:     FindingTLSSecretForbidden,
+			Message:  "TLS secret " + secret.Name + " of host " + host + " can not be read, so its certificate is not checked",
+		}}
+	}
+
+	if !secret.Found {
+		return []Finding{{
+			Severity: SeverityError,
+			Type:     FindingTLSSecretNotFound,
+			Message:  "TLS secret " + secret.Name + " of host " + host + " not found",
+		}}
+	}
+
+	findings := []Finding{}
+
+	if secret.Type != string(v1.SecretTypeTLS) {
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			Type:     FindingTLSSecretInvalid,
+			Message:  "TLS secret " + secret.Name + " is of type " + secret.Type + " instead of " + string(v1.SecretTypeTLS),
+		})
+	}
+
+	certificate := secret.Certificate
+	if certificate == nil {
+		return append(findings, Finding{
+			Severity: SeverityError,
+			Type:     FindingTLSSecretInvalid,
+			Message:  "TLS secret " + secret.Name + " does not contain a valid certificate",
+		})
+	}
+
+	switch current := time.Now(); {
+	case current.After(certificate.NotAfter):
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Type:     FindingTLSCertificateExpired,
+			Message:  "certificate of TLS secret " + secret.Name + " expired on " + certificate.NotAfter.Format(time.RFC3339),
+		})
+
+	case current.Before(certificate.NotBefore):
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			Type:     FindingTLSCertificateNotValid,
+			Message:  "certificate of TLS secret " + secret.Name + " is not valid before " + certificate.NotBefore.Format(time.RFC3339),
+		})
+	}
+
+	if !certificateMatchesHost(certificate, host) {
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			Type:     FindingTLSHostMismatch,
+			Message:  "certificate of TLS secret " + secret.Name + " does not match host " + host,
+		})
+	}
+
+	return findings
+}
+
+// certificateMatchesHost returns whether a host matches one of the DNS names of a certificate
+func certificateMatchesHost(certificate *Certificate, host string) bool {
+	for _, name := range certificate.DNSNames {
+		if MatchHost(name, host) != HostMatchNone {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,186 @@
+package route
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newCertificatePEM returns a PEM encoded self-signed certificate for the given names
+func newCertificatePEM(t *testing.T, commonName string, dnsNames []string, notBefore time.Time, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate})
+}
+
+func newTLSSecret(name string, secretType v1.SecretType, certificate []byte) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Type:       secretType,
+		Data:       map[string][]byte{v1.TLSCertKey: certificate},
+	}
+}
+
+func NewFakeTLSResolver(t *testing.T) *Resolver {
+	notBefore := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rules := []networkingv1.IngressRule{}
+	for _, host := range []string{"foo.com", "www.foo.com", "bar.com", "expired.com", "missing.com", "opaque.com", "plain.com"} {
+		rules = append(rules, networkingv1.IngressRule{Host: host})
+	}
+
+	client := NewFakeIngressClient(
+		[]string{IngressNetworkingV1},
+		newTLSSecret("foo-tls", v1.SecretTypeTLS, newCertificatePEM(t, "foo.com", []string{"foo.com", "*.foo.com"}, notBefore, notAfter)),
+		newTLSSecret("bar-tls", v1.SecretTypeTLS, newCertificatePEM(t, "baz.com", []string{"baz.com"}, notBefore, notAfter)),
+		newTLSSecret("expired-tls", v1.SecretTypeTLS, newCertificatePEM(t, "expired.com", []string{"expired.com"}, notBefore, notBefore.AddDate(1, 0, 0))),
+		newTLSSecret("opaque-tls", v1.SecretTypeOpaque, []byte("not a certificate")),
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"},
+			Spec: networkingv1.IngressSpec{
+				TLS: []networkingv1.IngressTLS{
+					{Hosts: []string{"foo.com", "www.foo.com"}, SecretName: "foo-tls"},
+					{Hosts: []string{"bar.com"}, SecretName: "bar-tls"},
+					{Hosts: []string{"expired.com"}, SecretName: "expired-tls"},
+					{Hosts: []string{"missing.com"}, SecretName: "missing-tls"},
+					{Hosts: []string{"opaque.com"}, SecretName: "opaque-tls"},
+				},
+				Rules: rules,
+			},
+		},
+	)
+
+	return NewResolver(client, "default")
+}
+
+func TestResolverResolveIngressTLS(t *testing.T) {
+
+	fooCertificate := &Certificate{
+		Subject:   "CN=foo.com",
+		DNSNames:  []string{"foo.com", "*.foo.com"},
+		Issuer:    "CN=foo.com",
+		NotBefore: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:  time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		host             string
+		expectedSecret   *Secret
+		expectedWarnings []string
+	}{
+		{"foo.com", &Secret{Name: "foo-tls", Found: true, Type: "kubernetes.io/tls", Certificate: fooCertificate}, nil},
+		{"www.foo.com", &Secret{Name: "foo-tls", Found: true, Type: "kubernetes.io/tls", Certificate: fooCertificate}, nil},
+		{"bar.com", nil, []string{"certificate of TLS secret bar-tls does not match host bar.com"}},
+		{"expired.com", nil, []string{"certificate of TLS secret expired-tls expired on 2021-01-01T00:00:00Z"}},
+		{"missing.com", &Secret{Name: "missing-tls"}, []string{"TLS secret missing-tls of host missing.com not found"}},
+		{"opaque.com", &Secret{Name: "opaque-tls", Found: true, Type: "Opaque"}, []string{"TLS secret opaque-tls is of type Opaque instead of kubernetes.io/tls", "TLS secret opaque-tls does not contain a valid certificate"}},
+		{"plain.com", nil, []string{"host plain.com is not covered by any TLS entry"}},
+	}
+
+	route, err := NewFakeTLSResolver(t).ResolveIngress("ingress")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for index, test := range tests {
+		host := route.Hosts[index]
+
+		if host.Host != test.host || !reflect.DeepEqual(host.Warnings, test.expectedWarnings) {
+			t.Errorf("Returned warnings of host %s were incorrect, got: %v, want: %v", test.host, host.Warnings, test.expectedWarnings)
+		}
+
+		if test.expectedSecret != nil && (host.TLS == nil || !reflect.DeepEqual(host.TLS.Secret, test.expectedSecret)) {
+			t.Errorf("Returned TLS of host %s was incorrect, got: %+v, want secret: %+v", test.host, host.TLS, test.expectedSecret)
+		}
+	}
+}
+
+func TestResolverCheckIngressTLS(t *testing.T) {
+
+	findings, err := NewFakeTLSResolver(t).CheckIngress("ingress")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	types := []FindingType{}
+	for _, finding := range findings {
+		if finding.Object != "Ingress/ingress" {
+			t.Errorf("Returned finding object was incorrect, got: %s", finding.Object)
+		}
+
+		types = append(types, finding.Type)
+	}
+
+	expected := []FindingType{
+		FindingTLSHostMismatch,
+		FindingTLSCertificateExpired,
+		FindingTLSSecretNotFound,
+		FindingTLSSecretInvalid,
+		FindingTLSSecretInvalid,
+		FindingTLSHostNotCovered,
+	}
+
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("Returned findings were incorrect, got: %v, want: %v", types, expected)
+	}
+}
+
+func TestResolverResolveIngressTLSForbidden(t *testing.T) {
+
+	resolver := NewFakeTLSResolver(t)
+	resolver.Client = &forbiddenClient{ClientInterface: resolver.Client, resources: map[string]bool{"secrets": true}}
+
+	route, err := resolver.ResolveIngress("ingress")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	host := route.Hosts[0]
+
+	expectedSecret := &Secret{Name: "foo-tls", Forbidden: true}
+	if host.TLS == nil || !reflect.DeepEqual(host.TLS.Secret, expectedSecret) {
+		t.Errorf("Returned TLS was incorrect, got: %+v, want secret: %+v", host.TLS, expectedSecret)
+	}
+
+	expectedWarnings := []string{"TLS secret foo-tls of host foo.com can not be read, so its certificate is not checked"}
+	if !reflect.DeepEqual(host.Warnings, expectedWarnings) {
+		t.Errorf("Returned warnings were incorrect, got: %v, want: %v", host.Warnings, expectedWarnings)
+	}
+
+	findings, err := resolver.CheckIngress("ingress")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if CountErrors(findings) != 0 {
+		t.Errorf("Returned findings were incorrect, got: %+v", findings)
+	}
+}
@@ -0,0 +1,341 @@
+package route
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// KindIngressRoute is the kind of Traefik ingress routes
+const KindIngressRoute = "IngressRoute"
+
+// ingressRouteObject declares the fields of a Traefik ingress route used by the resolver
+type ingressRouteObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Routes []struct {
+			Match       string `json:"match"`
+			Middlewares []struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace,omitempty"`
+			} `json:"middlewares,omitempty"`
+			Services []struct {
+				Kind      string              `json:"kind,omitempty"`
+				Name      string              `json:"name"`
+				Namespace string              `json:"namespace,omitempty"`
+				Port      *intstr.IntOrString `json:"port,omitempty"`
+				Weight    *int32              `json:"weight,omitempty"`
+			} `json:"services,omitempty"`
+		} `json:"routes"`
+		TLS *struct {
+			SecretName string `json:"secretName,omitempty"`
+		} `json:"tls,omitempty"`
+	} `json:"spec"`
+}
+
+// traefikMatch defines an alternative of a Traefik rule, which is made of the matchers joined by &&.
+// The request conditions of the matchers are set on the path template
+type traefikMatch struct {
+	hosts []string
+	paths []Path
+	path  Path
+}
+
+// ResolveIngressRoute returns the route graph of the Traefik ingress route that matches a given name.
+// The hosts and paths are read from the Host and Path matchers of the route rules
+func (r *Resolver) ResolveIngressRoute(name string) (*Route, error) {
+
+	object, err := r.Client.GetCustomResourceByName("ingressroutes", TraefikGroupVersions, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if object == nil {
+		return nil, fmt.Errorf("ingressroute %q not found", name)
+	}
+
+	ingressRoute := &ingressRouteObject{}
+	if err := fromUnstructured(object, ingressRoute); err != nil {
+		return nil, err
+	}
+
+	hosts := newHostIndex()
+
+	var tls *TLS
+	if ingressRoute.Spec.TLS != nil {
+		tls = &TLS{Termination: "edge"}
+	}
+
+	for _, rule := range ingressRoute.Spec.Routes {
+
+		filters := []string{}
+		for _, middleware := range rule.Middlewares {
+			filters = append(filters, "middleware:"+objectName(middleware.Namespace, middleware.Name, r.Namespace))
+		}
+
+		backends := []Path{}
+
+		for _, service := range rule.Services {
+
+			namespace := r.Namespace
+			if service.Namespace != "" {
+				namespace = service.Namespace
+			}
+
+			// Traefik spreads the traffic evenly between services without weight
+			weight := int32(1)
+			if service.Weight != nil {
+				weight = *service.Weight
+			}
+
+			backend := Path{
+				Weight: &weight,
+			}
+
+			if service.Port != nil {
+				backend.BackendPort = service.Port.String()
+			}
+
+			// Only core services are followed, TraefikService objects are reported as not found
+			if service.Kind == "" || service.Kind == "Service" {
+				backend.Service, err = r.forNamespace(namespace).resolveBackendService(service.Name)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				backend.Service = &Service{Name: service.Name, Namespace: namespace, Type: service.Kind}
+			}
+
+			backends = append(backends, backend)
+		}
+
+		for _, match := range parseTraefikRule(rule.Match) {
+			for _, host := range match.hosts {
+				for _, matchPath := range match.paths {
+					for _, backend := range backends {
+						path := match.path
+						path.Path = matchPath.Path
+						path.PathType = matchPath.PathType
+						path.BackendPort = backend.BackendPort
+						path.Weight = backend.Weight
+						path.Service = backend.Service
+
+						if len(filters) > 0 {
+							path.Filters = filters
+						}
+
+						hosts.add(host, tls, path)
+					}
+				}
+			}
+		}
+	}
+
+	return &Route{
+		Kind:      KindIngressRoute,
+		Name:      ingressRoute.Name,
+		Namespace: r.Namespace,
+		Hosts:     hosts.hosts,
+	}, nil
+}
+
+// parseTraefikRule returns the alternatives of a Traefik rule, e.g. Host(`foo.com`) && PathPrefix(`/api`).
+// Hosts, paths, headers, query parameters and methods are read from their matchers, and any other
+// expression, such as negations or nested alternatives, is kept as is in the path conditions
+func parseTraefikRule(rule string) []traefikMatch {
+
+	matches := []traefikMatch{}
+
+	for _, alternative := range splitTraefikRule(rule, "||") {
+
+		match := traefikMatch{}
+
+		for _, term := range splitTraefikRule(alternative, "&&") {
+			term = trimTraefikParentheses(term)
+
+			name, args, ok := parseTraefikMatcher(term)
+			if !ok {
+				match.path.Conditions = append(match.path.Conditions, term)
+				continue
+			}
+
+			switch {
+			case (name == "Host" || name == "HostRegexp") && len(args) > 0:
+				match.hosts = append(match.hosts, args...)
+
+			case (name == "Path" || name == "PathPrefix" || name == "PathRegexp") && len(args) > 0:
+				pathType := map[string]string{"Path": "Exact", "PathPrefix": "Prefix", "PathRegexp": "RegularExpression"}[name]
+				for _, arg := range args {
+					match.paths = append(match.paths, Path{Path: arg, PathType: pathType})
+				}
+
+			case (name == "Headers" || name == "Header") && len(args) == 2:
+				match.path.Headers = append(match.path.Headers, args[0]+"="+args[1])
+
+			case (name == "HeadersRegexp" || name == "HeaderRegexp") && len(args) == 2:
+				match.path.Headers = append(match.path.Headers, args[0]+"~="+args[1])
+
+			case name == "Query" && len(args) == 2:
+				match.path.QueryParams = append(match.path.QueryParams, args[0]+"="+args[1])
+
+			case name == "Query" && len(args) > 0:
+				match.path.QueryParams = append(match.path.QueryParams, args...)
+
+			case name == "QueryRegexp" && len(args) == 2:
+				match.path.QueryParams = append(match.path.QueryParams, args[0]+"~="+args[1])
+
+			case name == "Method" && len(args) > 0:
+				match.path.Method = strings.Join(args, "|")
+
+			case name == "ClientIP" && len(args) > 0:
+				match.path.Conditions = append(match.path.Conditions, "clientIP="+strings.Join(args, "|"))
+
+			default:
+				match.path.Conditions = append(match.path.Conditions, term)
+			}
+		}
+
+		// Rules without Host or Path matchers match every host or path
+		if len(match.hosts) == 0 {
+			match.hosts = []string{""}
+		}
+
+		if len(match.paths) == 0 {
+			match.paths = []Path{{Path: "/", PathType: "Prefix"}}
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches
+}
+
+// splitTraefikRule splits a rule on an operator, ignoring the operators that
+// are nested in parentheses or quoted in matcher arguments
+func splitTraefikRule(rule string, operator string) []string {
+
+	parts := []string{}
+	depth := 0
+	quote := byte(0)
+	start := 0
+
+	for index := 0; index < len(rule); index++ {
+		char := rule[index]
+
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+
+		case char == '`' || char == '"':
+			quote = char
+
+		case char == '(':
+			depth++
+
+		case char == ')':
+			depth--
+
+		case depth == 0 && strings.HasPrefix(rule[index:], operator):
+			parts = append(parts, strings.TrimSpace(rule[start:index]))
+			start = index + len(operator)
+			index += len(operator) - 1
+		}
+	}
+
+	return append(parts, strings.TrimSpace(rule[start:]))
+}
+
+// trimTraefikParentheses removes the parentheses that enclose a whole expression
+func trimTraefikParentheses(term string) string {
+	for strings.HasPrefix(term, "(") && closingParenthesis(term) == len(term)-1 {
+		term = strings.TrimSpace(term[1 : len(term)-1])
+	}
+
+	return term
+}
+
+// closingParenthesis returns the index of the parenthesis that closes the one
+// an expression starts with, ignoring the ones quoted in matcher arguments
+func closingParenthesis(term string) int {
+	depth := 0
+	quote := byte(0)
+
+	for index := 0; index < len(term); index++ {
+		char := term[index]
+
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+
+		case char == '`' || char == '"':
+			quote = char
+
+		case char == '(':
+			depth++
+
+		case char == ')':
+			depth--
+			if depth == 0 {
+				return index
+			}
+		}
+	}
+
+	return -1
+}
+
+// parseTraefikMatcher returns the name and arguments of a matcher such as Headers(`X-Foo`, `bar`)
+func parseTraefikMatcher(term string) (string, []string, bool) {
+
+	open := strings.Index(term, "(")
+	if open <= 0 || closingParenthesis(term[open:]) != len(term)-open-1 || strings.ContainsAny(term[:open], " !") {
+		return "", nil, false
+	}
+
+	args := []string{}
+	for _, arg := range splitTraefikRule(term[open+1:len(term)-1], ",") {
+		if len(arg) < 2 || (arg[0] != '`' && arg[0] != '"') || arg[len(arg)-1] != arg[0] {
+			return "", nil, false
+		}
+
+		args = append(args, arg[1:len(arg)-1])
+	}
+
+	return term[:open], args, true
+}
+
+// hostIndex collects the paths of a route by host, keeping the hosts in order of appearance
+type hostIndex struct {
+	hosts   []Host
+	indexes map[string]int
+}
+
+func newHostIndex() *hostIndex {
+	return &hostIndex{hosts: []Host{}, indexes: map[string]int{}}
+}
+
+func (h *hostIndex) add(host string, tls *TLS, path Path) {
+	index, ok := h.indexes[host]
+	if !ok {
+		index = len(h.hosts)
+		h.indexes[host] = index
+		h.hosts = append(h.hosts, Host{Host: host, TLS: tls, Paths: []Path{}})
+	}
+
+	h.hosts[index].Paths = append(h.hosts[index].Paths, path)
+}
+
+// objectName returns the name of an object, prefixed with its namespace when it is not the given one
+func objectName(namespace string, name string, defaultNamespace string) string {
+	if namespace == "" || namespace == defaultNamespace {
+		return name
+	}
+
+	return namespace + "/" + name
+}
@@ -0,0 +1,145 @@
+package route
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestParseTraefikRule(t *testing.T) {
+
+	tests := []struct {
+		rule     string
+		expected []traefikMatch
+	}{
+		{
+			"Host(`foo.com`) && PathPrefix(`/api`)",
+			[]traefikMatch{
+				{hosts: []string{"foo.com"}, paths: []Path{{Path: "/api", PathType: "Prefix"}}},
+			},
+		},
+		{
+			"(Host(`foo.com`) || Host(`bar.com`)) && Path(`/health`)",
+			[]traefikMatch{
+				{hosts: []string{""}, paths: []Path{{Path: "/health", PathType: "Exact"}}, path: Path{Conditions: []string{"Host(`foo.com`) || Host(`bar.com`)"}}},
+			},
+		},
+		{
+			"Host(`foo.com`) && Headers(`X-Canary`, `true`) && Method(`GET`, `POST`) || Host(`bar.com`) && Query(`debug=1`)",
+			[]traefikMatch{
+				{hosts: []string{"foo.com"}, paths: []Path{{Path: "/", PathType: "Prefix"}}, path: Path{Headers: []string{"X-Canary=true"}, Method: "GET|POST"}},
+				{hosts: []string{"bar.com"}, paths: []Path{{Path: "/", PathType: "Prefix"}}, path: Path{QueryParams: []string{"debug=1"}}},
+			},
+		},
+		{
+			"Host(`foo.com`) && !PathPrefix(`/admin`) && PathRegexp(`^/v[0-9]+`)",
+			[]traefikMatch{
+				{hosts: []string{"foo.com"}, paths: []Path{{Path: "^/v[0-9]+", PathType: "RegularExpression"}}, path: Path{Conditions: []string{"!PathPrefix(`/admin`)"}}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		matches := parseTraefikRule(test.rule)
+
+		if !reflect.DeepEqual(matches, test.expected) {
+			t.Errorf("Returned matches of rule %s were incorrect,\ngot:\n%+v\nwant:\n%+v", test.rule, matches, test.expected)
+		}
+	}
+}
+
+func TestResolverResolveIngressRoute(t *testing.T) {
+
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: TraefikV1alpha1,
+			APIResources: []metav1.APIResource{{Name: "ingressroutes", Kind: "IngressRoute", Namespaced: true}},
+		},
+	}
+
+	objects := []runtime.Object{
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-foo", Namespace: "default"},
+			Spec: v1.ServiceSpec{
+				Type:  v1.ServiceTypeClusterIP,
+				Ports: []v1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(8080), Protocol: v1.ProtocolTCP}},
+			},
+		},
+	}
+
+	client := NewFakeCustomResourceClient(t, resources, objects, `
+apiVersion: traefik.io/v1alpha1
+kind: IngressRoute
+metadata:
+  name: ingressroute-foo
+  namespace: default
+spec:
+  routes:
+  - match: Host(`+"`foo.com`"+`) && PathPrefix(`+"`/api`"+`)
+    kind: Rule
+    middlewares:
+    - name: strip-prefix
+    - name: auth
+      namespace: security
+    services:
+    - name: service-foo
+      port: 80
+      weight: 3
+    - name: service-canary
+      kind: TraefikService
+  tls:
+    secretName: foo-tls
+`)
+
+	weight3 := int32(3)
+	weight1 := int32(1)
+	filters := []string{"middleware:strip-prefix", "middleware:security/auth"}
+
+	expected := &Route{
+		Kind:      "IngressRoute",
+		Name:      "ingressroute-foo",
+		Namespace: "default",
+		Hosts: []Host{
+			{
+				Host: "foo.com",
+				TLS:  &TLS{Termination: "edge"},
+				Paths: []Path{
+					{
+						Path:        "/api",
+						PathType:    "Prefix",
+						Filters:     filters,
+						BackendPort: "80",
+						Weight:      &weight3,
+						Service: &Service{
+							Name:      "service-foo",
+							Namespace: "default",
+							Found:     true,
+							Type:      "ClusterIP",
+							Ports:     []Port{{Port: 80, TargetPort: "8080", Protocol: "TCP"}},
+						},
+					},
+					{
+						Path:     "/api",
+						PathType: "Prefix",
+						Filters:  filters,
+						Weight:   &weight1,
+						Service:  &Service{Name: "service-canary", Namespace: "default", Type: "TraefikService"},
+					},
+				},
+			},
+		},
+	}
+
+	route, err := NewResolver(client, "default").ResolveIngressRoute("ingressroute-foo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(route, expected) {
+		t.Errorf("Returned route was incorrect,\ngot:\n%+v\nwant:\n%+v", route, expected)
+	}
+}
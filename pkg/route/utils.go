@@ -0,0 +1,95 @@
+package route
+
+import (
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// PortToString returns a string port
+func PortToString(portType intstr.Type, strVal string, intVal int32) (portString string) {
+	switch portType {
+	case 0:
+		portString = strconv.FormatInt(int64(intVal), 10)
+	case 1:
+		portString = strVal
+	}
+
+	return
+}
+
+// IngressBackendServiceName returns the service name of an ingress backend
+func IngressBackendServiceName(backend networkingv1.IngressBackend) string {
+	if backend.Service == nil {
+		return ""
+	}
+
+	return backend.Service.Name
+}
+
+// IngressBackendResourceToString returns the apiGroup/kind/name reference of an ingress resource backend.
+// Resources of the core group are returned as kind/name
+func IngressBackendResourceToString(backend networkingv1.IngressBackend) string {
+	if backend.Resource == nil {
+		return ""
+	}
+
+	reference := backend.Resource.Kind + "/" + backend.Resource.Name
+
+	if backend.Resource.APIGroup != nil && *backend.Resource.APIGroup != "" {
+		reference = *backend.Resource.APIGroup + "/" + reference
+	}
+
+	return reference
+}
+
+// IngressBackendPortToString returns a string service port of an ingress backend
+func IngressBackendPortToString(backend networkingv1.IngressBackend) string {
+	if backend.Service == nil {
+		return ""
+	}
+
+	if backend.Service.Port.Name != "" {
+		return backend.Service.Port.Name
+	}
+
+	return strconv.FormatInt(int64(backend.Service.Port.Number), 10)
+}
+
+// IngressBackendToString returns an ingress backend in the "service:port" format,
+// or the reference of its resource for resource backends
+func IngressBackendToString(backend networkingv1.IngressBackend) string {
+	if backend.Service == nil {
+		return IngressBackendResourceToString(backend)
+	}
+
+	return backend.Service.Name + ":" + IngressBackendPortToString(backend)
+}
+
+// IngressClassName returns the ingress class set in the ingress spec
+// or, for older ingresses, in the kubernetes.io/ingress.class annotation
+func IngressClassName(ingress *networkingv1.Ingress) string {
+	if ingress.Spec.IngressClassName != nil {
+		return *ingress.Spec.IngressClassName
+	}
+
+	return ingress.Annotations[AnnotationIngressClass]
+}
+
+// ServiceTypeToString returns a string service type
+func ServiceTypeToString(serviceType v1.ServiceType) (serviceTypeString string) {
+	switch serviceType {
+	case v1.ServiceTypeLoadBalancer:
+		serviceTypeString = "LoadBalancer"
+	case v1.ServiceTypeNodePort:
+		serviceTypeString = "NodePort"
+	case v1.ServiceTypeClusterIP:
+		serviceTypeString = "ClusterIP"
+	case v1.ServiceTypeExternalName:
+		serviceTypeString = "ExternalName"
+	}
+
+	return
+}